@@ -2,51 +2,255 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	_ "time/tzdata"
 
 	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/billing"
+	"github/guiferpa/bank/domain/customer"
+	"github/guiferpa/bank/domain/fraud"
+	"github/guiferpa/bank/domain/health"
 	logd "github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/ratelimit"
+	"github/guiferpa/bank/domain/schedule"
+	"github/guiferpa/bank/domain/statement"
 	"github/guiferpa/bank/handler/http/api"
+	"github/guiferpa/bank/infra/auth/jwt"
+	"github/guiferpa/bank/infra/clock"
 	"github/guiferpa/bank/infra/logger/log"
+	"github/guiferpa/bank/infra/metrics/prometheus"
+	"github/guiferpa/bank/infra/rules/file"
+	"github/guiferpa/bank/infra/storage/memory"
 	"github/guiferpa/bank/infra/storage/postgres"
+	"github/guiferpa/bank/infra/trace/otel"
+	"github/guiferpa/bank/pkg/config"
 )
 
+// Rules are optional, without fraud.rules_file every transaction goes straight to storage.
+func loadTransactionEvaluator(cfg config.FraudConfig, storage *postgres.PostgresStorage) (account.TransactionEvaluator, error) {
+	if cfg.RulesFile == "" {
+		return nil, nil
+	}
+
+	rules, err := file.Load(cfg.RulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var counters fraud.CounterRepository = storage
+	if cfg.CounterStore == "memory" {
+		counters = memory.NewCounterStorage()
+	}
+
+	return fraud.NewEvaluator(rules, counters, clock.NewSystemClock())
+}
+
 func main() {
+	run(os.Args[1:])
+}
+
+func run(args []string) {
+	cfg, flags, err := config.Load(args, os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	if flags.PrintConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if flags.PrintConfig {
+		return
+	}
+
 	value := logd.LoggerContext{
 		RequestID: "",
 	}
 	ctx := context.WithValue(context.Background(), logd.LoggerContextKey, &value)
 
-	logger := log.NewLogger()
-	storage, err := postgres.NewStorage(postgres.NewStorageOptions{
-		Host:         os.Getenv("DATABASE_HOST"),
-		User:         os.Getenv("DATABASE_USER"),
-		Password:     os.Getenv("DATABASE_PASSWORD"),
-		DatabaseName: os.Getenv("DATABASE_NAME"),
-		Port:         os.Getenv("DATABASE_PORT"),
-		Logger:       logger,
-	})
+	logger, err := log.NewLogger(log.NewLoggerOptions{Level: cfg.Log.Level})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	metrics := prometheus.NewMetrics()
+	exporter, err := otel.NewExporter(ctx, cfg.Tracing.Exporter, os.Stdout)
+	if err != nil {
+		logger.Error(ctx, err.Error())
+		return
+	}
+	tp := otel.NewTracerProvider("bank-api", exporter)
+	defer tp.Shutdown(ctx)
+	tracer := otel.NewTracer(tp, "github/guiferpa/bank/domain")
+	var verifier auth.TokenVerifier
+	if cfg.Auth.Enabled && cfg.Auth.JWKSFile != "" {
+		v, err := jwt.NewVerifier(jwt.NewVerifierOptions{JWKSFile: cfg.Auth.JWKSFile, Issuer: cfg.Auth.JWTIssuer, Audience: cfg.Auth.JWTAudience})
+		if err != nil {
+			logger.Error(ctx, err.Error())
+			return
+		}
+		verifier = v
+	}
+	rateLimitRules, err := api.ParseRateLimitRules(cfg.RateLimit.Rules)
+	if err != nil {
+		logger.Error(ctx, err.Error())
+		return
+	}
+	if !cfg.Auth.Enabled {
+		logger.Warn(ctx, "authentication is disabled, the API is open to anyone reaching it")
+	}
+	if cfg.Database.ApplicationName == "" {
+		cfg.Database.ApplicationName = "bank-api"
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.Port))
 	if err != nil {
 		logger.Error(ctx, err.Error())
 		return
 	}
-	service := account.NewUseCaseService(storage, logger)
-	handler := api.NewHTTPHandler(service, logger)
+
+	logger.Info(ctx, fmt.Sprintf("API's running at port %v", cfg.Server.Port))
+
+	// Probes are answered while the database is being connected, the API replaces them once it's ready.
+	bootstrapper := postgres.NewBootstrapper(postgres.BootstrapOptions{
+		Timeout:        cfg.Database.ConnectTimeout,
+		InitialBackoff: cfg.Database.ConnectBackoff,
+		MaxBackoff:     cfg.Database.ConnectMaxBackoff,
+		Logger:         logger,
+	})
+	root := newSwapHandler(newStartupHandler(health.NewUseCaseService([]health.HealthChecker{bootstrapper}, logger)))
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, newServer(root, cfg.Server), ln, cfg.Server.ShutdownTimeout)
+	}()
+	abort := func(err error) {
+		logger.Error(ctx, err.Error())
+		stop()
+		<-served
+	}
+
+	storage, err := bootstrapper.Connect(ctx, postgres.NewStorageOptions{
+		Host:                 cfg.Database.Host,
+		User:                 cfg.Database.User,
+		Password:             cfg.Database.Password,
+		DatabaseName:         cfg.Database.Name,
+		Port:                 strconv.Itoa(cfg.Database.Port),
+		SSLMode:              cfg.Database.SSLMode,
+		SSLRootCert:          cfg.Database.SSLRootCert,
+		SSLCert:              cfg.Database.SSLCert,
+		SSLKey:               cfg.Database.SSLKey,
+		ApplicationName:      cfg.Database.ApplicationName,
+		StatementTimeout:     cfg.Database.StatementTimeout,
+		DSN:                  cfg.Database.DSN,
+		MaxOpenConns:         cfg.Database.MaxOpenConns,
+		MaxIdleConns:         cfg.Database.MaxIdleConns,
+		ConnMaxLifetime:      cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime:      cfg.Database.ConnMaxIdleTime,
+		ReplicaDSNs:          cfg.Database.ReplicaDSNs,
+		ReplicaCheckInterval: cfg.Database.ReplicaCheckInterval,
+		Logger:               logger,
+		Metrics:              metrics,
+		TracerProvider:       tp,
+	})
+	if err != nil {
+		abort(err)
+		return
+	}
+	db, err := storage.DB()
+	if err != nil {
+		abort(err)
+		return
+	}
+	if err := metrics.RegisterDB(db, cfg.Database.Name); err != nil {
+		abort(err)
+		return
+	}
+	evaluator, err := loadTransactionEvaluator(cfg.Fraud, storage)
+	if err != nil {
+		abort(err)
+		return
+	}
+	window := account.EventDateWindow{MaxBackdate: cfg.EventDate.MaxBackdate, MaxFuture: cfg.EventDate.MaxFuture}
+	service := account.NewTracedUseCase(account.NewInstrumentedUseCase(account.NewUseCaseService(storage, evaluator, window, clock.NewSystemClock(), logger), metrics), tracer)
+	customerService := customer.NewTracedUseCase(customer.NewUseCaseService(storage, service, logger), tracer)
+	billingService := billing.NewTracedUseCase(billing.NewUseCaseService(storage, logger), tracer)
+	scheduleService := schedule.NewTracedUseCase(schedule.NewUseCaseService(storage, service, clock.NewSystemClock(), logger), tracer)
+	statementService := statement.NewTracedUseCase(statement.NewUseCaseService(storage, clock.NewSystemClock(), logger), tracer)
+	healthService := health.NewUseCaseService([]health.HealthChecker{storage}, logger)
+	var authService auth.UseCase
+	if cfg.Auth.Enabled {
+		signing := auth.SigningOptions{Window: cfg.Auth.SignatureWindow, Nonces: memory.NewNonceStorage()}
+		authService = auth.NewTracedUseCase(auth.NewUseCaseService(storage, verifier, signing, clock.NewSystemClock(), logger), tracer)
+	}
+	var rateLimitService ratelimit.UseCase
+	if len(rateLimitRules) > 0 {
+		rateLimitService = ratelimit.NewUseCaseService(memory.NewBucketStorage(), clock.NewSystemClock(), logger)
+	}
 
 	// Reason which make me to do seed on my own hand: https://github.com/go-gorm/gorm/issues/5339
 	if err := storage.RunSeed(); err != nil {
-		logger.Error(ctx, err.Error())
+		abort(err)
 		return
 	}
 	logger.Info(ctx, "seed done successful")
 
-	port := os.Getenv("PORT")
+	root.Swap(api.NewHTTPHandler(api.NewHTTPHandlerOptions{
+		AccountUseCase:   service,
+		CustomerUseCase:  customerService,
+		BillingUseCase:   billingService,
+		ScheduleUseCase:  scheduleService,
+		StatementUseCase: statementService,
+		HealthUseCase:    healthService,
+		AuthUseCase:      authService,
+		RateLimitUseCase: rateLimitService,
+		RateLimitRules:   rateLimitRules,
+		BatchMaxItems:    cfg.Batch.MaxItems,
+		Metrics:          metrics,
+		TracerProvider:   tp,
+		MetricsHandler:   metrics.Handler(),
+		Logger:           logger,
+	}))
+
+	var workers sync.WaitGroup
+	if cfg.Scheduler.Interval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			runScheduler(ctx, scheduleService, cfg.Scheduler.Interval, logger)
+		}()
+	}
+
+	if err := <-served; err != nil {
+		logger.Error(ctx, fmt.Sprintf("server stopped: %s", err))
+	}
+	logger.Info(ctx, "server stopped, waiting for background workers")
 
-	logger.Info(ctx, fmt.Sprintf("API's running at port %v", port))
+	stop()
+	workers.Wait()
 
-	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), handler); err != nil {
+	if err := storage.Close(); err != nil {
 		logger.Error(ctx, err.Error())
 	}
+	logger.Info(ctx, "shutdown done")
 }
@@ -5,14 +5,70 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/handler/http/api"
+	"github/guiferpa/bank/infra/clock"
+	"github/guiferpa/bank/infra/storage/postgres"
 	"github/guiferpa/bank/pkg/docker"
+	"github/guiferpa/bank/pkg/signing"
 )
 
+func waitReady(url string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("API not ready after %s", timeout)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+type apiKeyTransport struct {
+	key string
+}
+
+func (akt apiKeyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set(api.APIKeyHeader, akt.key)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// createAPIKey goes straight to the database the API is using, as bankctl create-api-key does.
+func createAPIKey(opts auth.CreateAPIKeyOptions) (auth.APIKey, string, error) {
+	storage, err := postgres.NewStorage(postgres.NewStorageOptions{
+		Host:          "localhost",
+		User:          "postgres",
+		Password:      "Pa$$w0rd",
+		DatabaseName:  "api",
+		Port:          "5431",
+		SkipMigration: true,
+	})
+	if err != nil {
+		return auth.APIKey{}, "", err
+	}
+	defer storage.Close()
+
+	return auth.NewUseCaseService(storage, nil, auth.SigningOptions{}, clock.NewSystemClock(), nil).CreateAPIKey(context.Background(), opts)
+}
+
 func TestIntegrationForAPI(t *testing.T) {
 	client, err := docker.NewEnvironment()
 	if err != nil {
@@ -43,16 +99,70 @@ func TestIntegrationForAPI(t *testing.T) {
 	t.Setenv("DATABASE_PASSWORD", "Pa$$w0rd")
 	t.Setenv("PORT", "8080")
 
-	time.Sleep(4 * time.Second)
+	// The API keeps trying the database while the container starts, it's ready once the seed is done.
+	go run(nil)
 
-	go main()
+	if err := waitReady("http://localhost:8080/readyz", time.Minute); err != nil {
+		t.Error(err)
+		return
+	}
 
-	time.Sleep(2 * time.Second)
+	_, key, err := createAPIKey(auth.CreateAPIKeyOptions{Name: "integration", Scopes: auth.Scopes})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	_, partnerKey, err := createAPIKey(auth.CreateAPIKeyOptions{Name: "partner", Scopes: []auth.Scope{auth.ReadAccountsScope}, Tenant: "acme"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	signingKey, _, err := createAPIKey(auth.CreateAPIKeyOptions{Name: "signing", Scopes: auth.Scopes, Signing: true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// Every request of the specs goes with the key, but the ones made by a client of their own.
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = apiKeyTransport{key}
+	defer func() {
+		http.DefaultClient.Transport = transport
+	}()
 
 	suite := []struct {
 		Describe string
 		Spec     func(t *testing.T)
 	}{
+		{
+			Describe: "Rejected request without credentials",
+			Spec: func(t *testing.T) {
+				resp, err := (&http.Client{}).Get("http://localhost:8080/api/v1/accounts/1")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := resp.StatusCode, http.StatusUnauthorized; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				var body account.HandlerError
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := body.Code, account.HandlerUnauthorizedErrorCode; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
 		{
 			Describe: "Created account successful",
 			Spec: func(t *testing.T) {
@@ -81,6 +191,79 @@ func TestIntegrationForAPI(t *testing.T) {
 				}
 			},
 		},
+		{
+			Describe: "Forbidden account created by the bank to a partner tenant",
+			Spec: func(t *testing.T) {
+				resp, err := (&http.Client{Transport: apiKeyTransport{partnerKey}}).Get("http://localhost:8080/api/v1/accounts/1")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := resp.StatusCode, http.StatusForbidden; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				var body account.HandlerError
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := body.Code, account.DomainAccountForbiddenErrorCode; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Accepted signed request",
+			Spec: func(t *testing.T) {
+				client := &http.Client{Transport: signing.Transport{KeyID: signingKey.Prefix, Secret: signingKey.SigningSecret}}
+				resp, err := client.Get("http://localhost:8080/api/v1/accounts/1")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := resp.StatusCode, http.StatusOK; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Rejected replayed signed request",
+			Spec: func(t *testing.T) {
+				req, err := http.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/accounts/1", nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if err := signing.Sign(req, signingKey.Prefix, signingKey.SigningSecret, time.Now()); err != nil {
+					t.Error(err)
+					return
+				}
+
+				for _, expected := range []int{http.StatusOK, http.StatusUnauthorized} {
+					resp, err := (&http.Client{}).Do(req)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp.Body.Close()
+
+					if got := resp.StatusCode; got != expected {
+						t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+						return
+					}
+				}
+			},
+		},
 		{
 			Describe: "Got duplicated account error when create account successful",
 			Spec: func(t *testing.T) {
@@ -131,7 +314,7 @@ func TestIntegrationForAPI(t *testing.T) {
 				}
 				defer resp.Body.Close()
 
-				if got, expected := string(data), "{\"code\":\"handler.3\",\"message\":\"missing request body\"}\n"; got != expected {
+				if got, expected := string(data), "{\"type\":\"https://github.com/guiferpa/bank/blob/main/docs/errors.md#handler.3\",\"title\":\"Bad request\",\"status\":400,\"detail\":\"missing request body\",\"instance\":\"/api/v1/accounts\",\"code\":\"handler.3\"}\n"; got != expected {
 					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
 					return
 				}
@@ -159,7 +342,7 @@ func TestIntegrationForAPI(t *testing.T) {
 				}
 				defer resp.Body.Close()
 
-				if got, expected := string(data), "{\"code\":\"handler.2\",\"message\":\"field document_number cannot be empty\",\"field\":\"document_number\"}\n"; got != expected {
+				if got, expected := string(data), "{\"type\":\"https://github.com/guiferpa/bank/blob/main/docs/errors.md#handler.2\",\"title\":\"Invalid request payload\",\"status\":422,\"detail\":\"field document_number cannot be empty\",\"instance\":\"/api/v1/accounts\",\"code\":\"handler.2\",\"errors\":[{\"field\":\"document_number\",\"detail\":\"field document_number cannot be empty\"}]}\n"; got != expected {
 					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
 					return
 				}
@@ -186,7 +369,7 @@ func TestIntegrationForAPI(t *testing.T) {
 				}
 				defer resp.Body.Close()
 
-				if got, expected := string(data), "{\"id\":1,\"document_number\":\"10\"}\n"; got != expected {
+				if got, expected := string(data), "{\"id\":1,\"document_number\":\"10\",\"time_zone\":\"UTC\"}\n"; got != expected {
 					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
 					return
 				}
@@ -325,12 +508,503 @@ func TestIntegrationForAPI(t *testing.T) {
 				}
 				defer resp.Body.Close()
 
-				if got, expected := string(data), "{\"code\":\"handler.2\",\"message\":\"this value can't be zero\",\"field\":\"amount\"}\n"; got != expected {
+				if got, expected := string(data), "{\"type\":\"https://github.com/guiferpa/bank/blob/main/docs/errors.md#handler.2\",\"title\":\"Invalid request payload\",\"status\":422,\"detail\":\"this value can't be zero\",\"instance\":\"/api/v1/accounts/transaction\",\"code\":\"handler.2\",\"errors\":[{\"field\":\"amount\",\"detail\":\"this value can't be zero\"}]}\n"; got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got invalid event date when create account transaction too far in the past",
+			Spec: func(t *testing.T) {
+				body := bytes.NewBufferString(`{"account_id": 1, "operation_type_id": 1, "amount": -10.0, "event_date": "2020-01-01T10:00:00-03:00"}`)
+				resp, err := http.Post("http://localhost:8080/api/v1/accounts/transaction", "application/json; chartset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusUnprocessableEntity; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := string(data), "{\"code\":\"domain.8\",\"message\":\"event date can't be more than 168h0m0s in the past\"}\n"; got != expected {
 					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
 					return
 				}
 			},
 		},
+		{
+			Describe: "Listed account transactions by merchant's MCC successful",
+			Spec: func(t *testing.T) {
+				body := bytes.NewBufferString(`{"account_id": 1, "operation_type_id": 1, "amount": -12.5, "merchant": {"name": "Padaria Real", "mcc": "5462"}, "metadata": {"order_id": "42"}}`)
+				resp, err := http.Post("http://localhost:8080/api/v1/accounts/transaction", "application/json; chartset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusCreated; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				resp, err = http.Get("http://localhost:8080/api/v1/accounts/1/transactions?mcc=5462")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusOK; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				var txs []map[string]interface{}
+				if err := json.NewDecoder(resp.Body).Decode(&txs); err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := len(txs), 1; got != expected {
+					t.Errorf("unexpected number of transactions, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := txs[0]["merchant"].(map[string]interface{})["name"], "Padaria Real"; got != expected {
+					t.Errorf("unexpected merchant name, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got invalid metadata when create account transaction",
+			Spec: func(t *testing.T) {
+				body := bytes.NewBufferString(`{"account_id": 1, "operation_type_id": 1, "amount": -12.5, "metadata": {"Order ID": "42"}}`)
+				resp, err := http.Post("http://localhost:8080/api/v1/accounts/transaction", "application/json; chartset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusUnprocessableEntity; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Created transactions batch with per item results successful",
+			Spec: func(t *testing.T) {
+				body := bytes.NewBufferString(`{"items": [{"account_id": 1, "operation_type_id": 1, "amount": -1.5}, {"account_id": 1398, "operation_type_id": 1, "amount": -1.5}]}`)
+				resp, err := http.Post("http://localhost:8080/api/v1/transactions:batch", "application/json; chartset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusMultiStatus; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				var result struct {
+					Items []struct {
+						Status string `json:"status"`
+					} `json:"items"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := result.Items[1].Status, "rejected"; got != expected {
+					t.Errorf("unexpected item status, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got whole transactions batch aborted when atomic",
+			Spec: func(t *testing.T) {
+				body := bytes.NewBufferString(`{"items": [{"account_id": 1, "operation_type_id": 1, "amount": -1.5}, {"account_id": 1398, "operation_type_id": 1, "amount": -1.5}]}`)
+				resp, err := http.Post("http://localhost:8080/api/v1/transactions:batch?atomic=true", "application/json; chartset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusUnprocessableEntity; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := string(data), "{\"items\":[{\"index\":0,\"status\":\"aborted\"},{\"index\":1,\"status\":\"rejected\",\"error\":{\"code\":\"infra.2\",\"message\":\"account not found\"}}]}\n"; got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Created customer successful",
+			Spec: func(t *testing.T) {
+				body := bytes.NewBufferString(`{"document_number": "20", "name": "Jane Doe", "birth_date": "1990-01-31"}`)
+				resp, err := http.Post("http://localhost:8080/api/v1/customers", "application/json; charset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusCreated; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := string(data), "{\"id\":2,\"document_number\":\"20\",\"name\":\"Jane Doe\",\"birth_date\":\"1990-01-31\",\"address\":{}}\n"; got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Opened account for customer successful",
+			Spec: func(t *testing.T) {
+				for i, id := range []uint{2, 3} {
+					resp, err := http.Post("http://localhost:8080/api/v1/customers/2/accounts", "application/json; charset=utf-8", nil)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+
+					if got, expected := resp.StatusCode, http.StatusCreated; got != expected {
+						t.Errorf("unexpected response status code at %d, got: %v, expected: %v", i, got, expected)
+						return
+					}
+
+					data, err := ioutil.ReadAll(resp.Body)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp.Body.Close()
+
+					if got, expected := string(data), fmt.Sprintf("{\"id\":%d,\"customer_id\":2,\"document_number\":\"20\"}\n", id); got != expected {
+						t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+						return
+					}
+				}
+			},
+		},
+		{
+			Describe: "Got customer not found when open account",
+			Spec: func(t *testing.T) {
+				resp, err := http.Post("http://localhost:8080/api/v1/customers/1398/accounts", "application/json; charset=utf-8", nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusNotFound; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := string(data), "{\"code\":\"infra.3\",\"message\":\"customer not found\"}\n"; got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got account statement as CSV successful",
+			Spec: func(t *testing.T) {
+				req, err := http.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/accounts/1/statement", nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				req.Header.Set("Accept", "text/csv")
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusOK; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := resp.Header.Get("Content-Type"), "text/csv; charset=utf-8"; got != expected {
+					t.Errorf("unexpected response content type, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := strings.SplitN(string(data), "\n", 2)[0], "date,transaction_id,operation_type_id,description,amount,balance"; got != expected {
+					t.Errorf("unexpected response header row, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got account statement not acceptable",
+			Spec: func(t *testing.T) {
+				req, err := http.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/accounts/1/statement", nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				req.Header.Set("Accept", "application/pdf")
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusNotAcceptable; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got ready with every check up",
+			Spec: func(t *testing.T) {
+				resp, err := http.Get("http://localhost:8080/readyz")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusOK; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				var body struct {
+					Status string `json:"status"`
+					Checks []struct {
+						Name   string `json:"name"`
+						Status string `json:"status"`
+					} `json:"checks"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := body.Status, "up"; got != expected {
+					t.Errorf("unexpected readiness status, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := len(body.Checks), 2; got != expected {
+					t.Errorf("unexpected number of checks, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got metrics of the requests served",
+			Spec: func(t *testing.T) {
+				resp, err := http.Get("http://localhost:8080/metrics")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusOK; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				for _, name := range []string{`bank_http_requests_total{method="POST",route="/api/v1/accounts/",status="201"}`, "bank_accounts_created_total", "go_sql_open_connections"} {
+					if !strings.Contains(string(data), name) {
+						t.Errorf("expected %s in metrics", name)
+						return
+					}
+				}
+			},
+		},
+		{
+			Describe: "Listed account invoices successful",
+			Spec: func(t *testing.T) {
+				resp, err := http.Get("http://localhost:8080/api/v1/accounts/1/invoices")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusOK; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := string(data), "[]\n"; got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got invoice not found",
+			Spec: func(t *testing.T) {
+				resp, err := http.Get("http://localhost:8080/api/v1/invoices/1398")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusNotFound; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if got, expected := string(data), "{\"code\":\"infra.4\",\"message\":\"invoice not found\"}\n"; got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Created scheduled transaction successful",
+			Spec: func(t *testing.T) {
+				startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+				body := bytes.NewBufferString(fmt.Sprintf(`{"operation_type_id": 4, "amount": 50.0, "start_at": "%s", "recurrence": {"frequency": "monthly"}, "max_occurrences": 12}`, startAt))
+				resp, err := http.Post("http://localhost:8080/api/v1/accounts/1/scheduled-transactions", "application/json; chartset=utf-8", body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusCreated; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+
+				expected := fmt.Sprintf("{\"id\":1,\"account_id\":1,\"operation_type_id\":4,\"amount\":50,\"start_at\":\"%s\",\"recurrence\":{\"frequency\":\"monthly\",\"interval\":1},\"max_occurrences\":12,\"occurrences\":0,\"next_run_at\":\"%s\",\"status\":\"active\"}\n", startAt, startAt)
+				if got := string(data); got != expected {
+					t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Canceled scheduled transaction successful",
+			Spec: func(t *testing.T) {
+				req, err := http.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/scheduled-transactions/1", nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusNoContent; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				resp, err = http.DefaultClient.Do(req)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusConflict; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got scheduled transaction not found",
+			Spec: func(t *testing.T) {
+				resp, err := http.Get("http://localhost:8080/api/v1/scheduled-transactions/1398")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := resp.StatusCode, http.StatusNotFound; got != expected {
+					t.Errorf("unexpected response status code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
 	}
 
 	for _, s := range suite {
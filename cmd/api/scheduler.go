@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github/guiferpa/bank/domain/consistency"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/schedule"
+)
+
+// detached keeps the values of its context but is never done.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+// runScheduler materializes due scheduled transactions at every interval until ctx is done, a run already
+// started when it's done is finished first. Runs write, so they read from the primary. It's safe to run it in
+// every replica, occurrences are created exactly once no matter how many schedulers run them.
+func runScheduler(ctx context.Context, usecase schedule.UseCase, interval time.Duration, logger log.LoggerRepository) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := usecase.RunDueScheduledTransactions(consistency.WithPrimary(detached{ctx}), schedule.RunDueOptions{})
+		for _, f := range result.Failures {
+			logger.Warn(ctx, fmt.Sprintf("scheduled transaction %d occurrence %d failed: %s", f.ScheduleID, f.Occurrence, f.Err))
+		}
+
+		if err != nil {
+			logger.Error(ctx, err.Error())
+			continue
+		}
+
+		if result.Created > 0 || result.Skipped > 0 {
+			logger.Info(ctx, fmt.Sprintf("scheduled transactions run: %d created, %d already created", result.Created, result.Skipped))
+		}
+	}
+}
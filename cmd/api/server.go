@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github/guiferpa/bank/domain/health"
+	"github/guiferpa/bank/handler/http/api"
+	"github/guiferpa/bank/pkg/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func newServer(handler http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve runs server on ln until ctx is done, then stops accepting connections and waits up to timeout
+// for the in-flight requests to finish.
+func serve(ctx context.Context, server *http.Server, ln net.Listener, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(sctx); err != nil {
+		server.Close()
+		return err
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// startupHandler answers probes while the API is connecting to the database, readiness and every other route
+// are 503 with the report of how it's going.
+func newStartupHandler(usecase health.UseCase) http.Handler {
+	readyz := api.Readyz(usecase, api.DefaultReadinessTimeout)
+
+	router := chi.NewRouter()
+	router.Get("/healthz", api.Healthz)
+	router.Get("/readyz", readyz)
+	router.NotFound(readyz)
+	router.MethodNotAllowed(readyz)
+
+	return router
+}
+
+// swapHandler serves one handler until Swap replaces it, so the listener is up before the API is ready.
+type swapHandler struct {
+	mu      sync.RWMutex
+	handler http.Handler
+}
+
+func (s *swapHandler) Swap(handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handler = handler
+}
+
+func (s *swapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	handler := s.handler
+	s.mu.RUnlock()
+
+	handler.ServeHTTP(w, r)
+}
+
+func newSwapHandler(handler http.Handler) *swapHandler {
+	return &swapHandler{handler: handler}
+}
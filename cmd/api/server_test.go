@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/health"
+	"github/guiferpa/bank/pkg/config"
+)
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	suite := []struct {
+		Name     string
+		Hold     time.Duration
+		Timeout  time.Duration
+		Finished bool
+	}{
+		{"in-flight request finishes within the deadline", 200 * time.Millisecond, time.Second, true},
+		{"in-flight request outlives the deadline", time.Second, 100 * time.Millisecond, false},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			started := make(chan struct{})
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				time.Sleep(s.Hold)
+				io.WriteString(w, "done")
+			})
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, stop := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() {
+				served <- serve(ctx, newServer(handler, config.ServerConfig{}), ln, s.Timeout)
+			}()
+
+			type response struct {
+				body string
+				err  error
+			}
+			responses := make(chan response, 1)
+			go func() {
+				resp, err := http.Get("http://" + ln.Addr().String())
+				if err != nil {
+					responses <- response{err: err}
+					return
+				}
+				defer resp.Body.Close()
+
+				body, err := io.ReadAll(resp.Body)
+				responses <- response{string(body), err}
+			}()
+
+			<-started
+			stop()
+
+			err = <-served
+			if got, expected := err == nil, s.Finished; got != expected {
+				t.Errorf("unexpected serve result, got: %v, expected finished: %v", err, expected)
+				return
+			}
+
+			if !s.Finished {
+				return
+			}
+
+			resp := <-responses
+			if resp.err != nil {
+				t.Error(resp.err)
+				return
+			}
+
+			if got, expected := resp.body, "done"; got != expected {
+				t.Errorf("unexpected response body, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if _, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+				t.Error("expected connections refused after shutdown")
+				return
+			}
+		})
+	}
+}
+
+type MockHealthUseCase struct {
+	Report health.Report
+}
+
+func (m MockHealthUseCase) Readiness(ctx context.Context) health.Report {
+	return m.Report
+}
+
+func TestSwapHandlerServesStartupUntilReady(t *testing.T) {
+	handler := newSwapHandler(newStartupHandler(MockHealthUseCase{Report: health.Report{Status: health.DownStatus}}))
+
+	suite := []struct {
+		Path     string
+		Expected int
+	}{
+		{"/healthz", http.StatusOK},
+		{"/readyz", http.StatusServiceUnavailable},
+		{"/api/v1/accounts/1", http.StatusServiceUnavailable},
+	}
+
+	for _, s := range suite {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, s.Path, nil))
+		if got, expected := w.Code, s.Expected; got != expected {
+			t.Errorf("unexpected status code for %s while starting, got: %v, expected: %v", s.Path, got, expected)
+			return
+		}
+	}
+
+	handler.Swap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if got, expected := w.Code, http.StatusTeapot; got != expected {
+		t.Errorf("unexpected status code after swap, got: %v, expected: %v", got, expected)
+		return
+	}
+}
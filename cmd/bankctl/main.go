@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/billing"
+	"github/guiferpa/bank/domain/charges"
+	logd "github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/handler/cli"
+	"github/guiferpa/bank/infra/clock"
+	"github/guiferpa/bank/infra/logger/log"
+	"github/guiferpa/bank/infra/storage/dryrun"
+	"github/guiferpa/bank/infra/storage/postgres"
+	"github/guiferpa/bank/pkg/config"
+)
+
+func main() {
+	// Subcommands own the flags, the configuration comes from CONFIG_FILE and the var environments.
+	cfg, _, err := config.Load(nil, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	value := logd.LoggerContext{
+		RequestID: "",
+	}
+	ctx := context.WithValue(context.Background(), logd.LoggerContextKey, &value)
+
+	logger, err := log.NewLogger(log.NewLoggerOptions{Level: cfg.Log.Level})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if cfg.Database.ApplicationName == "" {
+		cfg.Database.ApplicationName = "bankctl"
+	}
+
+	bootstrapper := postgres.NewBootstrapper(postgres.BootstrapOptions{
+		Timeout:        cfg.Database.ConnectTimeout,
+		InitialBackoff: cfg.Database.ConnectBackoff,
+		MaxBackoff:     cfg.Database.ConnectMaxBackoff,
+		Logger:         logger,
+	})
+	storage, err := bootstrapper.Connect(ctx, postgres.NewStorageOptions{
+		Host:             cfg.Database.Host,
+		User:             cfg.Database.User,
+		Password:         cfg.Database.Password,
+		DatabaseName:     cfg.Database.Name,
+		Port:             strconv.Itoa(cfg.Database.Port),
+		SSLMode:          cfg.Database.SSLMode,
+		SSLRootCert:      cfg.Database.SSLRootCert,
+		SSLCert:          cfg.Database.SSLCert,
+		SSLKey:           cfg.Database.SSLKey,
+		ApplicationName:  cfg.Database.ApplicationName,
+		StatementTimeout: cfg.Database.StatementTimeout,
+		DSN:              cfg.Database.DSN,
+		MaxOpenConns:     cfg.Database.MaxOpenConns,
+		MaxIdleConns:     cfg.Database.MaxIdleConns,
+		ConnMaxLifetime:  cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.Database.ConnMaxIdleTime,
+		Logger:           logger,
+		// The API migrates on start, here it only happens through the migrate command. Replicas are left out,
+		// the jobs read what they write.
+		SkipMigration: true,
+	})
+	if err != nil {
+		logger.Error(ctx, err.Error())
+		os.Exit(1)
+	}
+	billingService := billing.NewUseCaseService(storage, logger)
+
+	chargesConfig, err := loadChargesConfig()
+	if err != nil {
+		logger.Error(ctx, err.Error())
+		os.Exit(1)
+	}
+	chargesService := charges.NewUseCaseService(storage, chargesConfig, clock.NewSystemClock(), logger)
+
+	// The CLI also loads historical data, so there's no event date window nor fraud rules.
+	accountService := account.NewUseCaseService(storage, nil, account.EventDateWindow{}, clock.NewSystemClock(), logger)
+	dryRunAccountService := account.NewUseCaseService(dryrun.NewStorage(storage), nil, account.EventDateWindow{}, clock.NewSystemClock(), logger)
+
+	authService := auth.NewUseCaseService(storage, nil, auth.SigningOptions{}, clock.NewSystemClock(), logger)
+
+	commands := []cli.Command{
+		cli.CloseInvoices(billingService, os.Stdout),
+		cli.ApplyCharges(chargesService, os.Stdout),
+		cli.Import(accountService, dryRunAccountService, os.Stdout),
+		cli.CreateAccount(accountService, os.Stdout),
+		cli.GetAccount(accountService, os.Stdout),
+		cli.PostTransaction(accountService, os.Stdout),
+		cli.ListOperationTypes(accountService, os.Stdout),
+		cli.PrintBalances(accountService, os.Stdout),
+		cli.CreateAPIKey(authService, os.Stdout),
+		cli.ListAPIKeys(authService, os.Stdout),
+		cli.RevokeAPIKey(authService, os.Stdout),
+		cli.Migrate(storage, os.Stdout),
+		cli.Seed(storage, os.Stdout),
+	}
+
+	if err := cli.Run(ctx, os.Args[1:], commands...); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		var cerr *cli.UnknownCommandError
+		if errors.As(err, &cerr) {
+			cli.Usage(os.Stderr, "bankctl", commands...)
+		}
+
+		logger.Error(ctx, err.Error())
+		os.Exit(1)
+	}
+}
+
+func loadChargesConfig() (charges.Config, error) {
+	config := charges.DefaultConfig
+
+	vars := []struct {
+		Name  string
+		Value *int64
+	}{
+		{"CHARGES_MONTHLY_INTEREST_RATE_PPM", (*int64)(&config.MonthlyInterestRate)},
+		{"CHARGES_LATE_FEE", &config.LateFee},
+		{"CHARGES_IOF_RATE_PPM", (*int64)(&config.IOFRate)},
+	}
+	for _, v := range vars {
+		raw := os.Getenv(v.Name)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return config, fmt.Errorf("invalid value for %s: %w", v.Name, err)
+		}
+		*v.Value = value
+	}
+
+	return config, nil
+}
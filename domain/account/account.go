@@ -1,6 +1,49 @@
 package account
 
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/tenant"
+)
+
+const (
+	DefaultClosingDay = 1
+	DefaultDueDay     = 10
+
+	// Billing days stop at 28 so every month has them.
+	MaxBillingDay = 28
+
+	DefaultTimeZone = "UTC"
+)
+
+// Tenant is the partner which created the account, empty for the ones created by the bank.
 type Account struct {
 	ID             uint
+	CustomerID     uint
 	DocumentNumber string
+	ClosingDay     int
+	DueDay         int
+	TimeZone       string
+	Tenant         string
+	CreatedAt      time.Time
+}
+
+// Location falls back to UTC for accounts created before time zones were stored.
+func (acc Account) Location() *time.Location {
+	loc, err := time.LoadLocation(acc.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// Authorize fails when ctx is scoped to a tenant other than the one which created the account.
+func Authorize(ctx context.Context, acc Account) error {
+	if id, ok := tenant.FromContext(ctx); ok && acc.Tenant != id {
+		return NewDomainError(DomainAccountForbiddenErrorCode, "account belongs to another tenant")
+	}
+
+	return nil
 }
@@ -3,10 +3,15 @@ package account
 type ErrorCode string
 
 const (
-	HandlerUnknwonErrorCode        ErrorCode = "handler.1"
-	HandlerInvalidPayloadErrorCode ErrorCode = "handler.2"
-	HandlerBadRequestErrorCode     ErrorCode = "handler.3"
-	HandlerInvalidPathParam        ErrorCode = "handler.4"
+	HandlerUnknwonErrorCode         ErrorCode = "handler.1"
+	HandlerInvalidPayloadErrorCode  ErrorCode = "handler.2"
+	HandlerBadRequestErrorCode      ErrorCode = "handler.3"
+	HandlerInvalidPathParam         ErrorCode = "handler.4"
+	HandlerNotAcceptableErrorCode   ErrorCode = "handler.5"
+	HandlerUnauthorizedErrorCode    ErrorCode = "handler.6"
+	HandlerForbiddenErrorCode       ErrorCode = "handler.7"
+	HandlerTooManyRequestsErrorCode ErrorCode = "handler.8"
+	HandlerPayloadTooLargeErrorCode ErrorCode = "handler.9"
 )
 
 type HandlerError struct {
@@ -14,6 +19,10 @@ type HandlerError struct {
 	Message string    `json:"message"`
 }
 
+func (err *HandlerError) Error() string {
+	return err.Message
+}
+
 func NewHandlerError(errorCode ErrorCode, message string) *HandlerError {
 	return &HandlerError{errorCode, message}
 }
@@ -38,6 +47,10 @@ type HandlerInvalidParamError struct {
 	Param   string    `json:"parameter,omitempty"`
 }
 
+func (err *HandlerInvalidParamError) Error() string {
+	return err.Message
+}
+
 func NewHandlerInvalidParamError(errorCode ErrorCode, message, param string) *HandlerInvalidParamError {
 	return &HandlerInvalidParamError{errorCode, message, param}
 }
@@ -45,11 +58,26 @@ func NewHandlerInvalidParamError(errorCode ErrorCode, message, param string) *Ha
 const (
 	DomainAccountAlreadyExistsErrorCode     ErrorCode = "domain.1"
 	DomainOperationTypeDoesntExistErrorCode ErrorCode = "domain.2"
+	DomainCustomerAlreadyExistsErrorCode    ErrorCode = "domain.3"
+	DomainInvalidBillingDayErrorCode        ErrorCode = "domain.4"
+	DomainTransactionDeclinedErrorCode      ErrorCode = "domain.5"
+	DomainInvalidScheduleErrorCode          ErrorCode = "domain.6"
+	DomainScheduleNotActiveErrorCode        ErrorCode = "domain.7"
+	DomainInvalidEventDateErrorCode         ErrorCode = "domain.8"
+	DomainInvalidTimeZoneErrorCode          ErrorCode = "domain.9"
+	DomainInvalidDateRangeErrorCode         ErrorCode = "domain.10"
+	DomainInvalidMerchantErrorCode          ErrorCode = "domain.11"
+	DomainInvalidMetadataErrorCode          ErrorCode = "domain.12"
+	DomainInvalidCredentialsErrorCode       ErrorCode = "domain.13"
+	DomainInvalidAPIKeyErrorCode            ErrorCode = "domain.14"
+	DomainAccountForbiddenErrorCode         ErrorCode = "domain.15"
+	DomainCustomerForbiddenErrorCode        ErrorCode = "domain.16"
 )
 
 type DomainError struct {
 	Code    ErrorCode `json:"code"`
 	Message string    `json:"message"`
+	RuleID  string    `json:"rule_id,omitempty"`
 }
 
 func (err *DomainError) Error() string {
@@ -57,12 +85,19 @@ func (err *DomainError) Error() string {
 }
 
 func NewDomainError(errorCode ErrorCode, message string) *DomainError {
-	return &DomainError{errorCode, message}
+	return &DomainError{Code: errorCode, Message: message}
 }
 
 const (
-	InfraUnknownError             ErrorCode = "infra.1"
-	InfraAccountNotFoundErrorCode ErrorCode = "infra.2"
+	InfraUnknownError                   ErrorCode = "infra.1"
+	InfraAccountNotFoundErrorCode       ErrorCode = "infra.2"
+	InfraCustomerNotFoundErrorCode      ErrorCode = "infra.3"
+	InfraInvoiceNotFoundErrorCode       ErrorCode = "infra.4"
+	InfraTransactionDuplicatedErrorCode ErrorCode = "infra.5"
+	InfraScheduleNotFoundErrorCode      ErrorCode = "infra.6"
+	InfraScheduleOutdatedErrorCode      ErrorCode = "infra.7"
+	InfraTransactionNotFoundErrorCode   ErrorCode = "infra.8"
+	InfraAPIKeyNotFoundErrorCode        ErrorCode = "infra.9"
 )
 
 type InfraError struct {
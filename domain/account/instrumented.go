@@ -0,0 +1,61 @@
+package account
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/metrics"
+)
+
+// InstrumentedUseCase counts the accounts and transactions going through the wrapped use case.
+type InstrumentedUseCase struct {
+	UseCase
+	metrics metrics.MetricsRepository
+}
+
+func (iuc *InstrumentedUseCase) declined(err error) {
+	if cerr, ok := err.(*DomainError); ok && cerr.Code == DomainTransactionDeclinedErrorCode {
+		iuc.metrics.IncTransactionsDeclined(cerr.RuleID)
+	}
+}
+
+func (iuc *InstrumentedUseCase) CreateAccount(ctx context.Context, opts CreateAccountOptions) (uint, error) {
+	accountID, err := iuc.UseCase.CreateAccount(ctx, opts)
+	if err == nil {
+		iuc.metrics.IncAccountsCreated()
+	}
+
+	return accountID, err
+}
+
+func (iuc *InstrumentedUseCase) CreateTransaction(ctx context.Context, opts CreateTransactionOptions) (uint, error) {
+	transID, err := iuc.UseCase.CreateTransaction(ctx, opts)
+	if err != nil {
+		iuc.declined(err)
+		return transID, err
+	}
+	iuc.metrics.IncTransactionsCreated(opts.OperationTypeID)
+
+	return transID, nil
+}
+
+func (iuc *InstrumentedUseCase) CreateTransactions(ctx context.Context, opts CreateTransactionsOptions) ([]BatchItemResult, error) {
+	results, err := iuc.UseCase.CreateTransactions(ctx, opts)
+	if err != nil {
+		return results, err
+	}
+
+	for i, result := range results {
+		switch result.Status {
+		case CreatedBatchItemStatus:
+			iuc.metrics.IncTransactionsCreated(opts.Items[i].OperationTypeID)
+		case RejectedBatchItemStatus:
+			iuc.declined(result.Err)
+		}
+	}
+
+	return results, nil
+}
+
+func NewInstrumentedUseCase(usecase UseCase, metrics metrics.MetricsRepository) *InstrumentedUseCase {
+	return &InstrumentedUseCase{usecase, metrics}
+}
@@ -0,0 +1,81 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type MockMetricsRepository struct {
+	AccountsCreated     int
+	TransactionsCreated map[uint]int
+	Declines            map[string]int
+}
+
+func (mmr *MockMetricsRepository) ObserveHTTPRequest(method, route string, status int, elapsed time.Duration) {
+}
+
+func (mmr *MockMetricsRepository) IncAccountsCreated() {
+	mmr.AccountsCreated += 1
+}
+
+func (mmr *MockMetricsRepository) IncTransactionsCreated(operationTypeID uint) {
+	mmr.TransactionsCreated[operationTypeID] += 1
+}
+
+func (mmr *MockMetricsRepository) IncTransactionsDeclined(reason string) {
+	mmr.Declines[reason] += 1
+}
+
+func (mmr *MockMetricsRepository) ObserveQuery(operation, table string, elapsed time.Duration) {}
+
+func TestInstrumentedUseCase(t *testing.T) {
+	metrics := &MockMetricsRepository{TransactionsCreated: make(map[uint]int), Declines: make(map[string]int)}
+	evaluator := &MockTransactionEvaluator{EvaluateResult: &DomainError{Code: DomainTransactionDeclinedErrorCode, RuleID: "velocity"}}
+
+	svc := NewInstrumentedUseCase(NewUseCaseService(&MockStorageRepository{}, nil, EventDateWindow{}, &FakeClock{}, nil), metrics)
+	declining := NewInstrumentedUseCase(NewUseCaseService(&MockStorageRepository{}, evaluator, EventDateWindow{}, &FakeClock{}, nil), metrics)
+
+	if _, err := svc.CreateAccount(context.Background(), CreateAccountOptions{DocumentNumber: "123"}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err := svc.CreateTransaction(context.Background(), CreateTransactionOptions{AccountID: 1, OperationTypeID: PaymentOperationTypeID, Amount: 10_00}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err := svc.CreateTransactions(context.Background(), CreateTransactionsOptions{Items: []CreateTransactionOptions{
+		{AccountID: 1, OperationTypeID: CashPurchaseOperationTypeID, Amount: -10_00},
+		{AccountID: 1, OperationTypeID: CashPurchaseOperationTypeID, Amount: -5_00},
+	}}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err := declining.CreateTransaction(context.Background(), CreateTransactionOptions{AccountID: 1, OperationTypeID: CashPurchaseOperationTypeID, Amount: -10_00}); err == nil {
+		t.Error("expected transaction declined")
+		return
+	}
+
+	if got, expected := metrics.AccountsCreated, 1; got != expected {
+		t.Errorf("unexpected accounts created, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := metrics.TransactionsCreated[CashPurchaseOperationTypeID], 2; got != expected {
+		t.Errorf("unexpected cash purchases created, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := metrics.TransactionsCreated[PaymentOperationTypeID], 1; got != expected {
+		t.Errorf("unexpected payments created, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := metrics.Declines["velocity"], 1; got != expected {
+		t.Errorf("unexpected declines, got: %v, expected: %v", got, expected)
+		return
+	}
+}
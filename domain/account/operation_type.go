@@ -0,0 +1,16 @@
+package account
+
+const (
+	CashPurchaseOperationTypeID        uint = 1
+	InstallmentPurchaseOperationTypeID uint = 2
+	WithdrawalOperationTypeID          uint = 3
+	PaymentOperationTypeID             uint = 4
+	InterestOperationTypeID            uint = 5
+	LateFeeOperationTypeID             uint = 6
+	IOFOperationTypeID                 uint = 7
+)
+
+type OperationType struct {
+	ID          uint
+	Description string
+}
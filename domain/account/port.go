@@ -1,30 +1,98 @@
 package account
 
 import (
+	"context"
 	"time"
 )
 
+// Tenant is taken from the context the account is created with.
 type CreateAccountOptions struct {
+	CustomerID     uint
 	DocumentNumber string
+	ClosingDay     int
+	DueDay         int
+	TimeZone       string
+	Tenant         string
 }
 
+// EventDateWindow bounds how far from now a client informed event date may be, a zero side is unbounded.
+type EventDateWindow struct {
+	MaxBackdate time.Duration
+	MaxFuture   time.Duration
+}
+
+// EventDate defaults to now when it's zero.
 type CreateTransactionOptions struct {
 	AccountID       uint
 	OperationTypeID uint
 	Amount          int64
 	EventDate       time.Time
+	IdempotencyKey  string
+	Merchant        *Merchant
+	Metadata        map[string]string
+	// Scheduled tells an occurrence of a scheduled transaction, dated when it was due however late it's created,
+	// so it isn't bound by the backdate of the event date window.
+	Scheduled bool
+}
+
+type CreateTransactionsOptions struct {
+	Items  []CreateTransactionOptions
+	Atomic bool
+}
+
+type BatchItemStatus string
+
+const (
+	CreatedBatchItemStatus  BatchItemStatus = "created"
+	RejectedBatchItemStatus BatchItemStatus = "rejected"
+	AbortedBatchItemStatus  BatchItemStatus = "aborted"
+)
+
+// BatchItemResult carries the ID of a created item or the error a rejected one failed with,
+// aborted items were fine but weren't stored because another one in an atomic batch was rejected.
+type BatchItemResult struct {
+	Status BatchItemStatus
+	ID     uint
+	Err    error
+}
+
+// Zero fields in ListTransactionsOptions don't filter, To is exclusive.
+type ListTransactionsOptions struct {
+	AccountID uint
+	MCC       string
+	From      time.Time
+	To        time.Time
 }
 
 type StorageRepository interface {
-	CreateAccount(CreateAccountOptions) (uint, error)
-	GetAccountByID(uint) (Account, error)
-	HasAccountByDocumentNumber(string) (bool, error)
-	HasOperationTypeByID(uint) (bool, error)
-	CreateTransaction(CreateTransactionOptions) (uint, error)
+	CreateAccount(context.Context, CreateAccountOptions) (uint, error)
+	GetAccountByID(context.Context, uint) (Account, error)
+	HasAccountByDocumentNumber(context.Context, string) (bool, error)
+	HasOperationTypeByID(context.Context, uint) (bool, error)
+	CreateTransaction(context.Context, CreateTransactionOptions) (uint, error)
+	CreateTransactions(context.Context, []CreateTransactionOptions) ([]uint, error)
+	GetTransactionByID(context.Context, uint) (Transaction, error)
+	ListTransactions(context.Context, ListTransactionsOptions) ([]Transaction, error)
+	ListOperationTypes(context.Context) ([]OperationType, error)
+	SumTransactionsBefore(ctx context.Context, accountID uint, before time.Time) (int64, error)
+}
+
+// ReleaseFunc takes back what an evaluation accounted, for a transaction accepted but not stored in the end.
+type ReleaseFunc func(context.Context) error
+
+// TransactionEvaluator may decline a transaction before it's created, the ones it accepts
+// are already accounted for the next evaluations until released.
+type TransactionEvaluator interface {
+	Evaluate(context.Context, Account, CreateTransactionOptions) (ReleaseFunc, error)
 }
 
 type UseCase interface {
-	CreateAccount(CreateAccountOptions) (uint, error)
-	GetAccountByID(uint) (Account, error)
-	CreateTransaction(CreateTransactionOptions) (uint, error)
+	CreateAccount(context.Context, CreateAccountOptions) (uint, error)
+	GetAccountByID(context.Context, uint) (Account, error)
+	CreateTransaction(context.Context, CreateTransactionOptions) (uint, error)
+	CreateTransactions(context.Context, CreateTransactionsOptions) ([]BatchItemResult, error)
+	GetTransactionByID(context.Context, uint) (Transaction, error)
+	ListTransactions(context.Context, ListTransactionsOptions) ([]Transaction, error)
+	ListOperationTypes(context.Context) ([]OperationType, error)
+	GetBalance(ctx context.Context, accountID uint, at time.Time) (int64, error)
 }
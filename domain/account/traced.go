@@ -0,0 +1,82 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/trace"
+)
+
+// TracedUseCase starts a span around every call to the wrapped use case.
+type TracedUseCase struct {
+	UseCase
+	tracer trace.Tracer
+}
+
+func (tuc *TracedUseCase) CreateAccount(ctx context.Context, opts CreateAccountOptions) (uint, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.CreateAccount")
+	result, err := tuc.UseCase.CreateAccount(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) GetAccountByID(ctx context.Context, accountID uint) (Account, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.GetAccountByID")
+	result, err := tuc.UseCase.GetAccountByID(ctx, accountID)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) CreateTransaction(ctx context.Context, opts CreateTransactionOptions) (uint, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.CreateTransaction")
+	result, err := tuc.UseCase.CreateTransaction(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) CreateTransactions(ctx context.Context, opts CreateTransactionsOptions) ([]BatchItemResult, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.CreateTransactions")
+	result, err := tuc.UseCase.CreateTransactions(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) GetTransactionByID(ctx context.Context, transactionID uint) (Transaction, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.GetTransactionByID")
+	result, err := tuc.UseCase.GetTransactionByID(ctx, transactionID)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) ListTransactions(ctx context.Context, opts ListTransactionsOptions) ([]Transaction, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.ListTransactions")
+	result, err := tuc.UseCase.ListTransactions(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) ListOperationTypes(ctx context.Context) ([]OperationType, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.ListOperationTypes")
+	result, err := tuc.UseCase.ListOperationTypes(ctx)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) GetBalance(ctx context.Context, accountID uint, at time.Time) (int64, error) {
+	ctx, span := tuc.tracer.Start(ctx, "account.GetBalance")
+	result, err := tuc.UseCase.GetBalance(ctx, accountID, at)
+	span.End(err)
+
+	return result, err
+}
+
+func NewTracedUseCase(usecase UseCase, tracer trace.Tracer) *TracedUseCase {
+	return &TracedUseCase{usecase, tracer}
+}
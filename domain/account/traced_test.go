@@ -0,0 +1,71 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/trace"
+)
+
+type MockSpan struct {
+	Name   string
+	Err    error
+	Parent string
+	Ended  bool
+}
+
+func (ms *MockSpan) End(err error) {
+	ms.Err = err
+	ms.Ended = true
+}
+
+type mockSpanKey struct{}
+
+type MockTracer struct {
+	Spans []*MockSpan
+}
+
+func (mt *MockTracer) Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	span := &MockSpan{Name: name}
+	if parent, ok := ctx.Value(mockSpanKey{}).(*MockSpan); ok {
+		span.Parent = parent.Name
+	}
+	mt.Spans = append(mt.Spans, span)
+
+	return context.WithValue(ctx, mockSpanKey{}, span), span
+}
+
+func TestTracedUseCase(t *testing.T) {
+	tracer := &MockTracer{}
+	storage := &MockStorageRepository{GetAccountByIDErrorResult: NewInfraError(InfraAccountNotFoundErrorCode, "account not found")}
+	svc := NewTracedUseCase(NewUseCaseService(storage, nil, EventDateWindow{}, &FakeClock{At: time.Now()}, nil), tracer)
+
+	ctx, parent := tracer.Start(context.Background(), "GET /api/v1/accounts/{id}")
+	if _, err := svc.GetAccountByID(ctx, 1); err == nil {
+		t.Error("expected account not found")
+		return
+	}
+	parent.End(nil)
+
+	if got, expected := len(tracer.Spans), 2; got != expected {
+		t.Errorf("unexpected number of spans, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	span := tracer.Spans[1]
+	if got, expected := span.Name, "account.GetAccountByID"; got != expected {
+		t.Errorf("unexpected span name, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := span.Parent, "GET /api/v1/accounts/{id}"; got != expected {
+		t.Errorf("unexpected parent span, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if !span.Ended || span.Err == nil {
+		t.Errorf("expected span ended with error, got: %+v", span)
+		return
+	}
+}
@@ -0,0 +1,77 @@
+package account
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxMerchantFieldLength = 100
+	MaxMetadataKeys        = 20
+	MaxMetadataValueLength = 500
+)
+
+var (
+	mccPattern         = regexp.MustCompile(`^[0-9]{4}$`)
+	countryPattern     = regexp.MustCompile(`^[A-Z]{2}$`)
+	metadataKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,39}$`)
+)
+
+// Merchant tells where a transaction happened, MCC is the ISO 18245 merchant category code and
+// Country the ISO 3166 alpha-2 code.
+type Merchant struct {
+	Name       string
+	MCC        string
+	City       string
+	Country    string
+	TerminalID string
+}
+
+func (m Merchant) validate() error {
+	for field, value := range map[string]string{"name": m.Name, "city": m.City, "terminal_id": m.TerminalID} {
+		if utf8.RuneCountInString(value) > MaxMerchantFieldLength {
+			return NewDomainError(DomainInvalidMerchantErrorCode, fmt.Sprintf("merchant %s can't be longer than %d characters", field, MaxMerchantFieldLength))
+		}
+	}
+
+	if m.MCC != "" && !mccPattern.MatchString(m.MCC) {
+		return NewDomainError(DomainInvalidMerchantErrorCode, "merchant mcc must have 4 digits")
+	}
+
+	if m.Country != "" && !countryPattern.MatchString(m.Country) {
+		return NewDomainError(DomainInvalidMerchantErrorCode, "merchant country must be an ISO 3166 alpha-2 code")
+	}
+
+	return nil
+}
+
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataKeys {
+		return NewDomainError(DomainInvalidMetadataErrorCode, fmt.Sprintf("metadata can't have more than %d keys", MaxMetadataKeys))
+	}
+
+	for key, value := range metadata {
+		if !metadataKeyPattern.MatchString(key) {
+			return NewDomainError(DomainInvalidMetadataErrorCode, fmt.Sprintf("metadata key %q must be lowercase letters, digits, '_', '.' or '-' up to 40 characters", key))
+		}
+
+		if utf8.RuneCountInString(value) > MaxMetadataValueLength {
+			return NewDomainError(DomainInvalidMetadataErrorCode, fmt.Sprintf("metadata value for %q can't be longer than %d characters", key, MaxMetadataValueLength))
+		}
+	}
+
+	return nil
+}
+
+// EventDate keeps the offset it was informed with, compare it with Equal rather than ==.
+type Transaction struct {
+	ID              uint
+	AccountID       uint
+	OperationTypeID uint
+	Amount          int64
+	EventDate       time.Time
+	Merchant        *Merchant
+	Metadata        map[string]string
+}
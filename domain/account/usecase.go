@@ -1,25 +1,62 @@
 package account
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github/guiferpa/bank/domain/clock"
 	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/tenant"
 )
 
 type UseCaseService struct {
-	storage StorageRepository
-	logger  log.LoggerRepository
+	storage   StorageRepository
+	evaluator TransactionEvaluator
+	window    EventDateWindow
+	clock     clock.Clock
+	logger    log.LoggerRepository
 }
 
-func (ucs *UseCaseService) CreateAccount(opts CreateAccountOptions) (uint, error) {
-	has, err := ucs.storage.HasAccountByDocumentNumber(opts.DocumentNumber)
-	if err != nil {
-		return 0, err
+func (ucs *UseCaseService) CreateAccount(ctx context.Context, opts CreateAccountOptions) (uint, error) {
+	if id, ok := tenant.FromContext(ctx); ok {
+		opts.Tenant = id
+	}
+
+	if opts.ClosingDay == 0 {
+		opts.ClosingDay = DefaultClosingDay
+	}
+
+	if opts.DueDay == 0 {
+		opts.DueDay = DefaultDueDay
 	}
 
-	if has {
-		return 0, NewDomainError(DomainAccountAlreadyExistsErrorCode, "account already exists")
+	if opts.ClosingDay < 1 || opts.ClosingDay > MaxBillingDay || opts.DueDay < 1 || opts.DueDay > MaxBillingDay {
+		return 0, NewDomainError(DomainInvalidBillingDayErrorCode, fmt.Sprintf("closing and due days must be between 1 and %d", MaxBillingDay))
 	}
 
-	accountID, err := ucs.storage.CreateAccount(opts)
+	if opts.TimeZone == "" {
+		opts.TimeZone = DefaultTimeZone
+	}
+
+	if _, err := time.LoadLocation(opts.TimeZone); err != nil {
+		return 0, NewDomainError(DomainInvalidTimeZoneErrorCode, fmt.Sprintf("unknown time zone %q", opts.TimeZone))
+	}
+
+	// Accounts opened without a customer keep the legacy rule of one account per document number,
+	// the storage then takes care of binding it to the holder with the same document number.
+	if opts.CustomerID == 0 {
+		has, err := ucs.storage.HasAccountByDocumentNumber(ctx, opts.DocumentNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		if has {
+			return 0, NewDomainError(DomainAccountAlreadyExistsErrorCode, "account already exists")
+		}
+	}
+
+	accountID, err := ucs.storage.CreateAccount(ctx, opts)
 	if err != nil {
 		return 0, err
 	}
@@ -27,37 +64,265 @@ func (ucs *UseCaseService) CreateAccount(opts CreateAccountOptions) (uint, error
 	return accountID, nil
 }
 
-func (ucs *UseCaseService) CreateTransaction(opts CreateTransactionOptions) (uint, error) {
-	if _, err := ucs.storage.GetAccountByID(opts.AccountID); err != nil {
-		return 0, err
+// lookups caches the accounts and operation types already fetched while checking a batch of transactions.
+type lookups struct {
+	accounts       map[uint]Account
+	operationTypes map[uint]bool
+}
+
+func newLookups() *lookups {
+	return &lookups{make(map[uint]Account), make(map[uint]bool)}
+}
+
+func (ucs *UseCaseService) account(ctx context.Context, l *lookups, accountID uint) (Account, error) {
+	if acc, ok := l.accounts[accountID]; ok {
+		return acc, nil
 	}
 
-	hasOperationType, err := ucs.storage.HasOperationTypeByID(opts.OperationTypeID)
+	acc, err := ucs.storage.GetAccountByID(ctx, accountID)
 	if err != nil {
-		return 0, err
+		return Account{}, err
+	}
+
+	if err := Authorize(ctx, acc); err != nil {
+		return Account{}, err
+	}
+	l.accounts[accountID] = acc
+
+	return acc, nil
+}
+
+func (ucs *UseCaseService) hasOperationType(ctx context.Context, l *lookups, operationTypeID uint) (bool, error) {
+	if has, ok := l.operationTypes[operationTypeID]; ok {
+		return has, nil
+	}
+
+	has, err := ucs.storage.HasOperationTypeByID(ctx, operationTypeID)
+	if err != nil {
+		return false, err
+	}
+	l.operationTypes[operationTypeID] = has
+
+	return has, nil
+}
+
+func noRelease(context.Context) error {
+	return nil
+}
+
+// release takes back what the evaluator accounted for transactions not stored in the end. Failing to is only
+// logged, those transactions counting for the rules until their windows go by.
+func (ucs *UseCaseService) release(ctx context.Context, releases []ReleaseFunc) {
+	for _, release := range releases {
+		if err := release(ctx); err != nil && ucs.logger != nil {
+			ucs.logger.Error(ctx, fmt.Sprintf("evaluation of transaction not released: %s", err))
+		}
+	}
+}
+
+// checkTransaction runs every rule a transaction goes through before being stored, evaluator included. What the
+// evaluator accounted is released by the returned function if the transaction doesn't get stored.
+func (ucs *UseCaseService) checkTransaction(ctx context.Context, l *lookups, opts CreateTransactionOptions, now time.Time) (CreateTransactionOptions, ReleaseFunc, error) {
+	if opts.EventDate.IsZero() {
+		opts.EventDate = now
+	}
+
+	if ucs.window.MaxBackdate > 0 && !opts.Scheduled && opts.EventDate.Before(now.Add(-ucs.window.MaxBackdate)) {
+		return opts, nil, NewDomainError(DomainInvalidEventDateErrorCode, fmt.Sprintf("event date can't be more than %s in the past", ucs.window.MaxBackdate))
+	}
+
+	if ucs.window.MaxFuture > 0 && opts.EventDate.After(now.Add(ucs.window.MaxFuture)) {
+		return opts, nil, NewDomainError(DomainInvalidEventDateErrorCode, fmt.Sprintf("event date can't be more than %s in the future", ucs.window.MaxFuture))
+	}
+
+	if opts.Merchant != nil {
+		if err := opts.Merchant.validate(); err != nil {
+			return opts, nil, err
+		}
+	}
+
+	if err := validateMetadata(opts.Metadata); err != nil {
+		return opts, nil, err
+	}
+
+	acc, err := ucs.account(ctx, l, opts.AccountID)
+	if err != nil {
+		return opts, nil, err
+	}
+
+	hasOperationType, err := ucs.hasOperationType(ctx, l, opts.OperationTypeID)
+	if err != nil {
+		return opts, nil, err
 	}
 
 	if !hasOperationType {
-		return 0, NewDomainError(DomainOperationTypeDoesntExistErrorCode, "operation type doesn't exist")
+		return opts, nil, NewDomainError(DomainOperationTypeDoesntExistErrorCode, "operation type doesn't exist")
+	}
+
+	if ucs.evaluator == nil {
+		return opts, noRelease, nil
+	}
+
+	release, err := ucs.evaluator.Evaluate(ctx, acc, opts)
+	if err != nil {
+		return opts, nil, err
 	}
 
-	transID, err := ucs.storage.CreateTransaction(opts)
+	return opts, release, nil
+}
+
+func (ucs *UseCaseService) CreateTransaction(ctx context.Context, opts CreateTransactionOptions) (uint, error) {
+	opts, release, err := ucs.checkTransaction(ctx, newLookups(), opts, ucs.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	transID, err := ucs.storage.CreateTransaction(ctx, opts)
 	if err != nil {
+		ucs.release(ctx, []ReleaseFunc{release})
 		return 0, err
 	}
 
 	return transID, nil
 }
 
-func (ucs *UseCaseService) GetAccountByID(accountID uint) (Account, error) {
-	acc, err := ucs.storage.GetAccountByID(accountID)
+// CreateTransactions checks every item before storing the accepted ones. In atomic mode a single rejected item
+// aborts the whole batch and the accepted ones are stored at once, otherwise only the rejected ones are left out and
+// each accepted one is stored on its own, one failing to be stored being rejected without the others. The accepted
+// items which don't get stored are released from the evaluator.
+func (ucs *UseCaseService) CreateTransactions(ctx context.Context, opts CreateTransactionsOptions) ([]BatchItemResult, error) {
+	l, now := newLookups(), ucs.clock.Now()
+
+	results := make([]BatchItemResult, len(opts.Items))
+	accepted := make([]CreateTransactionOptions, 0, len(opts.Items))
+	releases := make([]ReleaseFunc, 0, len(opts.Items))
+	indexes := make([]int, 0, len(opts.Items))
+	rejected := false
+	for i, item := range opts.Items {
+		item, release, err := ucs.checkTransaction(ctx, l, item, now)
+		if err != nil {
+			if cerr, ok := err.(*InfraError); ok && cerr.Code == InfraUnknownError {
+				ucs.release(ctx, releases)
+				return nil, err
+			}
+
+			results[i] = BatchItemResult{Status: RejectedBatchItemStatus, Err: err}
+			rejected = true
+			continue
+		}
+
+		accepted = append(accepted, item)
+		releases = append(releases, release)
+		indexes = append(indexes, i)
+	}
+
+	if opts.Atomic && rejected {
+		ucs.release(ctx, releases)
+		for _, i := range indexes {
+			results[i] = BatchItemResult{Status: AbortedBatchItemStatus}
+		}
+
+		return results, nil
+	}
+
+	if len(accepted) == 0 {
+		return results, nil
+	}
+
+	if !opts.Atomic {
+		for n, i := range indexes {
+			id, err := ucs.storage.CreateTransaction(ctx, accepted[n])
+			if err != nil {
+				ucs.release(ctx, releases[n:n+1])
+				results[i] = BatchItemResult{Status: RejectedBatchItemStatus, Err: err}
+				continue
+			}
+
+			results[i] = BatchItemResult{Status: CreatedBatchItemStatus, ID: id}
+		}
+
+		return results, nil
+	}
+
+	ids, err := ucs.storage.CreateTransactions(ctx, accepted)
+	if err != nil {
+		ucs.release(ctx, releases)
+		return nil, err
+	}
+
+	for n, i := range indexes {
+		results[i] = BatchItemResult{Status: CreatedBatchItemStatus, ID: ids[n]}
+	}
+
+	return results, nil
+}
+
+func (ucs *UseCaseService) GetAccountByID(ctx context.Context, accountID uint) (Account, error) {
+	acc, err := ucs.storage.GetAccountByID(ctx, accountID)
 	if err != nil {
 		return Account{}, err
 	}
 
+	if err := Authorize(ctx, acc); err != nil {
+		return Account{}, err
+	}
+
 	return acc, nil
 }
 
-func NewUseCaseService(storage StorageRepository, logger log.LoggerRepository) *UseCaseService {
-	return &UseCaseService{storage, logger}
+func (ucs *UseCaseService) GetTransactionByID(ctx context.Context, transactionID uint) (Transaction, error) {
+	tx, err := ucs.storage.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	if _, err := ucs.GetAccountByID(ctx, tx.AccountID); err != nil {
+		return Transaction{}, err
+	}
+
+	return tx, nil
+}
+
+func (ucs *UseCaseService) ListTransactions(ctx context.Context, opts ListTransactionsOptions) ([]Transaction, error) {
+	if _, err := ucs.GetAccountByID(ctx, opts.AccountID); err != nil {
+		return nil, err
+	}
+
+	txs, err := ucs.storage.ListTransactions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+func (ucs *UseCaseService) ListOperationTypes(ctx context.Context) ([]OperationType, error) {
+	ots, err := ucs.storage.ListOperationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ots, nil
+}
+
+// GetBalance sums every transaction of the account with event date before at, now when it's zero.
+func (ucs *UseCaseService) GetBalance(ctx context.Context, accountID uint, at time.Time) (int64, error) {
+	if at.IsZero() {
+		at = ucs.clock.Now()
+	}
+
+	if _, err := ucs.GetAccountByID(ctx, accountID); err != nil {
+		return 0, err
+	}
+
+	balance, err := ucs.storage.SumTransactionsBefore(ctx, accountID, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func NewUseCaseService(storage StorageRepository, evaluator TransactionEvaluator, window EventDateWindow, clock clock.Clock, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, evaluator, window, clock, logger}
 }
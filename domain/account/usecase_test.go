@@ -1,40 +1,95 @@
 package account
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/tenant"
+)
+
+type FakeClock struct {
+	At time.Time
+}
+
+func (fc *FakeClock) Now() time.Time {
+	return fc.At
+}
 
 type MockStorageRepository struct {
 	NCalledCreateAccount              int
 	NCalledCreatedTransaction         int
+	NCalledCreateTransactions         int
 	NCalledGetAccountByID             int
 	NCalledHasAccountByDocumentNumber int
 	NCalledHasOperationTypeByID       int
 	DocumentNumberResult              string
+	TenantResult                      string
 	HasAccountByDocumentNumberResult  bool
+	GetAccountByIDResult              Account
 	GetAccountByIDErrorResult         error
+	SumTransactionsBeforeResult       int64
+	SumTransactionsBeforeAt           time.Time
+	CreateTransactionErrorResult      error
+	CreateTransactionsErrorResult     error
 }
 
-func (msr *MockStorageRepository) CreateAccount(opts CreateAccountOptions) (uint, error) {
+func (msr *MockStorageRepository) CreateAccount(ctx context.Context, opts CreateAccountOptions) (uint, error) {
 	msr.NCalledCreateAccount += 1
 	msr.DocumentNumberResult = opts.DocumentNumber
+	msr.TenantResult = opts.Tenant
 	return 0, nil
 }
 
-func (msr *MockStorageRepository) CreateTransaction(opts CreateTransactionOptions) (uint, error) {
+func (msr *MockStorageRepository) CreateTransaction(ctx context.Context, opts CreateTransactionOptions) (uint, error) {
 	msr.NCalledCreatedTransaction += 1
-	return 0, nil
+	if msr.CreateTransactionErrorResult != nil {
+		return 0, msr.CreateTransactionErrorResult
+	}
+	return uint(msr.NCalledCreatedTransaction), nil
+}
+
+func (msr *MockStorageRepository) CreateTransactions(ctx context.Context, items []CreateTransactionOptions) ([]uint, error) {
+	msr.NCalledCreateTransactions += 1
+	if msr.CreateTransactionsErrorResult != nil {
+		return nil, msr.CreateTransactionsErrorResult
+	}
+	ids := make([]uint, 0, len(items))
+	for i := range items {
+		ids = append(ids, uint(i+1))
+	}
+	return ids, nil
+}
+
+func (msr *MockStorageRepository) GetTransactionByID(ctx context.Context, transactionID uint) (Transaction, error) {
+	return Transaction{ID: transactionID}, nil
+}
+
+func (msr *MockStorageRepository) ListTransactions(ctx context.Context, opts ListTransactionsOptions) ([]Transaction, error) {
+	return []Transaction{}, nil
+}
+
+func (msr *MockStorageRepository) ListOperationTypes(ctx context.Context) ([]OperationType, error) {
+	return []OperationType{{ID: CashPurchaseOperationTypeID, Description: "COMPRA A VISTA"}}, nil
+}
+
+func (msr *MockStorageRepository) SumTransactionsBefore(ctx context.Context, accountID uint, before time.Time) (int64, error) {
+	msr.SumTransactionsBeforeAt = before
+	return msr.SumTransactionsBeforeResult, nil
 }
 
-func (msr *MockStorageRepository) GetAccountByID(accountID uint) (Account, error) {
+func (msr *MockStorageRepository) GetAccountByID(ctx context.Context, accountID uint) (Account, error) {
 	msr.NCalledGetAccountByID += 1
-	return Account{}, msr.GetAccountByIDErrorResult
+	return msr.GetAccountByIDResult, msr.GetAccountByIDErrorResult
 }
 
-func (msr *MockStorageRepository) HasAccountByDocumentNumber(documentNumber string) (bool, error) {
+func (msr *MockStorageRepository) HasAccountByDocumentNumber(ctx context.Context, documentNumber string) (bool, error) {
 	msr.NCalledHasAccountByDocumentNumber += 1
 	return msr.HasAccountByDocumentNumberResult, nil
 }
 
-func (msr *MockStorageRepository) HasOperationTypeByID(operationTypeID uint) (bool, error) {
+func (msr *MockStorageRepository) HasOperationTypeByID(ctx context.Context, operationTypeID uint) (bool, error) {
 	msr.NCalledHasOperationTypeByID += 1
 	return true, nil
 }
@@ -59,7 +114,7 @@ func TestCreateAccount(t *testing.T) {
 		svc := &UseCaseService{storage: mock}
 
 		opts := CreateAccountOptions{DocumentNumber: s.DocumentNumber}
-		if _, err := svc.CreateAccount(opts); err != nil {
+		if _, err := svc.CreateAccount(context.Background(), opts); err != nil {
 			t.Error(err)
 			return
 		}
@@ -103,7 +158,7 @@ func TestCreateAccountWithDocumentNumberAlreadyRegistered(t *testing.T) {
 		svc := &UseCaseService{storage: mock}
 
 		opts := CreateAccountOptions{DocumentNumber: s.DocumentNumber}
-		_, err := svc.CreateAccount(opts)
+		_, err := svc.CreateAccount(context.Background(), opts)
 
 		cerr, ok := err.(*DomainError)
 
@@ -129,6 +184,47 @@ func TestCreateAccountWithDocumentNumberAlreadyRegistered(t *testing.T) {
 	}
 }
 
+func TestCreateAccountForCustomer(t *testing.T) {
+	suite := []struct {
+		CustomerID                                uint
+		DocumentNumber                            string
+		HasAccountByDocumentNumberResult          bool
+		ExpectedNCalledHasAccountByDocumentNumber int
+		ExpectedNCalledCreateAccount              int
+	}{
+		{
+			CustomerID:                       1,
+			DocumentNumber:                   "123",
+			HasAccountByDocumentNumberResult: true,
+			ExpectedNCalledHasAccountByDocumentNumber: 0,
+			ExpectedNCalledCreateAccount:              1,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{
+			HasAccountByDocumentNumberResult: s.HasAccountByDocumentNumberResult,
+		}
+		svc := &UseCaseService{storage: mock}
+
+		opts := CreateAccountOptions{CustomerID: s.CustomerID, DocumentNumber: s.DocumentNumber}
+		if _, err := svc.CreateAccount(context.Background(), opts); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := mock.NCalledHasAccountByDocumentNumber, s.ExpectedNCalledHasAccountByDocumentNumber; got != expected {
+			t.Errorf("unexpected N called HasAccountByDocumentNumber, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreateAccount, s.ExpectedNCalledCreateAccount; got != expected {
+			t.Errorf("unexpected N called CreateAccount, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
 func TestCreateTransaction(t *testing.T) {
 	suite := []struct {
 		ExpectedNCalledGetAccountByID    int
@@ -142,10 +238,10 @@ func TestCreateTransaction(t *testing.T) {
 
 	for _, s := range suite {
 		mock := &MockStorageRepository{}
-		svc := &UseCaseService{storage: mock}
+		svc := &UseCaseService{storage: mock, clock: &FakeClock{}}
 
 		opts := CreateTransactionOptions{}
-		if _, err := svc.CreateTransaction(opts); err != nil {
+		if _, err := svc.CreateTransaction(context.Background(), opts); err != nil {
 			t.Error(err)
 			return
 		}
@@ -162,6 +258,349 @@ func TestCreateTransaction(t *testing.T) {
 	}
 }
 
+type MockTransactionEvaluator struct {
+	NCalledEvaluate int
+	EvaluateResult  error
+	// Accounted is how many accepted transactions weren't released.
+	Accounted int
+}
+
+func (mte *MockTransactionEvaluator) Evaluate(ctx context.Context, acc Account, opts CreateTransactionOptions) (ReleaseFunc, error) {
+	mte.NCalledEvaluate += 1
+	if mte.EvaluateResult != nil {
+		return nil, mte.EvaluateResult
+	}
+
+	mte.Accounted += 1
+	return func(ctx context.Context) error {
+		mte.Accounted -= 1
+		return nil
+	}, nil
+}
+
+func TestCreateTransactionDeclined(t *testing.T) {
+	suite := []struct {
+		EvaluateResult                   error
+		ExpectedNCalledCreateTransaction int
+	}{
+		{
+			EvaluateResult:                   nil,
+			ExpectedNCalledCreateTransaction: 1,
+		},
+		{
+			EvaluateResult:                   &DomainError{Code: DomainTransactionDeclinedErrorCode, RuleID: "velocity"},
+			ExpectedNCalledCreateTransaction: 0,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{}
+		evaluator := &MockTransactionEvaluator{EvaluateResult: s.EvaluateResult}
+		svc := &UseCaseService{storage: mock, evaluator: evaluator, clock: &FakeClock{}}
+
+		_, err := svc.CreateTransaction(context.Background(), CreateTransactionOptions{})
+		if got, expected := err, s.EvaluateResult; got != expected {
+			t.Errorf("unexpected error, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := evaluator.NCalledEvaluate, 1; got != expected {
+			t.Errorf("unexpected N called Evaluate, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreatedTransaction, s.ExpectedNCalledCreateTransaction; got != expected {
+			t.Errorf("unexpected N called CreateTransaction, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestCreateTransactionEventDate(t *testing.T) {
+	now := time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC)
+	window := EventDateWindow{MaxBackdate: 72 * time.Hour, MaxFuture: 5 * time.Minute}
+
+	suite := []struct {
+		EventDate         time.Time
+		Scheduled         bool
+		ExpectedErrorCode ErrorCode
+	}{
+		{EventDate: time.Time{}},
+		{EventDate: now.Add(-48 * time.Hour)},
+		{EventDate: time.Date(2023, time.March, 10, 9, 1, 0, 0, time.FixedZone("BRT", -3*60*60))},
+		{EventDate: now.Add(-73 * time.Hour), ExpectedErrorCode: DomainInvalidEventDateErrorCode},
+		{EventDate: now.Add(-240 * time.Hour), Scheduled: true},
+		{EventDate: now.Add(10 * time.Minute), ExpectedErrorCode: DomainInvalidEventDateErrorCode},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{}
+		svc := NewUseCaseService(mock, nil, window, &FakeClock{now}, nil)
+
+		_, err := svc.CreateTransaction(context.Background(), CreateTransactionOptions{EventDate: s.EventDate, Scheduled: s.Scheduled})
+		if s.ExpectedErrorCode == "" {
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			continue
+		}
+
+		cerr, ok := err.(*DomainError)
+		if !ok {
+			t.Errorf("unexpected error, got: %v", err)
+			return
+		}
+
+		if got, expected := cerr.Code, s.ExpectedErrorCode; got != expected {
+			t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreatedTransaction, 0; got != expected {
+			t.Errorf("unexpected N called CreateTransaction, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestCreateTransactionWithMerchantAndMetadata(t *testing.T) {
+	suite := []struct {
+		Merchant          *Merchant
+		Metadata          map[string]string
+		ExpectedErrorCode ErrorCode
+	}{
+		{
+			Merchant: &Merchant{Name: "Padaria Real", MCC: "5462", City: "São Paulo", Country: "BR", TerminalID: "T-01"},
+			Metadata: map[string]string{"order_id": "42", "channel.pos": "chip"},
+		},
+		{
+			Merchant:          &Merchant{Name: "Padaria Real", MCC: "546"},
+			ExpectedErrorCode: DomainInvalidMerchantErrorCode,
+		},
+		{
+			Merchant:          &Merchant{Name: "Padaria Real", Country: "Brazil"},
+			ExpectedErrorCode: DomainInvalidMerchantErrorCode,
+		},
+		{
+			Metadata:          map[string]string{"Order ID": "42"},
+			ExpectedErrorCode: DomainInvalidMetadataErrorCode,
+		},
+		{
+			Metadata:          map[string]string{"note": string(make([]byte, MaxMetadataValueLength+1))},
+			ExpectedErrorCode: DomainInvalidMetadataErrorCode,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{}
+		svc := &UseCaseService{storage: mock, clock: &FakeClock{}}
+
+		_, err := svc.CreateTransaction(context.Background(), CreateTransactionOptions{Merchant: s.Merchant, Metadata: s.Metadata})
+		if s.ExpectedErrorCode == "" {
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			continue
+		}
+
+		cerr, ok := err.(*DomainError)
+		if !ok {
+			t.Errorf("unexpected error, got: %v", err)
+			return
+		}
+
+		if got, expected := cerr.Code, s.ExpectedErrorCode; got != expected {
+			t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreatedTransaction, 0; got != expected {
+			t.Errorf("unexpected N called CreateTransaction, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestCreateTransactions(t *testing.T) {
+	suite := []struct {
+		Atomic                            bool
+		ExpectedStatuses                  []BatchItemStatus
+		ExpectedNCalledCreateTransaction  int
+		ExpectedNCalledCreateTransactions int
+	}{
+		{
+			Atomic:                            false,
+			ExpectedStatuses:                  []BatchItemStatus{CreatedBatchItemStatus, RejectedBatchItemStatus, CreatedBatchItemStatus},
+			ExpectedNCalledCreateTransaction:  2,
+			ExpectedNCalledCreateTransactions: 0,
+		},
+		{
+			Atomic:                            true,
+			ExpectedStatuses:                  []BatchItemStatus{AbortedBatchItemStatus, RejectedBatchItemStatus, AbortedBatchItemStatus},
+			ExpectedNCalledCreateTransaction:  0,
+			ExpectedNCalledCreateTransactions: 0,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{}
+		svc := &UseCaseService{storage: mock, clock: &FakeClock{}}
+
+		results, err := svc.CreateTransactions(context.Background(), CreateTransactionsOptions{
+			Items: []CreateTransactionOptions{
+				{AccountID: 1, OperationTypeID: 1, Amount: -10_00},
+				{AccountID: 1, OperationTypeID: 1, Amount: -10_00, Metadata: map[string]string{"Order ID": "42"}},
+				{AccountID: 1, OperationTypeID: 4, Amount: 10_00},
+			},
+			Atomic: s.Atomic,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		for i, expected := range s.ExpectedStatuses {
+			if got := results[i].Status; got != expected {
+				t.Errorf("unexpected status for item %d, got: %v, expected: %v", i, got, expected)
+				return
+			}
+		}
+
+		if got, expected := results[2].ID, uint(0); s.Atomic && got != expected {
+			t.Errorf("unexpected ID for aborted item, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledGetAccountByID, 1; got != expected {
+			t.Errorf("unexpected N called GetAccountByID, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreatedTransaction, s.ExpectedNCalledCreateTransaction; got != expected {
+			t.Errorf("unexpected N called CreateTransaction, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreateTransactions, s.ExpectedNCalledCreateTransactions; got != expected {
+			t.Errorf("unexpected N called CreateTransactions, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestCreateTransactionsReleasesEvaluations(t *testing.T) {
+	valid := []CreateTransactionOptions{
+		{AccountID: 1, OperationTypeID: 1, Amount: -10_00},
+		{AccountID: 1, OperationTypeID: 4, Amount: 10_00},
+	}
+	mixed := []CreateTransactionOptions{
+		{AccountID: 1, OperationTypeID: 1, Amount: -10_00},
+		{AccountID: 1, OperationTypeID: 1, Amount: -10_00, Metadata: map[string]string{"Order ID": "42"}},
+		{AccountID: 1, OperationTypeID: 4, Amount: 10_00},
+	}
+	duplicated := NewInfraError(InfraTransactionDuplicatedErrorCode, "transaction already exists")
+	refused := errors.New("connection refused")
+
+	suite := []struct {
+		Name              string
+		Items             []CreateTransactionOptions
+		Atomic            bool
+		ItemErr           error
+		BatchErr          error
+		ExpectedAccounted int
+	}{
+		{"Stored batch", mixed, false, nil, nil, 2},
+		{"Aborted atomic batch", mixed, true, nil, nil, 0},
+		{"Items failing to be stored", mixed, false, duplicated, nil, 0},
+		{"Atomic batch failing to be stored", valid, true, nil, refused, 0},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			mock := &MockStorageRepository{CreateTransactionErrorResult: s.ItemErr, CreateTransactionsErrorResult: s.BatchErr}
+			evaluator := &MockTransactionEvaluator{}
+			svc := &UseCaseService{storage: mock, evaluator: evaluator, clock: &FakeClock{}}
+
+			_, err := svc.CreateTransactions(context.Background(), CreateTransactionsOptions{Items: s.Items, Atomic: s.Atomic})
+			if got, expected := err, s.BatchErr; got != expected {
+				t.Errorf("unexpected error, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := evaluator.NCalledEvaluate, 2; got != expected {
+				t.Errorf("unexpected N called Evaluate, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := evaluator.Accounted, s.ExpectedAccounted; got != expected {
+				t.Errorf("unexpected accounted transactions, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestCreateTransactionsItemFailingToBeStored(t *testing.T) {
+	mock := &MockStorageRepository{CreateTransactionErrorResult: NewInfraError(InfraTransactionDuplicatedErrorCode, "transaction already exists")}
+	svc := &UseCaseService{storage: mock, clock: &FakeClock{}}
+
+	results, err := svc.CreateTransactions(context.Background(), CreateTransactionsOptions{
+		Items: []CreateTransactionOptions{{AccountID: 1, OperationTypeID: 1, Amount: -10_00, IdempotencyKey: "a1"}},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := results[0].Status, RejectedBatchItemStatus; got != expected {
+		t.Errorf("unexpected status, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := results[0].Err, mock.CreateTransactionErrorResult; got != expected {
+		t.Errorf("unexpected error, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestCreateAccountWithTimeZone(t *testing.T) {
+	suite := []struct {
+		TimeZone          string
+		ExpectedErrorCode ErrorCode
+	}{
+		{TimeZone: ""},
+		{TimeZone: "America/Sao_Paulo"},
+		{TimeZone: "Mars/Olympus_Mons", ExpectedErrorCode: DomainInvalidTimeZoneErrorCode},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{}
+		svc := &UseCaseService{storage: mock}
+
+		_, err := svc.CreateAccount(context.Background(), CreateAccountOptions{DocumentNumber: "123", TimeZone: s.TimeZone})
+		if s.ExpectedErrorCode == "" {
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			continue
+		}
+
+		cerr, ok := err.(*DomainError)
+		if !ok {
+			t.Errorf("unexpected error, got: %v", err)
+			return
+		}
+
+		if got, expected := cerr.Code, s.ExpectedErrorCode; got != expected {
+			t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
 func TestGetAccountById(t *testing.T) {
 	suite := []struct {
 		ExpectedNCalledGetAccountByID int
@@ -174,7 +613,7 @@ func TestGetAccountById(t *testing.T) {
 		svc := &UseCaseService{storage: mock}
 
 		accountID := uint(20)
-		if _, err := svc.GetAccountByID(accountID); err != nil {
+		if _, err := svc.GetAccountByID(context.Background(), accountID); err != nil {
 			t.Error(err)
 			return
 		}
@@ -203,7 +642,7 @@ func TestGetAccountByIdWithNotFound(t *testing.T) {
 		}
 		svc := &UseCaseService{storage: mock}
 
-		_, err := svc.GetAccountByID(20)
+		_, err := svc.GetAccountByID(context.Background(), 20)
 		cerr, ok := err.(*InfraError)
 		if !ok {
 			t.Error("unexpected error")
@@ -221,3 +660,101 @@ func TestGetAccountByIdWithNotFound(t *testing.T) {
 		}
 	}
 }
+
+func TestAccountOwnership(t *testing.T) {
+	suite := []struct {
+		Name          string
+		Tenant        string
+		AccountTenant string
+		Allowed       bool
+	}{
+		{"Bank caller on a bank account", "", "", true},
+		{"Bank caller on a partner account", "", "acme", true},
+		{"Partner on its own account", "acme", "acme", true},
+		{"Partner on another partner's account", "acme", "globex", false},
+		{"Partner on a bank account", "acme", "", false},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if s.Tenant != "" {
+				ctx = tenant.NewContext(ctx, s.Tenant)
+			}
+
+			mock := &MockStorageRepository{GetAccountByIDResult: Account{ID: 20, Tenant: s.AccountTenant}}
+			svc := &UseCaseService{storage: mock, clock: &FakeClock{}}
+
+			_, getErr := svc.GetAccountByID(ctx, 20)
+			_, balanceErr := svc.GetBalance(ctx, 20, time.Time{})
+			for _, err := range []error{getErr, balanceErr} {
+				if s.Allowed {
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					continue
+				}
+
+				cerr, ok := err.(*DomainError)
+				if !ok || cerr.Code != DomainAccountForbiddenErrorCode {
+					t.Errorf("unexpected error, got: %v, expected: %v", err, DomainAccountForbiddenErrorCode)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestCreateAccountForTenant(t *testing.T) {
+	mock := &MockStorageRepository{}
+	svc := &UseCaseService{storage: mock}
+
+	if _, err := svc.CreateAccount(tenant.NewContext(context.Background(), "acme"), CreateAccountOptions{DocumentNumber: "12345678900"}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := mock.TenantResult, "acme"; got != expected {
+		t.Errorf("unexpected tenant, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	now := time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	suite := []struct {
+		At         time.Time
+		ExpectedAt time.Time
+	}{
+		{ExpectedAt: now},
+		{At: now.AddDate(0, 0, -1), ExpectedAt: now.AddDate(0, 0, -1)},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{SumTransactionsBeforeResult: -12_50}
+		svc := NewUseCaseService(mock, nil, EventDateWindow{}, &FakeClock{now}, nil)
+
+		balance, err := svc.GetBalance(context.Background(), 1, s.At)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := balance, int64(-12_50); got != expected {
+			t.Errorf("unexpected balance, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.SumTransactionsBeforeAt, s.ExpectedAt; !got.Equal(expected) {
+			t.Errorf("unexpected balance instant, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledGetAccountByID, 1; got != expected {
+			t.Errorf("unexpected number of calls to GetAccountByID, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
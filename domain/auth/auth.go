@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type PrincipalKind string
+
+const (
+	APIKeyPrincipalKind PrincipalKind = "api_key"
+	TokenPrincipalKind  PrincipalKind = "token"
+)
+
+type Scope string
+
+const (
+	ReadAccountsScope      Scope = "accounts:read"
+	WriteAccountsScope     Scope = "accounts:write"
+	WriteTransactionsScope Scope = "transactions:write"
+)
+
+// Scopes are the ones a principal may be granted, reading accounts covers their transactions, invoices,
+// statements and scheduled transactions.
+var Scopes = []Scope{ReadAccountsScope, WriteAccountsScope, WriteTransactionsScope}
+
+func ParseScope(s string) (Scope, error) {
+	for _, scope := range Scopes {
+		if string(scope) == s {
+			return scope, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown scope %q", s)
+}
+
+// Principal is who is calling, ID is the prefix of an API key or the subject of a token. A principal with
+// a Tenant is a partner and only reaches the accounts it created.
+type Principal struct {
+	Kind   PrincipalKind
+	ID     string
+	Name   string
+	Scopes []Scope
+	Tenant string
+}
+
+func (p Principal) String() string {
+	return fmt.Sprintf("%s:%s", p.Kind, p.ID)
+}
+
+func (p Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// APIKey is stored without its secret, Prefix finds it and Hash checks the secret. A key which signs requests
+// has a SigningSecret too, kept as is since it's needed to check the signatures.
+type APIKey struct {
+	ID            uint
+	Name          string
+	Prefix        string
+	Hash          string
+	SigningSecret string
+	Scopes        []Scope
+	Tenant        string
+	CreatedAt     time.Time
+	RevokedAt     time.Time
+}
+
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Signature is what a signed request carries, KeyID is the prefix of the API key it was signed by and MAC
+// the signature of Payload, the request in canonical form as pkg/signing makes it.
+type Signature struct {
+	KeyID     string
+	Timestamp time.Time
+	Nonce     string
+	Payload   []byte
+	MAC       string
+}
+
+// Credentials are the API key, the bearer token or the signature of a request, at most one of them is expected.
+type Credentials struct {
+	APIKey    string
+	Token     string
+	Signature *Signature
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Tenant makes the key a partner's one, empty for the bank's own callers. Signing gives the key a secret to sign
+// requests with.
+type CreateAPIKeyOptions struct {
+	Name    string
+	Scopes  []Scope
+	Tenant  string
+	Signing bool
+}
+
+// NonceStore remembers the nonces of signed requests until they expire, RememberNonce is false for one
+// already remembered, a replayed request.
+type NonceStore interface {
+	RememberNonce(ctx context.Context, nonce string, expiresAt time.Time) (bool, error)
+}
+
+// SigningOptions bound how far from now the timestamp of a signed request may be, signed requests aren't
+// accepted without a Window and Nonces.
+type SigningOptions struct {
+	Window time.Duration
+	Nonces NonceStore
+}
+
+type StorageRepository interface {
+	CreateAPIKey(context.Context, APIKey) (uint, error)
+	GetAPIKeyByPrefix(context.Context, string) (APIKey, error)
+	ListAPIKeys(context.Context) ([]APIKey, error)
+	RevokeAPIKey(context.Context, uint, time.Time) error
+}
+
+// TokenVerifier checks the signature and claims of a bearer token and tells who it belongs to.
+type TokenVerifier interface {
+	Verify(context.Context, string) (Principal, error)
+}
+
+type UseCase interface {
+	CreateAPIKey(context.Context, CreateAPIKeyOptions) (APIKey, string, error)
+	ListAPIKeys(context.Context) ([]APIKey, error)
+	RevokeAPIKey(context.Context, uint) error
+	Authenticate(context.Context, Credentials) (Principal, error)
+}
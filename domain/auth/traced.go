@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/trace"
+)
+
+// TracedUseCase starts a span around every call to the wrapped use case.
+type TracedUseCase struct {
+	UseCase
+	tracer trace.Tracer
+}
+
+func (tuc *TracedUseCase) CreateAPIKey(ctx context.Context, opts CreateAPIKeyOptions) (APIKey, string, error) {
+	ctx, span := tuc.tracer.Start(ctx, "auth.CreateAPIKey")
+	result, raw, err := tuc.UseCase.CreateAPIKey(ctx, opts)
+	span.End(err)
+
+	return result, raw, err
+}
+
+func (tuc *TracedUseCase) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	ctx, span := tuc.tracer.Start(ctx, "auth.ListAPIKeys")
+	result, err := tuc.UseCase.ListAPIKeys(ctx)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) RevokeAPIKey(ctx context.Context, id uint) error {
+	ctx, span := tuc.tracer.Start(ctx, "auth.RevokeAPIKey")
+	err := tuc.UseCase.RevokeAPIKey(ctx, id)
+	span.End(err)
+
+	return err
+}
+
+func (tuc *TracedUseCase) Authenticate(ctx context.Context, creds Credentials) (Principal, error) {
+	ctx, span := tuc.tracer.Start(ctx, "auth.Authenticate")
+	result, err := tuc.UseCase.Authenticate(ctx, creds)
+	span.End(err)
+
+	return result, err
+}
+
+func NewTracedUseCase(usecase UseCase, tracer trace.Tracer) *TracedUseCase {
+	return &TracedUseCase{usecase, tracer}
+}
@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/clock"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/pkg/signing"
+)
+
+// API keys look like bank_<prefix>_<secret>, the prefix is kept in clear to find the key and names it in logs.
+const APIKeyPrefix = "bank_"
+
+const (
+	apiKeyPrefixBytes  = 6
+	apiKeySecretBytes  = 32
+	signingSecretBytes = 32
+)
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func invalidCredentials(message string) error {
+	return account.NewDomainError(account.DomainInvalidCredentialsErrorCode, message)
+}
+
+type UseCaseService struct {
+	storage  StorageRepository
+	verifier TokenVerifier
+	signing  SigningOptions
+	clock    clock.Clock
+	logger   log.LoggerRepository
+}
+
+// CreateAPIKey returns the key in full only once, it can't be recovered from what's stored. Its signing secret,
+// when asked for, is in the returned APIKey.
+func (ucs *UseCaseService) CreateAPIKey(ctx context.Context, opts CreateAPIKeyOptions) (APIKey, string, error) {
+	if strings.TrimSpace(opts.Name) == "" {
+		return APIKey{}, "", account.NewDomainError(account.DomainInvalidAPIKeyErrorCode, "api key name is required")
+	}
+
+	if len(opts.Scopes) == 0 {
+		return APIKey{}, "", account.NewDomainError(account.DomainInvalidAPIKeyErrorCode, "api key needs at least one scope")
+	}
+
+	for _, scope := range opts.Scopes {
+		if _, err := ParseScope(string(scope)); err != nil {
+			return APIKey{}, "", account.NewDomainError(account.DomainInvalidAPIKeyErrorCode, err.Error())
+		}
+	}
+
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	raw := fmt.Sprintf("%s%s_%s", APIKeyPrefix, prefix, secret)
+	key := APIKey{
+		Name:      opts.Name,
+		Prefix:    prefix,
+		Hash:      hashAPIKey(raw),
+		Scopes:    opts.Scopes,
+		Tenant:    opts.Tenant,
+		CreatedAt: ucs.clock.Now(),
+	}
+
+	if opts.Signing {
+		key.SigningSecret, err = randomHex(signingSecretBytes)
+		if err != nil {
+			return APIKey{}, "", err
+		}
+	}
+
+	key.ID, err = ucs.storage.CreateAPIKey(ctx, key)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	return key, raw, nil
+}
+
+func (ucs *UseCaseService) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	return ucs.storage.ListAPIKeys(ctx)
+}
+
+func (ucs *UseCaseService) RevokeAPIKey(ctx context.Context, id uint) error {
+	return ucs.storage.RevokeAPIKey(ctx, id, ucs.clock.Now())
+}
+
+func (ucs *UseCaseService) apiKey(ctx context.Context, prefix string) (APIKey, error) {
+	key, err := ucs.storage.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		var ierr *account.InfraError
+		if errors.As(err, &ierr) && ierr.Code == account.InfraAPIKeyNotFoundErrorCode {
+			return APIKey{}, invalidCredentials("invalid api key")
+		}
+
+		return APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func principal(key APIKey) Principal {
+	return Principal{Kind: APIKeyPrincipalKind, ID: key.Prefix, Name: key.Name, Scopes: key.Scopes, Tenant: key.Tenant}
+}
+
+func (ucs *UseCaseService) authenticateAPIKey(ctx context.Context, raw string) (Principal, error) {
+	prefix, _, ok := strings.Cut(strings.TrimPrefix(raw, APIKeyPrefix), "_")
+	if !strings.HasPrefix(raw, APIKeyPrefix) || !ok || prefix == "" {
+		return Principal{}, invalidCredentials("malformed api key")
+	}
+
+	key, err := ucs.apiKey(ctx, prefix)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(raw)), []byte(key.Hash)) != 1 {
+		return Principal{}, invalidCredentials("invalid api key")
+	}
+
+	if key.Revoked() {
+		return Principal{}, invalidCredentials("api key revoked")
+	}
+
+	return principal(key), nil
+}
+
+// authenticateSignature checks the nonce only once the signature is valid, so forged requests can't use up the
+// nonces of legit ones.
+func (ucs *UseCaseService) authenticateSignature(ctx context.Context, sig Signature) (Principal, error) {
+	if ucs.signing.Window <= 0 || ucs.signing.Nonces == nil {
+		return Principal{}, invalidCredentials("signed requests aren't accepted")
+	}
+
+	if sig.KeyID == "" || sig.Nonce == "" || sig.MAC == "" {
+		return Principal{}, invalidCredentials("signature needs a key id, a nonce and a mac")
+	}
+
+	key, err := ucs.apiKey(ctx, sig.KeyID)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if key.SigningSecret == "" || !hmac.Equal([]byte(signing.MAC(key.SigningSecret, sig.Payload)), []byte(sig.MAC)) {
+		return Principal{}, invalidCredentials("invalid signature")
+	}
+
+	if key.Revoked() {
+		return Principal{}, invalidCredentials("api key revoked")
+	}
+
+	now := ucs.clock.Now()
+	if sig.Timestamp.Before(now.Add(-ucs.signing.Window)) || sig.Timestamp.After(now.Add(ucs.signing.Window)) {
+		return Principal{}, invalidCredentials(fmt.Sprintf("signature timestamp is more than %s away from now", ucs.signing.Window))
+	}
+
+	// Past the window the timestamp alone rejects the request, the nonce needn't be kept longer.
+	fresh, err := ucs.signing.Nonces.RememberNonce(ctx, fmt.Sprintf("%s:%s", key.Prefix, sig.Nonce), sig.Timestamp.Add(ucs.signing.Window))
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if !fresh {
+		return Principal{}, invalidCredentials("replayed request")
+	}
+
+	return principal(key), nil
+}
+
+func (ucs *UseCaseService) Authenticate(ctx context.Context, creds Credentials) (Principal, error) {
+	given := 0
+	for _, ok := range []bool{creds.APIKey != "", creds.Token != "", creds.Signature != nil} {
+		if ok {
+			given += 1
+		}
+	}
+
+	switch {
+	case given > 1:
+		return Principal{}, invalidCredentials("only one of an api key, a bearer token or a signature is expected")
+	case creds.Signature != nil:
+		return ucs.authenticateSignature(ctx, *creds.Signature)
+	case creds.APIKey != "":
+		return ucs.authenticateAPIKey(ctx, creds.APIKey)
+	case creds.Token != "":
+		if ucs.verifier == nil {
+			return Principal{}, invalidCredentials("bearer tokens aren't accepted")
+		}
+
+		// Why a token was turned down is only logged, telling the client would help forging one.
+		p, err := ucs.verifier.Verify(ctx, creds.Token)
+		if err != nil {
+			if ucs.logger != nil {
+				ucs.logger.Warn(ctx, fmt.Sprintf("invalid bearer token: %s", err))
+			}
+			return Principal{}, invalidCredentials("invalid bearer token")
+		}
+
+		return p, nil
+	default:
+		return Principal{}, invalidCredentials("missing credentials")
+	}
+}
+
+// NewUseCaseService takes a nil verifier when bearer tokens aren't accepted.
+func NewUseCaseService(storage StorageRepository, verifier TokenVerifier, signing SigningOptions, clock clock.Clock, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, verifier, signing, clock, logger}
+}
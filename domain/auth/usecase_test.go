@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/pkg/signing"
+)
+
+type FakeClock struct {
+	At time.Time
+}
+
+func (fc *FakeClock) Now() time.Time {
+	return fc.At
+}
+
+type MockStorageRepository struct {
+	Keys map[string]APIKey
+}
+
+func (msr *MockStorageRepository) CreateAPIKey(ctx context.Context, key APIKey) (uint, error) {
+	key.ID = uint(len(msr.Keys) + 1)
+	msr.Keys[key.Prefix] = key
+	return key.ID, nil
+}
+
+func (msr *MockStorageRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (APIKey, error) {
+	key, ok := msr.Keys[prefix]
+	if !ok {
+		return APIKey{}, account.NewInfraError(account.InfraAPIKeyNotFoundErrorCode, "api key not found")
+	}
+
+	return key, nil
+}
+
+func (msr *MockStorageRepository) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	keys := make([]APIKey, 0, len(msr.Keys))
+	for _, key := range msr.Keys {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (msr *MockStorageRepository) RevokeAPIKey(ctx context.Context, id uint, at time.Time) error {
+	for prefix, key := range msr.Keys {
+		if key.ID == id {
+			key.RevokedAt = at
+			msr.Keys[prefix] = key
+			return nil
+		}
+	}
+
+	return account.NewInfraError(account.InfraAPIKeyNotFoundErrorCode, "api key not found")
+}
+
+type MockTokenVerifier struct{}
+
+func (mtv MockTokenVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	if token != "valid" {
+		return Principal{}, errors.New("signature is invalid")
+	}
+
+	return Principal{Kind: TokenPrincipalKind, ID: "partner"}, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	storage := &MockStorageRepository{Keys: make(map[string]APIKey)}
+	usecase := NewUseCaseService(storage, MockTokenVerifier{}, SigningOptions{}, &FakeClock{At: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	ctx := context.Background()
+
+	key, raw, err := usecase.CreateAPIKey(ctx, CreateAPIKeyOptions{Name: "backoffice", Scopes: []Scope{ReadAccountsScope}, Tenant: "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(key.Hash, raw) || !strings.HasPrefix(raw, APIKeyPrefix+key.Prefix+"_") {
+		t.Fatalf("unexpected api key %q for prefix %q", raw, key.Prefix)
+	}
+
+	revoked, revokedRaw, err := usecase.CreateAPIKey(ctx, CreateAPIKeyOptions{Name: "retired", Scopes: Scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := usecase.RevokeAPIKey(ctx, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	suite := []struct {
+		Name        string
+		Credentials Credentials
+		Expected    Principal
+		Err         bool
+	}{
+		{"Valid API key", Credentials{APIKey: raw}, Principal{Kind: APIKeyPrincipalKind, ID: key.Prefix, Name: "backoffice", Scopes: []Scope{ReadAccountsScope}, Tenant: "acme"}, false},
+		{"Wrong secret", Credentials{APIKey: APIKeyPrefix + key.Prefix + "_deadbeef"}, Principal{}, true},
+		{"Unknown prefix", Credentials{APIKey: APIKeyPrefix + "000000000000_deadbeef"}, Principal{}, true},
+		{"Malformed API key", Credentials{APIKey: "secret"}, Principal{}, true},
+		{"Revoked API key", Credentials{APIKey: revokedRaw}, Principal{}, true},
+		{"Valid token", Credentials{Token: "valid"}, Principal{Kind: TokenPrincipalKind, ID: "partner"}, false},
+		{"Invalid token", Credentials{Token: "forged"}, Principal{}, true},
+		{"Both credentials", Credentials{APIKey: raw, Token: "valid"}, Principal{}, true},
+		{"No credentials", Credentials{}, Principal{}, true},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			got, err := usecase.Authenticate(ctx, s.Credentials)
+			if s.Err {
+				var derr *account.DomainError
+				if !errors.As(err, &derr) || derr.Code != account.DomainInvalidCredentialsErrorCode {
+					t.Errorf("unexpected error, got: %v, expected: %v", err, account.DomainInvalidCredentialsErrorCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if expected := s.Expected; !reflect.DeepEqual(got, expected) {
+				t.Errorf("unexpected principal, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyScopes(t *testing.T) {
+	usecase := NewUseCaseService(&MockStorageRepository{Keys: make(map[string]APIKey)}, nil, SigningOptions{}, &FakeClock{}, nil)
+
+	suite := []struct {
+		Name   string
+		Scopes []Scope
+		Err    bool
+	}{
+		{"Every scope", Scopes, false},
+		{"Single scope", []Scope{WriteTransactionsScope}, false},
+		{"Without scopes", nil, true},
+		{"Unknown scope", []Scope{ReadAccountsScope, "accounts:delete"}, true},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			_, _, err := usecase.CreateAPIKey(context.Background(), CreateAPIKeyOptions{Name: "partner", Scopes: s.Scopes})
+			if !s.Err {
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+
+			var derr *account.DomainError
+			if !errors.As(err, &derr) || derr.Code != account.DomainInvalidAPIKeyErrorCode {
+				t.Errorf("unexpected error, got: %v, expected: %v", err, account.DomainInvalidAPIKeyErrorCode)
+				return
+			}
+		})
+	}
+}
+
+type MockNonceStore struct {
+	Nonces map[string]time.Time
+}
+
+func (mns *MockNonceStore) RememberNonce(ctx context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	if _, ok := mns.Nonces[nonce]; ok {
+		return false, nil
+	}
+	mns.Nonces[nonce] = expiresAt
+
+	return true, nil
+}
+
+func TestAuthenticateSignature(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	storage := &MockStorageRepository{Keys: make(map[string]APIKey)}
+	nonces := &MockNonceStore{Nonces: make(map[string]time.Time)}
+	usecase := NewUseCaseService(storage, nil, SigningOptions{Window: 5 * time.Minute, Nonces: nonces}, &FakeClock{At: now}, nil)
+	ctx := context.Background()
+
+	key, _, err := usecase.CreateAPIKey(ctx, CreateAPIKeyOptions{Name: "partner", Scopes: Scopes, Tenant: "acme", Signing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned, _, err := usecase.CreateAPIKey(ctx, CreateAPIKeyOptions{Name: "backoffice", Scopes: Scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte("GET\n/api/v1/accounts/1")
+	signed := func(keyID, secret, nonce string, at time.Time) Credentials {
+		return Credentials{Signature: &Signature{KeyID: keyID, Timestamp: at, Nonce: nonce, Payload: payload, MAC: signing.MAC(secret, payload)}}
+	}
+
+	suite := []struct {
+		Name        string
+		Credentials Credentials
+		Err         bool
+	}{
+		{"Valid signature", signed(key.Prefix, key.SigningSecret, "n1", now), false},
+		{"Replayed nonce", signed(key.Prefix, key.SigningSecret, "n1", now), true},
+		{"Timestamp within the window", signed(key.Prefix, key.SigningSecret, "n2", now.Add(-4*time.Minute)), false},
+		{"Timestamp too old", signed(key.Prefix, key.SigningSecret, "n3", now.Add(-6*time.Minute)), true},
+		{"Timestamp too far ahead", signed(key.Prefix, key.SigningSecret, "n4", now.Add(6*time.Minute)), true},
+		{"Wrong secret", signed(key.Prefix, "forged", "n5", now), true},
+		{"Key without signing secret", signed(unsigned.Prefix, "", "n6", now), true},
+		{"Unknown key", signed("000000000000", key.SigningSecret, "n7", now), true},
+		{"Without nonce", signed(key.Prefix, key.SigningSecret, "", now), true},
+		{"Signature and API key", Credentials{APIKey: "bank_a1b2_secret", Signature: signed(key.Prefix, key.SigningSecret, "n8", now).Signature}, true},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			got, err := usecase.Authenticate(ctx, s.Credentials)
+			if s.Err {
+				var derr *account.DomainError
+				if !errors.As(err, &derr) || derr.Code != account.DomainInvalidCredentialsErrorCode {
+					t.Errorf("unexpected error, got: %v, expected: %v", err, account.DomainInvalidCredentialsErrorCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got, expected := got.Tenant, "acme"; got != expected {
+				t.Errorf("unexpected tenant, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+
+	if _, ok := nonces.Nonces[key.Prefix+":n5"]; ok {
+		t.Error("unexpected nonce remembered for a forged signature")
+	}
+}
+
+type MockLoggerRepository struct {
+	log.LoggerRepository
+
+	Warnings []string
+}
+
+func (mlr *MockLoggerRepository) Warn(ctx context.Context, msg string) {
+	mlr.Warnings = append(mlr.Warnings, msg)
+}
+
+func TestAuthenticateInvalidTokenDetail(t *testing.T) {
+	logger := &MockLoggerRepository{}
+	usecase := NewUseCaseService(&MockStorageRepository{Keys: make(map[string]APIKey)}, MockTokenVerifier{}, SigningOptions{}, &FakeClock{}, logger)
+
+	_, err := usecase.Authenticate(context.Background(), Credentials{Token: "forged"})
+	if got, expected := err.Error(), "invalid bearer token"; got != expected {
+		t.Errorf("unexpected detail, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := logger.Warnings, []string{"invalid bearer token: signature is invalid"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected warnings, got: %v, expected: %v", got, expected)
+		return
+	}
+}
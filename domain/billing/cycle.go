@@ -0,0 +1,48 @@
+package billing
+
+import "time"
+
+// Cycle is the billing period [Start, End) closed at End, which is always a closing day at midnight UTC.
+type Cycle struct {
+	Start   time.Time
+	End     time.Time
+	DueDate time.Time
+
+	closingDay int
+	dueDay     int
+}
+
+func closingDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func dueDateAfter(end time.Time, dueDay int) time.Time {
+	due := closingDate(end.Year(), end.Month(), dueDay)
+	if !due.After(end) {
+		due = due.AddDate(0, 1, 0)
+	}
+
+	return due
+}
+
+func NewCycle(at time.Time, closingDay, dueDay int) Cycle {
+	at = at.UTC()
+
+	end := closingDate(at.Year(), at.Month(), closingDay)
+	if !end.After(at) {
+		end = end.AddDate(0, 1, 0)
+	}
+	start := end.AddDate(0, -1, 0)
+
+	return Cycle{
+		Start:      start,
+		End:        end,
+		DueDate:    dueDateAfter(end, dueDay),
+		closingDay: closingDay,
+		dueDay:     dueDay,
+	}
+}
+
+func (c Cycle) Next() Cycle {
+	return NewCycle(c.End, c.closingDay, c.dueDay)
+}
@@ -0,0 +1,66 @@
+package billing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCycle(t *testing.T) {
+	suite := []struct {
+		At              time.Time
+		ClosingDay      int
+		DueDay          int
+		ExpectedStart   time.Time
+		ExpectedEnd     time.Time
+		ExpectedDueDate time.Time
+	}{
+		{
+			At:              time.Date(2023, time.March, 15, 10, 0, 0, 0, time.UTC),
+			ClosingDay:      1,
+			DueDay:          10,
+			ExpectedStart:   time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			ExpectedEnd:     time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC),
+			ExpectedDueDate: time.Date(2023, time.April, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			At:              time.Date(2023, time.March, 20, 0, 0, 0, 0, time.UTC),
+			ClosingDay:      20,
+			DueDay:          5,
+			ExpectedStart:   time.Date(2023, time.March, 20, 0, 0, 0, 0, time.UTC),
+			ExpectedEnd:     time.Date(2023, time.April, 20, 0, 0, 0, 0, time.UTC),
+			ExpectedDueDate: time.Date(2023, time.May, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			At:              time.Date(2023, time.December, 31, 23, 59, 0, 0, time.UTC),
+			ClosingDay:      28,
+			DueDay:          28,
+			ExpectedStart:   time.Date(2023, time.December, 28, 0, 0, 0, 0, time.UTC),
+			ExpectedEnd:     time.Date(2024, time.January, 28, 0, 0, 0, 0, time.UTC),
+			ExpectedDueDate: time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, s := range suite {
+		cycle := NewCycle(s.At, s.ClosingDay, s.DueDay)
+
+		if got, expected := cycle.Start, s.ExpectedStart; !got.Equal(expected) {
+			t.Errorf("unexpected cycle start, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := cycle.End, s.ExpectedEnd; !got.Equal(expected) {
+			t.Errorf("unexpected cycle end, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := cycle.DueDate, s.ExpectedDueDate; !got.Equal(expected) {
+			t.Errorf("unexpected cycle due date, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := cycle.Next().Start, s.ExpectedEnd; !got.Equal(expected) {
+			t.Errorf("unexpected next cycle start, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
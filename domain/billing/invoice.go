@@ -0,0 +1,41 @@
+package billing
+
+import "time"
+
+type Status string
+
+const (
+	OpenStatus    Status = "open"
+	ClosedStatus  Status = "closed"
+	PaidStatus    Status = "paid"
+	OverdueStatus Status = "overdue"
+)
+
+type InvoiceTotal struct {
+	OperationTypeID uint
+	Amount          int64
+}
+
+type Invoice struct {
+	ID          uint
+	AccountID   uint
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	DueDate     time.Time
+	Status      Status
+	Totals      []InvoiceTotal
+	Total       int64
+	PaidAmount  int64
+	ClosedAt    *time.Time
+	PaidAt      *time.Time
+}
+
+// AmountDue is what the holder owes for the cycle, purchases and withdrawals are stored
+// as negative amounts so a positive net means there's nothing to pay.
+func (inv Invoice) AmountDue() int64 {
+	if inv.Total >= 0 {
+		return 0
+	}
+
+	return -inv.Total
+}
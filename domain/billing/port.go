@@ -0,0 +1,35 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type CloseInvoicesOptions struct {
+	At time.Time
+}
+
+type CloseInvoicesResult struct {
+	Closed  int
+	Paid    int
+	Overdue int
+}
+
+type StorageRepository interface {
+	ListAccounts(context.Context) ([]account.Account, error)
+	GetAccountByID(context.Context, uint) (account.Account, error)
+	SumTransactionsByOperationType(ctx context.Context, accountID uint, from, to time.Time) ([]InvoiceTotal, error)
+	SaveInvoice(context.Context, Invoice) (uint, error)
+	GetInvoiceByID(context.Context, uint) (Invoice, error)
+	GetLatestInvoiceByAccountID(context.Context, uint) (Invoice, error)
+	ListInvoicesByAccountID(context.Context, uint) ([]Invoice, error)
+	ListInvoicesByStatus(context.Context, ...Status) ([]Invoice, error)
+}
+
+type UseCase interface {
+	CloseInvoices(context.Context, CloseInvoicesOptions) (CloseInvoicesResult, error)
+	GetInvoiceByID(context.Context, uint) (Invoice, error)
+	ListInvoicesByAccountID(context.Context, uint) ([]Invoice, error)
+}
@@ -0,0 +1,41 @@
+package billing
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/trace"
+)
+
+// TracedUseCase starts a span around every call to the wrapped use case.
+type TracedUseCase struct {
+	UseCase
+	tracer trace.Tracer
+}
+
+func (tuc *TracedUseCase) CloseInvoices(ctx context.Context, opts CloseInvoicesOptions) (CloseInvoicesResult, error) {
+	ctx, span := tuc.tracer.Start(ctx, "billing.CloseInvoices")
+	result, err := tuc.UseCase.CloseInvoices(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) GetInvoiceByID(ctx context.Context, invoiceID uint) (Invoice, error) {
+	ctx, span := tuc.tracer.Start(ctx, "billing.GetInvoiceByID")
+	result, err := tuc.UseCase.GetInvoiceByID(ctx, invoiceID)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) ListInvoicesByAccountID(ctx context.Context, accountID uint) ([]Invoice, error) {
+	ctx, span := tuc.tracer.Start(ctx, "billing.ListInvoicesByAccountID")
+	result, err := tuc.UseCase.ListInvoicesByAccountID(ctx, accountID)
+	span.End(err)
+
+	return result, err
+}
+
+func NewTracedUseCase(usecase UseCase, tracer trace.Tracer) *TracedUseCase {
+	return &TracedUseCase{usecase, tracer}
+}
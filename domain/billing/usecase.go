@@ -0,0 +1,225 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+)
+
+type UseCaseService struct {
+	storage StorageRepository
+	logger  log.LoggerRepository
+}
+
+// Payments are taken out of the invoice total because they settle the previous cycle, not the current one.
+func chargesTotal(totals []InvoiceTotal) int64 {
+	var total int64
+	for _, t := range totals {
+		if t.OperationTypeID == account.PaymentOperationTypeID {
+			continue
+		}
+		total += t.Amount
+	}
+
+	return total
+}
+
+func paymentsTotal(totals []InvoiceTotal) int64 {
+	var total int64
+	for _, t := range totals {
+		if t.OperationTypeID == account.PaymentOperationTypeID {
+			total += t.Amount
+		}
+	}
+
+	return total
+}
+
+func (ucs *UseCaseService) currentCycle(ctx context.Context, acc account.Account) (Cycle, error) {
+	latest, err := ucs.storage.GetLatestInvoiceByAccountID(ctx, acc.ID)
+	if err != nil {
+		if cerr, ok := err.(*account.InfraError); ok && cerr.Code == account.InfraInvoiceNotFoundErrorCode {
+			return NewCycle(acc.CreatedAt, acc.ClosingDay, acc.DueDay), nil
+		}
+
+		return Cycle{}, err
+	}
+
+	if latest.Status == OpenStatus {
+		return NewCycle(latest.PeriodStart, acc.ClosingDay, acc.DueDay), nil
+	}
+
+	return NewCycle(latest.PeriodEnd, acc.ClosingDay, acc.DueDay), nil
+}
+
+func (ucs *UseCaseService) closeCycles(ctx context.Context, acc account.Account, at time.Time) (int, error) {
+	cycle, err := ucs.currentCycle(ctx, acc)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for {
+		totals, err := ucs.storage.SumTransactionsByOperationType(ctx, acc.ID, cycle.Start, cycle.End)
+		if err != nil {
+			return closed, err
+		}
+
+		inv := Invoice{
+			AccountID:   acc.ID,
+			PeriodStart: cycle.Start,
+			PeriodEnd:   cycle.End,
+			DueDate:     cycle.DueDate,
+			Status:      OpenStatus,
+			Totals:      totals,
+			Total:       chargesTotal(totals),
+		}
+
+		if cycle.End.After(at) {
+			if _, err := ucs.storage.SaveInvoice(ctx, inv); err != nil {
+				return closed, err
+			}
+
+			return closed, nil
+		}
+
+		closedAt := cycle.End
+		inv.Status = ClosedStatus
+		inv.ClosedAt = &closedAt
+		if _, err := ucs.storage.SaveInvoice(ctx, inv); err != nil {
+			return closed, err
+		}
+		closed += 1
+
+		cycle = cycle.Next()
+	}
+}
+
+// Payments made during the cycle right after the closing one are the ones that settle the invoice.
+func (ucs *UseCaseService) settle(ctx context.Context, acc account.Account, inv Invoice, at time.Time) (Status, error) {
+	status := inv.Status
+
+	if due := inv.AmountDue(); due == 0 {
+		inv.Status = PaidStatus
+	} else {
+		to := NewCycle(inv.PeriodEnd, acc.ClosingDay, acc.DueDay).End
+		if at.Before(to) {
+			to = at
+		}
+
+		totals, err := ucs.storage.SumTransactionsByOperationType(ctx, acc.ID, inv.PeriodEnd, to)
+		if err != nil {
+			return status, err
+		}
+		inv.PaidAmount = paymentsTotal(totals)
+
+		if inv.PaidAmount >= due {
+			inv.Status = PaidStatus
+		} else if !at.Before(inv.DueDate.AddDate(0, 0, 1)) {
+			inv.Status = OverdueStatus
+		}
+	}
+
+	if inv.Status == PaidStatus {
+		paidAt := at
+		inv.PaidAt = &paidAt
+	}
+
+	if _, err := ucs.storage.SaveInvoice(ctx, inv); err != nil {
+		return status, err
+	}
+
+	return inv.Status, nil
+}
+
+func (ucs *UseCaseService) CloseInvoices(ctx context.Context, opts CloseInvoicesOptions) (CloseInvoicesResult, error) {
+	result := CloseInvoicesResult{}
+
+	accs, err := ucs.storage.ListAccounts(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	accsByID := make(map[uint]account.Account, len(accs))
+	for _, acc := range accs {
+		accsByID[acc.ID] = acc
+
+		closed, err := ucs.closeCycles(ctx, acc, opts.At)
+		result.Closed += closed
+		if err != nil {
+			return result, err
+		}
+	}
+
+	invs, err := ucs.storage.ListInvoicesByStatus(ctx, ClosedStatus, OverdueStatus)
+	if err != nil {
+		return result, err
+	}
+
+	for _, inv := range invs {
+		acc, ok := accsByID[inv.AccountID]
+		if !ok {
+			continue
+		}
+
+		status, err := ucs.settle(ctx, acc, inv, opts.At)
+		if err != nil {
+			return result, err
+		}
+
+		if status == inv.Status {
+			continue
+		}
+
+		switch status {
+		case PaidStatus:
+			result.Paid += 1
+		case OverdueStatus:
+			result.Overdue += 1
+		}
+	}
+
+	return result, nil
+}
+
+func (ucs *UseCaseService) GetInvoiceByID(ctx context.Context, invoiceID uint) (Invoice, error) {
+	inv, err := ucs.storage.GetInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	acc, err := ucs.storage.GetAccountByID(ctx, inv.AccountID)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	if err := account.Authorize(ctx, acc); err != nil {
+		return Invoice{}, err
+	}
+
+	return inv, nil
+}
+
+func (ucs *UseCaseService) ListInvoicesByAccountID(ctx context.Context, accountID uint) ([]Invoice, error) {
+	acc, err := ucs.storage.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := account.Authorize(ctx, acc); err != nil {
+		return nil, err
+	}
+
+	invs, err := ucs.storage.ListInvoicesByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return invs, nil
+}
+
+func NewUseCaseService(storage StorageRepository, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, logger}
+}
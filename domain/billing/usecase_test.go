@@ -0,0 +1,255 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type MockTransaction struct {
+	AccountID       uint
+	OperationTypeID uint
+	Amount          int64
+	EventDate       time.Time
+}
+
+type MockStorageRepository struct {
+	Accounts     []account.Account
+	Transactions []MockTransaction
+	Invoices     []Invoice
+
+	NCalledSaveInvoice int
+}
+
+func (msr *MockStorageRepository) ListAccounts(ctx context.Context) ([]account.Account, error) {
+	return msr.Accounts, nil
+}
+
+func (msr *MockStorageRepository) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	for _, acc := range msr.Accounts {
+		if acc.ID == accountID {
+			return acc, nil
+		}
+	}
+
+	return account.Account{}, account.NewInfraError(account.InfraAccountNotFoundErrorCode, "account not found")
+}
+
+func (msr *MockStorageRepository) SumTransactionsByOperationType(ctx context.Context, accountID uint, from, to time.Time) ([]InvoiceTotal, error) {
+	totals := make([]InvoiceTotal, 0)
+	for _, tx := range msr.Transactions {
+		if tx.AccountID != accountID || tx.EventDate.Before(from) || !tx.EventDate.Before(to) {
+			continue
+		}
+		totals = append(totals, InvoiceTotal{OperationTypeID: tx.OperationTypeID, Amount: tx.Amount})
+	}
+
+	return totals, nil
+}
+
+func (msr *MockStorageRepository) SaveInvoice(ctx context.Context, inv Invoice) (uint, error) {
+	msr.NCalledSaveInvoice += 1
+
+	for i, stored := range msr.Invoices {
+		if stored.AccountID == inv.AccountID && stored.PeriodStart.Equal(inv.PeriodStart) {
+			inv.ID = stored.ID
+			msr.Invoices[i] = inv
+			return inv.ID, nil
+		}
+	}
+
+	inv.ID = uint(len(msr.Invoices) + 1)
+	msr.Invoices = append(msr.Invoices, inv)
+
+	return inv.ID, nil
+}
+
+func (msr *MockStorageRepository) GetInvoiceByID(ctx context.Context, invoiceID uint) (Invoice, error) {
+	for _, inv := range msr.Invoices {
+		if inv.ID == invoiceID {
+			return inv, nil
+		}
+	}
+
+	return Invoice{}, account.NewInfraError(account.InfraInvoiceNotFoundErrorCode, "invoice not found")
+}
+
+func (msr *MockStorageRepository) GetLatestInvoiceByAccountID(ctx context.Context, accountID uint) (Invoice, error) {
+	var latest *Invoice
+	for i, inv := range msr.Invoices {
+		if inv.AccountID == accountID && (latest == nil || inv.PeriodStart.After(latest.PeriodStart)) {
+			latest = &msr.Invoices[i]
+		}
+	}
+
+	if latest == nil {
+		return Invoice{}, account.NewInfraError(account.InfraInvoiceNotFoundErrorCode, "invoice not found")
+	}
+
+	return *latest, nil
+}
+
+func (msr *MockStorageRepository) ListInvoicesByAccountID(ctx context.Context, accountID uint) ([]Invoice, error) {
+	invs := make([]Invoice, 0)
+	for _, inv := range msr.Invoices {
+		if inv.AccountID == accountID {
+			invs = append(invs, inv)
+		}
+	}
+
+	return invs, nil
+}
+
+func (msr *MockStorageRepository) ListInvoicesByStatus(ctx context.Context, statuses ...Status) ([]Invoice, error) {
+	invs := make([]Invoice, 0)
+	for _, inv := range msr.Invoices {
+		for _, status := range statuses {
+			if inv.Status == status {
+				invs = append(invs, inv)
+			}
+		}
+	}
+
+	return invs, nil
+}
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2023, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestCloseInvoices(t *testing.T) {
+	suite := []struct {
+		Describe         string
+		Transactions     []MockTransaction
+		At               time.Time
+		ExpectedStatuses []Status
+		ExpectedTotals   []int64
+		ExpectedResult   CloseInvoicesResult
+	}{
+		{
+			Describe:         "Only the open invoice when cycle isn't over",
+			Transactions:     []MockTransaction{{1, account.CashPurchaseOperationTypeID, -50_00, date(time.January, 10)}},
+			At:               date(time.January, 20),
+			ExpectedStatuses: []Status{OpenStatus},
+			ExpectedTotals:   []int64{-50_00},
+			ExpectedResult:   CloseInvoicesResult{},
+		},
+		{
+			Describe:         "Closed invoice and opened the next one",
+			Transactions:     []MockTransaction{{1, account.CashPurchaseOperationTypeID, -50_00, date(time.January, 10)}},
+			At:               date(time.February, 3),
+			ExpectedStatuses: []Status{ClosedStatus, OpenStatus},
+			ExpectedTotals:   []int64{-50_00, 0},
+			ExpectedResult:   CloseInvoicesResult{Closed: 1},
+		},
+		{
+			Describe:         "Overdue invoice after due date without payment",
+			Transactions:     []MockTransaction{{1, account.CashPurchaseOperationTypeID, -50_00, date(time.January, 10)}},
+			At:               date(time.February, 11),
+			ExpectedStatuses: []Status{OverdueStatus, OpenStatus},
+			ExpectedTotals:   []int64{-50_00, 0},
+			ExpectedResult:   CloseInvoicesResult{Closed: 1, Overdue: 1},
+		},
+		{
+			Describe: "Paid invoice when payment covers the amount due",
+			Transactions: []MockTransaction{
+				{1, account.CashPurchaseOperationTypeID, -50_00, date(time.January, 10)},
+				{1, account.WithdrawalOperationTypeID, -20_00, date(time.January, 11)},
+				{1, account.PaymentOperationTypeID, 70_00, date(time.February, 5)},
+			},
+			At:               date(time.February, 11),
+			ExpectedStatuses: []Status{PaidStatus, OpenStatus},
+			ExpectedTotals:   []int64{-70_00, 0},
+			ExpectedResult:   CloseInvoicesResult{Closed: 1, Paid: 1},
+		},
+		{
+			Describe:         "Paid invoice when there's nothing to pay",
+			Transactions:     []MockTransaction{},
+			At:               date(time.February, 3),
+			ExpectedStatuses: []Status{PaidStatus, OpenStatus},
+			ExpectedTotals:   []int64{0, 0},
+			ExpectedResult:   CloseInvoicesResult{Closed: 1, Paid: 1},
+		},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Describe, func(t *testing.T) {
+			mock := &MockStorageRepository{
+				Accounts: []account.Account{
+					{ID: 1, ClosingDay: 1, DueDay: 10, CreatedAt: date(time.January, 2)},
+				},
+				Transactions: s.Transactions,
+			}
+			svc := &UseCaseService{storage: mock}
+
+			result, err := svc.CloseInvoices(context.Background(), CloseInvoicesOptions{At: s.At})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got, expected := result, s.ExpectedResult; got != expected {
+				t.Errorf("unexpected result, got: %+v, expected: %+v", got, expected)
+				return
+			}
+
+			if got, expected := len(mock.Invoices), len(s.ExpectedStatuses); got != expected {
+				t.Errorf("unexpected number of invoices, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			for i, inv := range mock.Invoices {
+				if got, expected := inv.Status, s.ExpectedStatuses[i]; got != expected {
+					t.Errorf("unexpected invoice status, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := inv.Total, s.ExpectedTotals[i]; got != expected {
+					t.Errorf("unexpected invoice total, got: %v, expected: %v", got, expected)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestCloseInvoicesReplayed(t *testing.T) {
+	mock := &MockStorageRepository{
+		Accounts: []account.Account{
+			{ID: 1, ClosingDay: 1, DueDay: 10, CreatedAt: date(time.January, 2)},
+		},
+		Transactions: []MockTransaction{{1, account.CashPurchaseOperationTypeID, -50_00, date(time.January, 10)}},
+	}
+	svc := &UseCaseService{storage: mock}
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.CloseInvoices(context.Background(), CloseInvoicesOptions{At: date(time.March, 3)}); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if got, expected := len(mock.Invoices), 3; got != expected {
+		t.Errorf("unexpected number of invoices, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestListInvoicesByAccountIDWithAccountNotFound(t *testing.T) {
+	mock := &MockStorageRepository{}
+	svc := &UseCaseService{storage: mock}
+
+	_, err := svc.ListInvoicesByAccountID(context.Background(), 20)
+	cerr, ok := err.(*account.InfraError)
+	if !ok {
+		t.Error("unexpected error")
+		return
+	}
+
+	if got, expected := cerr.Code, account.InfraAccountNotFoundErrorCode; got != expected {
+		t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package charges
+
+import "time"
+
+type Config struct {
+	MonthlyInterestRate Rate
+	LateFee             int64
+	IOFRate             Rate
+}
+
+var DefaultConfig = Config{
+	MonthlyInterestRate: 140_000,
+	LateFee:             5_00,
+	IOFRate:             3_800,
+}
+
+// Key identifies a charge so generating it again for the same date never duplicates it.
+type Charge struct {
+	Key             string
+	AccountID       uint
+	OperationTypeID uint
+	Amount          int64
+	EventDate       time.Time
+}
@@ -0,0 +1,109 @@
+package charges
+
+import (
+	"fmt"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/billing"
+)
+
+const daysPerMonth = 30
+
+type EngineInput struct {
+	AccountID    uint
+	Transactions []account.Transaction
+	Invoices     []billing.Invoice
+	At           time.Time
+}
+
+type Engine struct {
+	config Config
+}
+
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (e *Engine) iof(input EngineInput) []Charge {
+	charges := make([]Charge, 0)
+	for _, tx := range input.Transactions {
+		if tx.OperationTypeID != account.WithdrawalOperationTypeID || tx.EventDate.After(input.At) {
+			continue
+		}
+
+		amount := tx.Amount
+		if amount < 0 {
+			amount = -amount
+		}
+
+		if fee := e.config.IOFRate.Apply(amount); fee > 0 {
+			charges = append(charges, Charge{
+				Key:             fmt.Sprintf("iof:%d", tx.ID),
+				AccountID:       input.AccountID,
+				OperationTypeID: account.IOFOperationTypeID,
+				Amount:          -fee,
+				EventDate:       tx.EventDate,
+			})
+		}
+	}
+
+	return charges
+}
+
+func (e *Engine) overdue(input EngineInput) []Charge {
+	charges := make([]Charge, 0)
+	for _, inv := range input.Invoices {
+		if inv.Status != billing.OverdueStatus {
+			continue
+		}
+
+		unpaid := inv.AmountDue() - inv.PaidAmount
+		if unpaid <= 0 {
+			continue
+		}
+
+		firstLateDay := day(inv.DueDate).AddDate(0, 0, 1)
+		if firstLateDay.After(input.At) {
+			continue
+		}
+
+		if e.config.LateFee > 0 {
+			charges = append(charges, Charge{
+				Key:             fmt.Sprintf("late-fee:%d", inv.ID),
+				AccountID:       input.AccountID,
+				OperationTypeID: account.LateFeeOperationTypeID,
+				Amount:          -e.config.LateFee,
+				EventDate:       firstLateDay,
+			})
+		}
+
+		interest := e.config.MonthlyInterestRate.Prorate(unpaid, 1, daysPerMonth)
+		if interest == 0 {
+			continue
+		}
+
+		for d := firstLateDay; !d.After(input.At); d = d.AddDate(0, 0, 1) {
+			charges = append(charges, Charge{
+				Key:             fmt.Sprintf("interest:%d:%s", inv.ID, d.Format("2006-01-02")),
+				AccountID:       input.AccountID,
+				OperationTypeID: account.InterestOperationTypeID,
+				Amount:          -interest,
+				EventDate:       d,
+			})
+		}
+	}
+
+	return charges
+}
+
+// Charges lists every charge due until input.At, the result only depends on the input
+// so replaying it for the same date always yields the same charges.
+func (e *Engine) Charges(input EngineInput) []Charge {
+	return append(e.iof(input), e.overdue(input)...)
+}
+
+func NewEngine(config Config) *Engine {
+	return &Engine{config}
+}
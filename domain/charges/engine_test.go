@@ -0,0 +1,121 @@
+package charges
+
+import (
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/billing"
+)
+
+func TestRateProrate(t *testing.T) {
+	suite := []struct {
+		Rate     Rate
+		Amount   int64
+		Num      int64
+		Den      int64
+		Expected int64
+	}{
+		{Rate: 3_800, Amount: 100_00, Num: 1, Den: 1, Expected: 38},
+		{Rate: 140_000, Amount: 100_00, Num: 1, Den: 30, Expected: 47},
+		{Rate: 140_000, Amount: 100_00, Num: 30, Den: 30, Expected: 14_00},
+		{Rate: 3_800, Amount: 1, Num: 1, Den: 1, Expected: 0},
+		{Rate: 3_800, Amount: -100_00, Num: 1, Den: 1, Expected: 0},
+	}
+
+	for _, s := range suite {
+		if got, expected := s.Rate.Prorate(s.Amount, s.Num, s.Den), s.Expected; got != expected {
+			t.Errorf("unexpected prorated amount for %d, got: %v, expected: %v", s.Amount, got, expected)
+			return
+		}
+	}
+}
+
+func TestEngineCharges(t *testing.T) {
+	overdue := billing.Invoice{
+		ID:         9,
+		AccountID:  1,
+		DueDate:    time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+		Status:     billing.OverdueStatus,
+		Total:      -150_00,
+		PaidAmount: 50_00,
+	}
+	withdrawal := account.Transaction{
+		ID:              3,
+		AccountID:       1,
+		OperationTypeID: account.WithdrawalOperationTypeID,
+		Amount:          -200_00,
+		EventDate:       time.Date(2023, time.February, 12, 9, 30, 0, 0, time.UTC),
+	}
+
+	suite := []struct {
+		Describe     string
+		At           time.Time
+		Transactions []account.Transaction
+		Invoices     []billing.Invoice
+		ExpectedKeys []string
+		ExpectedSum  int64
+	}{
+		{
+			Describe:     "Nothing charged before due date",
+			At:           time.Date(2023, time.February, 10, 23, 59, 0, 0, time.UTC),
+			Invoices:     []billing.Invoice{overdue},
+			ExpectedKeys: []string{},
+		},
+		{
+			Describe:     "Late fee and daily interest after due date",
+			At:           time.Date(2023, time.February, 12, 8, 0, 0, 0, time.UTC),
+			Invoices:     []billing.Invoice{overdue},
+			ExpectedKeys: []string{"late-fee:9", "interest:9:2023-02-11", "interest:9:2023-02-12"},
+			ExpectedSum:  -(5_00 + 47 + 47),
+		},
+		{
+			Describe:     "IOF only after the withdrawal happened",
+			At:           time.Date(2023, time.February, 12, 8, 0, 0, 0, time.UTC),
+			Transactions: []account.Transaction{withdrawal},
+			ExpectedKeys: []string{},
+		},
+		{
+			Describe:     "IOF on withdrawal",
+			At:           time.Date(2023, time.February, 12, 10, 0, 0, 0, time.UTC),
+			Transactions: []account.Transaction{withdrawal},
+			ExpectedKeys: []string{"iof:3"},
+			ExpectedSum:  -76,
+		},
+		{
+			Describe: "Nothing charged for paid invoices",
+			At:       time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			Invoices: []billing.Invoice{
+				{ID: 10, DueDate: overdue.DueDate, Status: billing.PaidStatus, Total: -150_00, PaidAmount: 150_00},
+			},
+			ExpectedKeys: []string{},
+		},
+	}
+
+	engine := NewEngine(DefaultConfig)
+	for _, s := range suite {
+		t.Run(s.Describe, func(t *testing.T) {
+			input := EngineInput{AccountID: 1, Transactions: s.Transactions, Invoices: s.Invoices, At: s.At}
+
+			charges := engine.Charges(input)
+			if got, expected := len(charges), len(s.ExpectedKeys); got != expected {
+				t.Errorf("unexpected number of charges, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			var sum int64
+			for i, charge := range charges {
+				if got, expected := charge.Key, s.ExpectedKeys[i]; got != expected {
+					t.Errorf("unexpected charge key, got: %v, expected: %v", got, expected)
+					return
+				}
+				sum += charge.Amount
+			}
+
+			if got, expected := sum, s.ExpectedSum; got != expected {
+				t.Errorf("unexpected charges sum, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package charges
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/billing"
+)
+
+type ApplyChargesOptions struct {
+	AccountID uint
+	At        time.Time
+}
+
+type ApplyChargesResult struct {
+	Created int
+	Skipped int
+}
+
+type StorageRepository interface {
+	ListAccounts(context.Context) ([]account.Account, error)
+	GetAccountByID(context.Context, uint) (account.Account, error)
+	ListTransactionsByAccountID(ctx context.Context, accountID uint, from, to time.Time) ([]account.Transaction, error)
+	ListInvoicesByAccountID(context.Context, uint) ([]billing.Invoice, error)
+	CreateTransaction(context.Context, account.CreateTransactionOptions) (uint, error)
+}
+
+type UseCase interface {
+	ApplyCharges(context.Context, ApplyChargesOptions) (ApplyChargesResult, error)
+}
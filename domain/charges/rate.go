@@ -0,0 +1,21 @@
+package charges
+
+// Rate is expressed in parts per million so 14% is 140_000 and 0.38% is 3_800,
+// keeping every charge computed with integer arithmetic.
+type Rate int64
+
+const ratePrecision = 1_000_000
+
+// Prorate applies the rate over amount scaled by num/den rounding half up.
+func (r Rate) Prorate(amount, num, den int64) int64 {
+	if amount <= 0 || r <= 0 || num <= 0 || den <= 0 {
+		return 0
+	}
+
+	divisor := ratePrecision * den
+	return (amount*int64(r)*num + divisor/2) / divisor
+}
+
+func (r Rate) Apply(amount int64) int64 {
+	return r.Prorate(amount, 1, 1)
+}
@@ -0,0 +1,88 @@
+package charges
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/clock"
+	"github/guiferpa/bank/domain/log"
+)
+
+type UseCaseService struct {
+	storage StorageRepository
+	engine  *Engine
+	clock   clock.Clock
+	logger  log.LoggerRepository
+}
+
+func (ucs *UseCaseService) accounts(ctx context.Context, accountID uint) ([]account.Account, error) {
+	if accountID == 0 {
+		return ucs.storage.ListAccounts(ctx)
+	}
+
+	acc, err := ucs.storage.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []account.Account{acc}, nil
+}
+
+func (ucs *UseCaseService) ApplyCharges(ctx context.Context, opts ApplyChargesOptions) (ApplyChargesResult, error) {
+	result := ApplyChargesResult{}
+
+	at := opts.At
+	if at.IsZero() {
+		at = ucs.clock.Now()
+	}
+
+	accs, err := ucs.accounts(ctx, opts.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	for _, acc := range accs {
+		txs, err := ucs.storage.ListTransactionsByAccountID(ctx, acc.ID, time.Time{}, day(at).AddDate(0, 0, 1))
+		if err != nil {
+			return result, err
+		}
+
+		invs, err := ucs.storage.ListInvoicesByAccountID(ctx, acc.ID)
+		if err != nil {
+			return result, err
+		}
+
+		charges := ucs.engine.Charges(EngineInput{
+			AccountID:    acc.ID,
+			Transactions: txs,
+			Invoices:     invs,
+			At:           at,
+		})
+
+		for _, charge := range charges {
+			_, err := ucs.storage.CreateTransaction(ctx, account.CreateTransactionOptions{
+				AccountID:       charge.AccountID,
+				OperationTypeID: charge.OperationTypeID,
+				Amount:          charge.Amount,
+				EventDate:       charge.EventDate,
+				IdempotencyKey:  charge.Key,
+			})
+			if err != nil {
+				if cerr, ok := err.(*account.InfraError); ok && cerr.Code == account.InfraTransactionDuplicatedErrorCode {
+					result.Skipped += 1
+					continue
+				}
+
+				return result, err
+			}
+			result.Created += 1
+		}
+	}
+
+	return result, nil
+}
+
+func NewUseCaseService(storage StorageRepository, config Config, clock clock.Clock, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, NewEngine(config), clock, logger}
+}
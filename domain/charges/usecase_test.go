@@ -0,0 +1,91 @@
+package charges
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/billing"
+)
+
+type FakeClock struct {
+	At time.Time
+}
+
+func (fc *FakeClock) Now() time.Time {
+	return fc.At
+}
+
+type MockStorageRepository struct {
+	Transactions []account.Transaction
+	Invoices     []billing.Invoice
+	Keys         map[string]bool
+
+	NCalledCreateTransaction int
+}
+
+func (msr *MockStorageRepository) ListAccounts(ctx context.Context) ([]account.Account, error) {
+	return []account.Account{{ID: 1}}, nil
+}
+
+func (msr *MockStorageRepository) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	return account.Account{ID: accountID}, nil
+}
+
+func (msr *MockStorageRepository) ListTransactionsByAccountID(ctx context.Context, accountID uint, from, to time.Time) ([]account.Transaction, error) {
+	return msr.Transactions, nil
+}
+
+func (msr *MockStorageRepository) ListInvoicesByAccountID(ctx context.Context, accountID uint) ([]billing.Invoice, error) {
+	return msr.Invoices, nil
+}
+
+func (msr *MockStorageRepository) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	msr.NCalledCreateTransaction += 1
+
+	if msr.Keys[opts.IdempotencyKey] {
+		return 0, account.NewInfraError(account.InfraTransactionDuplicatedErrorCode, "transaction already exists")
+	}
+	msr.Keys[opts.IdempotencyKey] = true
+
+	return uint(len(msr.Keys)), nil
+}
+
+func TestApplyChargesReplayed(t *testing.T) {
+	mock := &MockStorageRepository{
+		Transactions: []account.Transaction{
+			{ID: 3, AccountID: 1, OperationTypeID: account.WithdrawalOperationTypeID, Amount: -200_00, EventDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		Invoices: []billing.Invoice{
+			{ID: 9, AccountID: 1, DueDate: time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC), Status: billing.OverdueStatus, Total: -100_00},
+		},
+		Keys: make(map[string]bool),
+	}
+	clock := &FakeClock{At: time.Date(2023, time.February, 12, 12, 0, 0, 0, time.UTC)}
+	svc := NewUseCaseService(mock, DefaultConfig, clock, nil)
+
+	suite := []struct {
+		At             time.Time
+		ExpectedResult ApplyChargesResult
+	}{
+		{At: clock.At, ExpectedResult: ApplyChargesResult{Created: 4}},
+		{At: clock.At, ExpectedResult: ApplyChargesResult{Skipped: 4}},
+		{At: clock.At.AddDate(0, 0, 1), ExpectedResult: ApplyChargesResult{Created: 1, Skipped: 4}},
+	}
+
+	for _, s := range suite {
+		clock.At = s.At
+
+		result, err := svc.ApplyCharges(context.Background(), ApplyChargesOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := result, s.ExpectedResult; got != expected {
+			t.Errorf("unexpected result at %v, got: %+v, expected: %+v", s.At, got, expected)
+			return
+		}
+	}
+}
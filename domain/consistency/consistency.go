@@ -0,0 +1,49 @@
+package consistency
+
+import (
+	"context"
+	"sync"
+)
+
+type primaryKey struct{}
+
+// WithPrimary makes the reads done with ctx go to the primary database instead of a replica, which may lag
+// behind it, so a caller sees the writes it has just made.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Primary tells whether the reads done with ctx must go to the primary database.
+func Primary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+type pinKey struct{}
+
+// pin keeps the database the first read done with a pinned context went to.
+type pin struct {
+	once sync.Once
+	db   interface{}
+}
+
+// WithPin makes every read done with ctx go to the same database, so reads which must agree with each other, such
+// as the opening balance and the rows of a statement, don't mix replicas lagging behind by different amounts.
+func WithPin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pinKey{}, &pin{})
+}
+
+// Pinned returns the database picked by pick for the first read done with ctx, pick is called for every read when
+// ctx isn't pinned.
+func Pinned(ctx context.Context, pick func() interface{}) interface{} {
+	p, ok := ctx.Value(pinKey{}).(*pin)
+	if !ok {
+		return pick()
+	}
+
+	p.once.Do(func() {
+		p.db = pick()
+	})
+
+	return p.db
+}
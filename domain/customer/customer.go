@@ -0,0 +1,38 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/tenant"
+)
+
+type Address struct {
+	Street       string
+	Number       string
+	Complement   string
+	Neighborhood string
+	City         string
+	State        string
+	ZipCode      string
+	Country      string
+}
+
+type Customer struct {
+	ID             uint
+	DocumentNumber string
+	Name           string
+	BirthDate      time.Time
+	Address        Address
+	Tenant         string
+}
+
+// Authorize fails when ctx is scoped to a tenant other than the one which created the customer.
+func Authorize(ctx context.Context, cus Customer) error {
+	if id, ok := tenant.FromContext(ctx); ok && cus.Tenant != id {
+		return account.NewDomainError(account.DomainCustomerForbiddenErrorCode, "customer belongs to another tenant")
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type CreateCustomerOptions struct {
+	DocumentNumber string
+	Name           string
+	BirthDate      time.Time
+	Address        Address
+	Tenant         string
+}
+
+type OpenAccountOptions struct {
+	CustomerID uint
+}
+
+type StorageRepository interface {
+	CreateCustomer(context.Context, CreateCustomerOptions) (uint, error)
+	GetCustomerByID(context.Context, uint) (Customer, error)
+	HasCustomerByDocumentNumber(context.Context, string) (bool, error)
+	// CompleteCustomer fills in the holder created along a legacy account, the one with only a document number,
+	// telling whether there was one.
+	CompleteCustomer(context.Context, CreateCustomerOptions) (uint, bool, error)
+	ListAccountsByCustomerID(context.Context, uint) ([]account.Account, error)
+}
+
+type UseCase interface {
+	CreateCustomer(context.Context, CreateCustomerOptions) (uint, error)
+	GetCustomerByID(context.Context, uint) (Customer, error)
+	OpenAccount(context.Context, OpenAccountOptions) (account.Account, error)
+	ListAccounts(context.Context, uint) ([]account.Account, error)
+}
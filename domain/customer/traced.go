@@ -0,0 +1,50 @@
+package customer
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/trace"
+)
+
+// TracedUseCase starts a span around every call to the wrapped use case.
+type TracedUseCase struct {
+	UseCase
+	tracer trace.Tracer
+}
+
+func (tuc *TracedUseCase) CreateCustomer(ctx context.Context, opts CreateCustomerOptions) (uint, error) {
+	ctx, span := tuc.tracer.Start(ctx, "customer.CreateCustomer")
+	result, err := tuc.UseCase.CreateCustomer(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) GetCustomerByID(ctx context.Context, customerID uint) (Customer, error) {
+	ctx, span := tuc.tracer.Start(ctx, "customer.GetCustomerByID")
+	result, err := tuc.UseCase.GetCustomerByID(ctx, customerID)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) OpenAccount(ctx context.Context, opts OpenAccountOptions) (account.Account, error) {
+	ctx, span := tuc.tracer.Start(ctx, "customer.OpenAccount")
+	result, err := tuc.UseCase.OpenAccount(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) ListAccounts(ctx context.Context, customerID uint) ([]account.Account, error) {
+	ctx, span := tuc.tracer.Start(ctx, "customer.ListAccounts")
+	result, err := tuc.UseCase.ListAccounts(ctx, customerID)
+	span.End(err)
+
+	return result, err
+}
+
+func NewTracedUseCase(usecase UseCase, tracer trace.Tracer) *TracedUseCase {
+	return &TracedUseCase{usecase, tracer}
+}
@@ -0,0 +1,106 @@
+package customer
+
+import (
+	"context"
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/tenant"
+)
+
+type UseCaseService struct {
+	storage  StorageRepository
+	accounts account.UseCase
+	logger   log.LoggerRepository
+}
+
+// CreateCustomer completes the holder an account opened without a customer created for the document number,
+// instead of answering it already exists.
+func (ucs *UseCaseService) CreateCustomer(ctx context.Context, opts CreateCustomerOptions) (uint, error) {
+	if id, ok := tenant.FromContext(ctx); ok {
+		opts.Tenant = id
+	}
+
+	customerID, completed, err := ucs.storage.CompleteCustomer(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	if completed {
+		return customerID, nil
+	}
+
+	has, err := ucs.storage.HasCustomerByDocumentNumber(ctx, opts.DocumentNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return 0, account.NewDomainError(account.DomainCustomerAlreadyExistsErrorCode, "customer already exists")
+	}
+
+	customerID, err = ucs.storage.CreateCustomer(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return customerID, nil
+}
+
+func (ucs *UseCaseService) GetCustomerByID(ctx context.Context, customerID uint) (Customer, error) {
+	cus, err := ucs.storage.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		return Customer{}, err
+	}
+
+	if err := Authorize(ctx, cus); err != nil {
+		return Customer{}, err
+	}
+
+	return cus, nil
+}
+
+func (ucs *UseCaseService) OpenAccount(ctx context.Context, opts OpenAccountOptions) (account.Account, error) {
+	cus, err := ucs.GetCustomerByID(ctx, opts.CustomerID)
+	if err != nil {
+		return account.Account{}, err
+	}
+
+	accountID, err := ucs.accounts.CreateAccount(ctx, account.CreateAccountOptions{
+		CustomerID:     cus.ID,
+		DocumentNumber: cus.DocumentNumber,
+	})
+	if err != nil {
+		return account.Account{}, err
+	}
+
+	return account.Account{
+		ID:             accountID,
+		CustomerID:     cus.ID,
+		DocumentNumber: cus.DocumentNumber,
+	}, nil
+}
+
+func (ucs *UseCaseService) ListAccounts(ctx context.Context, customerID uint) ([]account.Account, error) {
+	if _, err := ucs.storage.GetCustomerByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	accs, err := ucs.storage.ListAccountsByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A partner tenant only sees the accounts it created, the customer may have others.
+	owned := make([]account.Account, 0, len(accs))
+	for _, acc := range accs {
+		if account.Authorize(ctx, acc) == nil {
+			owned = append(owned, acc)
+		}
+	}
+
+	return owned, nil
+}
+
+func NewUseCaseService(storage StorageRepository, accounts account.UseCase, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, accounts, logger}
+}
@@ -0,0 +1,209 @@
+package customer
+
+import (
+	"context"
+	"testing"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type MockStorageRepository struct {
+	NCalledCreateCustomer              int
+	NCalledGetCustomerByID             int
+	NCalledHasCustomerByDocumentNumber int
+	NCalledListAccountsByCustomerID    int
+	NCalledCompleteCustomer            int
+	CompleteCustomerResult             bool
+	HasCustomerByDocumentNumberResult  bool
+	GetCustomerByIDResult              Customer
+	GetCustomerByIDErrorResult         error
+	ListAccountsByCustomerIDResult     []account.Account
+}
+
+func (msr *MockStorageRepository) CreateCustomer(ctx context.Context, opts CreateCustomerOptions) (uint, error) {
+	msr.NCalledCreateCustomer += 1
+	return 0, nil
+}
+
+func (msr *MockStorageRepository) GetCustomerByID(ctx context.Context, customerID uint) (Customer, error) {
+	msr.NCalledGetCustomerByID += 1
+	return msr.GetCustomerByIDResult, msr.GetCustomerByIDErrorResult
+}
+
+func (msr *MockStorageRepository) HasCustomerByDocumentNumber(ctx context.Context, documentNumber string) (bool, error) {
+	msr.NCalledHasCustomerByDocumentNumber += 1
+	return msr.HasCustomerByDocumentNumberResult, nil
+}
+
+func (msr *MockStorageRepository) CompleteCustomer(ctx context.Context, opts CreateCustomerOptions) (uint, bool, error) {
+	msr.NCalledCompleteCustomer += 1
+	if msr.CompleteCustomerResult {
+		return 3, true, nil
+	}
+	return 0, false, nil
+}
+
+func (msr *MockStorageRepository) ListAccountsByCustomerID(ctx context.Context, customerID uint) ([]account.Account, error) {
+	msr.NCalledListAccountsByCustomerID += 1
+	return msr.ListAccountsByCustomerIDResult, nil
+}
+
+type MockAccountUseCase struct {
+	account.UseCase
+
+	NCalledCreateAccount int
+	CreateAccountOptions account.CreateAccountOptions
+}
+
+func (mau *MockAccountUseCase) CreateAccount(ctx context.Context, opts account.CreateAccountOptions) (uint, error) {
+	mau.NCalledCreateAccount += 1
+	mau.CreateAccountOptions = opts
+	return 7, nil
+}
+
+func TestCreateCustomer(t *testing.T) {
+	suite := []struct {
+		CompleteCustomerResult                     bool
+		HasCustomerByDocumentNumberResult          bool
+		ExpectedNCalledHasCustomerByDocumentNumber int
+		ExpectedNCalledCreateCustomer              int
+		ExpectedErrorCode                          account.ErrorCode
+	}{
+		{
+			HasCustomerByDocumentNumberResult:          false,
+			ExpectedNCalledHasCustomerByDocumentNumber: 1,
+			ExpectedNCalledCreateCustomer:              1,
+		},
+		{
+			HasCustomerByDocumentNumberResult:          true,
+			ExpectedNCalledHasCustomerByDocumentNumber: 1,
+			ExpectedNCalledCreateCustomer:              0,
+			ExpectedErrorCode:                          account.DomainCustomerAlreadyExistsErrorCode,
+		},
+		{
+			CompleteCustomerResult:                     true,
+			HasCustomerByDocumentNumberResult:          true,
+			ExpectedNCalledHasCustomerByDocumentNumber: 0,
+			ExpectedNCalledCreateCustomer:              0,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{CompleteCustomerResult: s.CompleteCustomerResult, HasCustomerByDocumentNumberResult: s.HasCustomerByDocumentNumberResult}
+		svc := &UseCaseService{storage: mock}
+
+		_, err := svc.CreateCustomer(context.Background(), CreateCustomerOptions{DocumentNumber: "123", Name: "Jane Doe"})
+		if s.ExpectedErrorCode != "" {
+			cerr, ok := err.(*account.DomainError)
+			if !ok {
+				t.Error("unexpected error")
+				return
+			}
+
+			if got, expected := cerr.Code, s.ExpectedErrorCode; got != expected {
+				t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+				return
+			}
+		} else if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := mock.NCalledHasCustomerByDocumentNumber, s.ExpectedNCalledHasCustomerByDocumentNumber; got != expected {
+			t.Errorf("unexpected N called HasCustomerByDocumentNumber, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.NCalledCreateCustomer, s.ExpectedNCalledCreateCustomer; got != expected {
+			t.Errorf("unexpected N called CreateCustomer, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestOpenAccount(t *testing.T) {
+	suite := []struct {
+		Customer                     Customer
+		ExpectedNCalledCreateAccount int
+	}{
+		{
+			Customer:                     Customer{ID: 3, DocumentNumber: "123"},
+			ExpectedNCalledCreateAccount: 1,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{GetCustomerByIDResult: s.Customer}
+		accounts := &MockAccountUseCase{}
+		svc := &UseCaseService{storage: mock, accounts: accounts}
+
+		acc, err := svc.OpenAccount(context.Background(), OpenAccountOptions{CustomerID: s.Customer.ID})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := accounts.NCalledCreateAccount, s.ExpectedNCalledCreateAccount; got != expected {
+			t.Errorf("unexpected N called CreateAccount, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := accounts.CreateAccountOptions.CustomerID, s.Customer.ID; got != expected {
+			t.Errorf("unexpected customer ID, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := acc.DocumentNumber, s.Customer.DocumentNumber; got != expected {
+			t.Errorf("unexpected document number, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestOpenAccountWithCustomerNotFound(t *testing.T) {
+	mock := &MockStorageRepository{
+		GetCustomerByIDErrorResult: account.NewInfraError(account.InfraCustomerNotFoundErrorCode, "customer not found"),
+	}
+	accounts := &MockAccountUseCase{}
+	svc := &UseCaseService{storage: mock, accounts: accounts}
+
+	_, err := svc.OpenAccount(context.Background(), OpenAccountOptions{CustomerID: 20})
+	cerr, ok := err.(*account.InfraError)
+	if !ok {
+		t.Error("unexpected error")
+		return
+	}
+
+	if got, expected := cerr.Code, account.InfraCustomerNotFoundErrorCode; got != expected {
+		t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := accounts.NCalledCreateAccount, 0; got != expected {
+		t.Errorf("unexpected N called CreateAccount, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	mock := &MockStorageRepository{
+		ListAccountsByCustomerIDResult: []account.Account{{ID: 1, CustomerID: 3}, {ID: 2, CustomerID: 3}},
+	}
+	svc := &UseCaseService{storage: mock}
+
+	accs, err := svc.ListAccounts(context.Background(), 3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := len(accs), 2; got != expected {
+		t.Errorf("unexpected number of accounts, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := mock.NCalledListAccountsByCustomerID, 1; got != expected {
+		t.Errorf("unexpected N called ListAccountsByCustomerID, got: %v, expected: %v", got, expected)
+		return
+	}
+}
@@ -0,0 +1,199 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/clock"
+)
+
+type InvalidRuleError struct {
+	RuleID string
+	Reason string
+}
+
+func (err *InvalidRuleError) Error() string {
+	return fmt.Sprintf("invalid rule %q: %s", err.RuleID, err.Reason)
+}
+
+type Evaluator struct {
+	rules     []Rule
+	blocklist map[string]map[string]bool
+	counters  CounterRepository
+	clock     clock.Clock
+}
+
+func validate(rules []Rule) error {
+	ids := make(map[string]bool)
+	for _, r := range rules {
+		if r.ID == "" {
+			return &InvalidRuleError{r.ID, "missing id"}
+		}
+
+		if ids[r.ID] {
+			return &InvalidRuleError{r.ID, "duplicated id"}
+		}
+		ids[r.ID] = true
+
+		switch r.Type {
+		case VelocityRuleType:
+			if r.MaxTransactions <= 0 {
+				return &InvalidRuleError{r.ID, "max transactions must be greater than zero"}
+			}
+		case AmountLimitRuleType:
+			if r.MaxAmount <= 0 {
+				return &InvalidRuleError{r.ID, "max amount must be greater than zero"}
+			}
+		case BlocklistRuleType:
+			if len(r.DocumentNumbers) == 0 {
+				return &InvalidRuleError{r.ID, "missing document numbers"}
+			}
+		default:
+			return &InvalidRuleError{r.ID, fmt.Sprintf("unknown type %q", r.Type)}
+		}
+
+		if r.Window < 0 {
+			return &InvalidRuleError{r.ID, "window can't be negative"}
+		}
+	}
+
+	return nil
+}
+
+func abs(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+
+	return amount
+}
+
+func window(r Rule) time.Duration {
+	if r.Window > 0 {
+		return r.Window
+	}
+
+	if r.Type == AmountLimitRuleType {
+		return DefaultAmountLimitWindow
+	}
+
+	return DefaultVelocityWindow
+}
+
+// Counters live in fixed windows aligned to the window size, so a per minute rule resets at every minute.
+func (e *Evaluator) counterKey(r Rule, opts account.CreateTransactionOptions) (CounterKey, bool) {
+	start := e.clock.Now().UTC().Truncate(window(r))
+
+	switch r.Type {
+	case VelocityRuleType:
+		return CounterKey{Name: fmt.Sprintf("%s:%d", r.ID, opts.AccountID), WindowStart: start}, true
+	case AmountLimitRuleType:
+		if r.OperationTypeID != 0 && r.OperationTypeID != opts.OperationTypeID {
+			return CounterKey{}, false
+		}
+		return CounterKey{Name: fmt.Sprintf("%s:%d:%d", r.ID, opts.AccountID, opts.OperationTypeID), WindowStart: start}, true
+	}
+
+	return CounterKey{}, false
+}
+
+func declined(r Rule, reason string) error {
+	err := account.NewDomainError(account.DomainTransactionDeclinedErrorCode, fmt.Sprintf("transaction declined: %s", reason))
+	err.RuleID = r.ID
+	return err
+}
+
+// increment is what a transaction added to a counter, kept to be taken back.
+type increment struct {
+	key       CounterKey
+	delta     Counter
+	expiresAt time.Time
+}
+
+// release takes back the increments of a transaction declined, or not stored in the end.
+func (e *Evaluator) release(ctx context.Context, increments []increment) error {
+	for _, inc := range increments {
+		delta := Counter{Count: -inc.delta.Count, Amount: -inc.delta.Amount}
+		if _, err := e.counters.IncrementCounter(ctx, inc.key, delta, inc.expiresAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Evaluate accounts the transaction in the counters and checks the rules against what the counters resulted in,
+// the store incrementing and returning them at once so concurrent transactions can't slip under a limit together.
+// A declined transaction has its increments taken back, then it never counts, and so has an accepted one by the
+// returned function when it doesn't get stored.
+func (e *Evaluator) Evaluate(ctx context.Context, acc account.Account, opts account.CreateTransactionOptions) (account.ReleaseFunc, error) {
+	for _, r := range e.rules {
+		if r.Type == BlocklistRuleType && e.blocklist[r.ID][strings.TrimSpace(acc.DocumentNumber)] {
+			return nil, declined(r, "document number is blocklisted")
+		}
+	}
+
+	increments := make([]increment, 0, len(e.rules))
+	for _, r := range e.rules {
+		key, ok := e.counterKey(r, opts)
+		if !ok {
+			continue
+		}
+
+		delta, expiresAt := Counter{Count: 1, Amount: abs(opts.Amount)}, key.WindowStart.Add(window(r))
+		counter, err := e.counters.IncrementCounter(ctx, key, delta, expiresAt)
+		if err != nil {
+			if rerr := e.release(ctx, increments); rerr != nil {
+				return nil, rerr
+			}
+			return nil, err
+		}
+		increments = append(increments, increment{key, delta, expiresAt})
+
+		var reason string
+		switch r.Type {
+		case VelocityRuleType:
+			if counter.Count > r.MaxTransactions {
+				reason = fmt.Sprintf("more than %d transactions in %s", r.MaxTransactions, window(r))
+			}
+		case AmountLimitRuleType:
+			if counter.Amount > r.MaxAmount {
+				reason = fmt.Sprintf("amount limit exceeded in %s", window(r))
+			}
+		}
+
+		if reason != "" {
+			if err := e.release(ctx, increments); err != nil {
+				return nil, err
+			}
+			return nil, declined(r, reason)
+		}
+	}
+
+	return func(ctx context.Context) error {
+		return e.release(ctx, increments)
+	}, nil
+}
+
+func NewEvaluator(rules []Rule, counters CounterRepository, clock clock.Clock) (*Evaluator, error) {
+	if err := validate(rules); err != nil {
+		return nil, err
+	}
+
+	blocklist := make(map[string]map[string]bool)
+	for _, r := range rules {
+		if r.Type != BlocklistRuleType {
+			continue
+		}
+
+		blocklist[r.ID] = make(map[string]bool, len(r.DocumentNumbers))
+		for _, documentNumber := range r.DocumentNumbers {
+			blocklist[r.ID][strings.TrimSpace(documentNumber)] = true
+		}
+	}
+
+	return &Evaluator{rules, blocklist, counters, clock}, nil
+}
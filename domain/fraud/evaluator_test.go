@@ -0,0 +1,171 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type FakeClock struct {
+	At time.Time
+}
+
+func (fc *FakeClock) Now() time.Time {
+	return fc.At
+}
+
+type MockCounterRepository struct {
+	Counters map[CounterKey]Counter
+}
+
+func (mcr *MockCounterRepository) IncrementCounter(ctx context.Context, key CounterKey, delta Counter, expiresAt time.Time) (Counter, error) {
+	counter := mcr.Counters[key]
+	counter.Count += delta.Count
+	counter.Amount += delta.Amount
+	mcr.Counters[key] = counter
+	return counter, nil
+}
+
+func TestNewEvaluatorWithInvalidRules(t *testing.T) {
+	suite := []struct {
+		Describe string
+		Rules    []Rule
+	}{
+		{Describe: "Missing id", Rules: []Rule{{Type: VelocityRuleType, MaxTransactions: 1}}},
+		{Describe: "Duplicated id", Rules: []Rule{{ID: "a", Type: VelocityRuleType, MaxTransactions: 1}, {ID: "a", Type: VelocityRuleType, MaxTransactions: 1}}},
+		{Describe: "Unknown type", Rules: []Rule{{ID: "a", Type: "unknown"}}},
+		{Describe: "Velocity without max transactions", Rules: []Rule{{ID: "a", Type: VelocityRuleType}}},
+		{Describe: "Amount limit without max amount", Rules: []Rule{{ID: "a", Type: AmountLimitRuleType}}},
+		{Describe: "Blocklist without document numbers", Rules: []Rule{{ID: "a", Type: BlocklistRuleType}}},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Describe, func(t *testing.T) {
+			if _, err := NewEvaluator(s.Rules, nil, nil); err == nil {
+				t.Error("unexpected nil error")
+				return
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2023, time.March, 10, 10, 0, 0, 0, time.UTC)
+	acc := account.Account{ID: 1, DocumentNumber: "123"}
+
+	type attempt struct {
+		At              time.Time
+		Account         account.Account
+		OperationTypeID uint
+		Amount          int64
+		ExpectedRuleID  string
+	}
+
+	suite := []struct {
+		Describe string
+		Rules    []Rule
+		Attempts []attempt
+	}{
+		{
+			Describe: "Declined by velocity within the same minute",
+			Rules:    []Rule{{ID: "velocity", Type: VelocityRuleType, MaxTransactions: 2}},
+			Attempts: []attempt{
+				{At: start, Account: acc, OperationTypeID: 1, Amount: -1_00},
+				{At: start.Add(10 * time.Second), Account: acc, OperationTypeID: 1, Amount: -1_00},
+				{At: start.Add(20 * time.Second), Account: acc, OperationTypeID: 1, Amount: -1_00, ExpectedRuleID: "velocity"},
+				{At: start.Add(time.Minute), Account: acc, OperationTypeID: 1, Amount: -1_00},
+			},
+		},
+		{
+			Describe: "Declined by amount limit only for the operation type",
+			Rules:    []Rule{{ID: "withdrawal", Type: AmountLimitRuleType, OperationTypeID: 3, MaxAmount: 100_00}},
+			Attempts: []attempt{
+				{At: start, Account: acc, OperationTypeID: 3, Amount: -80_00},
+				{At: start.Add(time.Hour), Account: acc, OperationTypeID: 3, Amount: -30_00, ExpectedRuleID: "withdrawal"},
+				{At: start.Add(time.Hour), Account: acc, OperationTypeID: 1, Amount: -30_00},
+				{At: start.Add(time.Hour), Account: acc, OperationTypeID: 3, Amount: -20_00},
+				{At: start.Add(24 * time.Hour), Account: acc, OperationTypeID: 3, Amount: -100_00},
+			},
+		},
+		{
+			Describe: "Declined by blocklisted document number",
+			Rules:    []Rule{{ID: "blocklist", Type: BlocklistRuleType, DocumentNumbers: []string{"123"}}},
+			Attempts: []attempt{
+				{At: start, Account: acc, OperationTypeID: 1, Amount: -1_00, ExpectedRuleID: "blocklist"},
+				{At: start, Account: account.Account{ID: 2, DocumentNumber: "456"}, OperationTypeID: 1, Amount: -1_00},
+			},
+		},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Describe, func(t *testing.T) {
+			clock := &FakeClock{}
+			counters := &MockCounterRepository{Counters: make(map[CounterKey]Counter)}
+			evaluator, err := NewEvaluator(s.Rules, counters, clock)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			for i, a := range s.Attempts {
+				clock.At = a.At
+
+				opts := account.CreateTransactionOptions{AccountID: a.Account.ID, OperationTypeID: a.OperationTypeID, Amount: a.Amount}
+				_, err := evaluator.Evaluate(context.Background(), a.Account, opts)
+				if a.ExpectedRuleID == "" {
+					if err != nil {
+						t.Errorf("unexpected error at attempt %d: %v", i, err)
+					}
+					continue
+				}
+
+				cerr, ok := err.(*account.DomainError)
+				if !ok {
+					t.Errorf("unexpected error at attempt %d, got: %v", i, err)
+					return
+				}
+
+				if got, expected := cerr.Code, account.DomainTransactionDeclinedErrorCode; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := cerr.RuleID, a.ExpectedRuleID; got != expected {
+					t.Errorf("unexpected rule ID, got: %v, expected: %v", got, expected)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateReleased(t *testing.T) {
+	rules := []Rule{
+		{ID: "velocity", Type: VelocityRuleType, MaxTransactions: 2},
+		{ID: "withdrawal", Type: AmountLimitRuleType, OperationTypeID: 3, MaxAmount: 100_00},
+	}
+	clock := &FakeClock{At: time.Date(2023, time.March, 10, 10, 0, 0, 0, time.UTC)}
+	counters := &MockCounterRepository{Counters: make(map[CounterKey]Counter)}
+	evaluator, err := NewEvaluator(rules, counters, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc := account.Account{ID: 1, DocumentNumber: "123"}
+	release, err := evaluator.Evaluate(context.Background(), acc, account.CreateTransactionOptions{AccountID: 1, OperationTypeID: 3, Amount: -80_00})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := release(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, counter := range counters.Counters {
+		if got, expected := counter, (Counter{}); got != expected {
+			t.Errorf("unexpected counter of %s, got: %v, expected: %v", key.Name, got, expected)
+		}
+	}
+}
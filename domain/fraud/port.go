@@ -0,0 +1,21 @@
+package fraud
+
+import (
+	"context"
+	"time"
+)
+
+type CounterKey struct {
+	Name        string
+	WindowStart time.Time
+}
+
+type Counter struct {
+	Count  int64
+	Amount int64
+}
+
+type CounterRepository interface {
+	// IncrementCounter adds delta to the counter of key and returns what it resulted in, as a single operation.
+	IncrementCounter(ctx context.Context, key CounterKey, delta Counter, expiresAt time.Time) (Counter, error)
+}
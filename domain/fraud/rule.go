@@ -0,0 +1,29 @@
+package fraud
+
+import "time"
+
+type RuleType string
+
+const (
+	VelocityRuleType    RuleType = "velocity"
+	AmountLimitRuleType RuleType = "amount_limit"
+	BlocklistRuleType   RuleType = "blocklist"
+)
+
+const (
+	DefaultVelocityWindow    = time.Minute
+	DefaultAmountLimitWindow = 24 * time.Hour
+)
+
+// Rule declines a transaction when, within Window, the account goes over MaxTransactions (velocity),
+// the amount for OperationTypeID goes over MaxAmount (amount_limit, every type when zero) or the
+// holder's document number is listed in DocumentNumbers (blocklist).
+type Rule struct {
+	ID              string
+	Type            RuleType
+	Window          time.Duration
+	MaxTransactions int64
+	OperationTypeID uint
+	MaxAmount       int64
+	DocumentNumbers []string
+}
@@ -0,0 +1,24 @@
+package health
+
+import "time"
+
+type Status string
+
+const (
+	UpStatus   Status = "up"
+	DownStatus Status = "down"
+)
+
+// Check is the state of one dependency, Error says why it's down.
+type Check struct {
+	Name    string
+	Status  Status
+	Error   string
+	Latency time.Duration
+}
+
+// Report is up only when every check is up.
+type Report struct {
+	Status Status
+	Checks []Check
+}
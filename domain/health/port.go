@@ -0,0 +1,12 @@
+package health
+
+import "context"
+
+// HealthChecker is implemented by adapters the API can't serve without, each may report several checks.
+type HealthChecker interface {
+	HealthChecks(ctx context.Context) []Check
+}
+
+type UseCase interface {
+	Readiness(ctx context.Context) Report
+}
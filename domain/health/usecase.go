@@ -0,0 +1,44 @@
+package health
+
+import (
+	"context"
+	"sync"
+
+	"github/guiferpa/bank/domain/log"
+)
+
+type UseCaseService struct {
+	checkers []HealthChecker
+	logger   log.LoggerRepository
+}
+
+// Readiness runs every checker at once, ctx bounds how long a slow dependency can hold the answer.
+func (ucs *UseCaseService) Readiness(ctx context.Context) Report {
+	results := make([][]Check, len(ucs.checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range ucs.checkers {
+		wg.Add(1)
+		go func(i int, checker HealthChecker) {
+			defer wg.Done()
+			results[i] = checker.HealthChecks(ctx)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	report := Report{Status: UpStatus, Checks: make([]Check, 0)}
+	for _, checks := range results {
+		for _, check := range checks {
+			if check.Status != UpStatus {
+				report.Status = DownStatus
+			}
+			report.Checks = append(report.Checks, check)
+		}
+	}
+
+	return report
+}
+
+func NewUseCaseService(checkers []HealthChecker, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{checkers, logger}
+}
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"testing"
+)
+
+type MockHealthChecker struct {
+	Checks []Check
+}
+
+func (mhc *MockHealthChecker) HealthChecks(ctx context.Context) []Check {
+	return mhc.Checks
+}
+
+func TestReadiness(t *testing.T) {
+	suite := []struct {
+		Checkers       []HealthChecker
+		ExpectedStatus Status
+		ExpectedChecks int
+	}{
+		{
+			Checkers:       []HealthChecker{},
+			ExpectedStatus: UpStatus,
+			ExpectedChecks: 0,
+		},
+		{
+			Checkers: []HealthChecker{
+				&MockHealthChecker{[]Check{{Name: "database", Status: UpStatus}, {Name: "migrations", Status: UpStatus}}},
+			},
+			ExpectedStatus: UpStatus,
+			ExpectedChecks: 2,
+		},
+		{
+			Checkers: []HealthChecker{
+				&MockHealthChecker{[]Check{{Name: "database", Status: UpStatus}}},
+				&MockHealthChecker{[]Check{{Name: "migrations", Status: DownStatus, Error: "missing table transactions"}}},
+			},
+			ExpectedStatus: DownStatus,
+			ExpectedChecks: 2,
+		},
+	}
+
+	for _, s := range suite {
+		svc := NewUseCaseService(s.Checkers, nil)
+
+		report := svc.Readiness(context.Background())
+
+		if got, expected := report.Status, s.ExpectedStatus; got != expected {
+			t.Errorf("unexpected status, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := len(report.Checks), s.ExpectedChecks; got != expected {
+			t.Errorf("unexpected number of checks, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
@@ -8,7 +8,10 @@ const LoggerContextKey LoggerContextKeyType = "__LOGGER_CTX_KEY__"
 
 type LoggerContext struct {
 	RequestID string      `json:"request_id"`
+	Principal string      `json:"principal,omitempty"`
 	Payload   interface{} `json:"payload"`
+	// Error is what went wrong with a request, when it's kept from the response.
+	Error string `json:"error,omitempty"`
 }
 
 type LoggerRepository interface {
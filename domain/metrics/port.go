@@ -0,0 +1,12 @@
+package metrics
+
+import "time"
+
+// MetricsRepository records what the layers do, adapters decide how it's exposed.
+type MetricsRepository interface {
+	ObserveHTTPRequest(method, route string, status int, elapsed time.Duration)
+	IncAccountsCreated()
+	IncTransactionsCreated(operationTypeID uint)
+	IncTransactionsDeclined(reason string)
+	ObserveQuery(operation, table string, elapsed time.Duration)
+}
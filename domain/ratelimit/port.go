@@ -0,0 +1,20 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store keeps a bucket for each key, shared by every replica of the API when the store is. Take must go through
+// Limit.Take atomically for the key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+	// Refund goes through Limit.Refund atomically for the key.
+	Refund(ctx context.Context, key string, limit Limit) error
+}
+
+type UseCase interface {
+	Allow(ctx context.Context, key string, limit Limit) (Decision, error)
+	// Refund gives back the token Allow took for a request turned down by another limit.
+	Refund(ctx context.Context, key string, limit Limit) error
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit is a token bucket holding up to Burst tokens, Requests when it's zero, refilled with Requests tokens
+// every Period. Each request takes a token and is turned down when there's none.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// rate is how many tokens are refilled a second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) after(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.rate() * float64(time.Second)))
+}
+
+// Bucket is what a store keeps for each key, the zero one is full.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Decision tells whether a request goes through and how it left the bucket, Reset is how long until the bucket is
+// full again and RetryAfter, for a request turned down, until there's a token for it.
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Refund gives b back a token taken by a request which didn't go through after all, never over the capacity.
+func (l Limit) Refund(b Bucket) Bucket {
+	b.Tokens = math.Min(float64(l.capacity()), b.Tokens+1)
+	return b
+}
+
+// Take refills b with the tokens of the time passed since it was last updated and takes one from it for a request
+// made at now. Stores call it while holding the bucket, so concurrent requests don't take the same token.
+func (l Limit) Take(b Bucket, now time.Time) (Bucket, Decision) {
+	capacity := float64(l.capacity())
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = capacity
+	} else if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed.Seconds()*l.rate())
+	}
+	if now.After(b.UpdatedAt) {
+		b.UpdatedAt = now
+	}
+
+	d := Decision{Limit: l.capacity()}
+	if b.Tokens >= 1 {
+		b.Tokens -= 1
+		d.Allowed = true
+	} else {
+		d.RetryAfter = l.after(1 - b.Tokens)
+	}
+	d.Remaining = int(math.Floor(b.Tokens))
+	d.Reset = l.after(capacity - b.Tokens)
+
+	return b, d
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitTake(t *testing.T) {
+	limit := Limit{Requests: 2, Period: time.Second, Burst: 3}
+	start := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	suite := []struct {
+		Name       string
+		At         time.Duration
+		Allowed    bool
+		Remaining  int
+		RetryAfter time.Duration
+	}{
+		{"First request finds a full bucket", 0, true, 2, 0},
+		{"Second request in the same instant", 0, true, 1, 0},
+		{"Third request takes the last token", 0, true, 0, 0},
+		{"Fourth request is turned down", 0, false, 0, 500 * time.Millisecond},
+		{"Half a token refilled isn't enough", 250 * time.Millisecond, false, 0, 250 * time.Millisecond},
+		{"A token refilled", 500 * time.Millisecond, true, 0, 0},
+		{"Refill stops at the burst", time.Hour, true, 2, 0},
+	}
+
+	var b Bucket
+	for _, s := range suite {
+		var d Decision
+		b, d = limit.Take(b, start.Add(s.At))
+
+		if got, expected := d.Allowed, s.Allowed; got != expected {
+			t.Errorf("%s: unexpected allowed, got: %v, expected: %v", s.Name, got, expected)
+			return
+		}
+
+		if got, expected := d.Remaining, s.Remaining; got != expected {
+			t.Errorf("%s: unexpected remaining, got: %v, expected: %v", s.Name, got, expected)
+			return
+		}
+
+		if got, expected := d.RetryAfter, s.RetryAfter; got != expected {
+			t.Errorf("%s: unexpected retry after, got: %v, expected: %v", s.Name, got, expected)
+			return
+		}
+
+		if got, expected := d.Limit, 3; got != expected {
+			t.Errorf("%s: unexpected limit, got: %v, expected: %v", s.Name, got, expected)
+			return
+		}
+	}
+}
+
+func TestLimitRefund(t *testing.T) {
+	limit := Limit{Requests: 2, Period: time.Second, Burst: 3}
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	b, _ := limit.Take(Bucket{}, now)
+	b = limit.Refund(b)
+	if _, d := limit.Take(b, now); d.Remaining != 2 {
+		t.Errorf("unexpected remaining after a refund, got: %v, expected: %v", d.Remaining, 2)
+		return
+	}
+
+	if got, expected := limit.Refund(limit.Refund(b)).Tokens, 3.0; got != expected {
+		t.Errorf("unexpected tokens refunded over the burst, got: %v, expected: %v", got, expected)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/clock"
+	"github/guiferpa/bank/domain/log"
+)
+
+type UseCaseService struct {
+	store  Store
+	clock  clock.Clock
+	logger log.LoggerRepository
+}
+
+func (ucs *UseCaseService) Allow(ctx context.Context, key string, limit Limit) (Decision, error) {
+	return ucs.store.Take(ctx, key, limit, ucs.clock.Now())
+}
+
+func (ucs *UseCaseService) Refund(ctx context.Context, key string, limit Limit) error {
+	return ucs.store.Refund(ctx, key, limit)
+}
+
+func NewUseCaseService(store Store, clock clock.Clock, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{store, clock, logger}
+}
@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+type CreateScheduledTransactionOptions struct {
+	AccountID       uint
+	OperationTypeID uint
+	Amount          int64
+	StartAt         time.Time
+	Recurrence      *Recurrence
+	EndAt           *time.Time
+	MaxOccurrences  int
+}
+
+// Nil fields in UpdateScheduledTransactionOptions keep their current value.
+type UpdateScheduledTransactionOptions struct {
+	ID             uint
+	Amount         *int64
+	EndAt          *time.Time
+	MaxOccurrences *int
+}
+
+type RunDueOptions struct {
+	At time.Time
+}
+
+type Failure struct {
+	ScheduleID uint
+	Occurrence int
+	Err        error
+}
+
+type RunDueResult struct {
+	Created  int
+	Skipped  int
+	Failures []Failure
+}
+
+type StorageRepository interface {
+	HasOperationTypeByID(context.Context, uint) (bool, error)
+	CreateScheduledTransaction(context.Context, ScheduledTransaction) (uint, error)
+	GetScheduledTransactionByID(context.Context, uint) (ScheduledTransaction, error)
+	ListScheduledTransactionsByAccountID(context.Context, uint) ([]ScheduledTransaction, error)
+	ListDueScheduledTransactions(ctx context.Context, at time.Time) ([]ScheduledTransaction, error)
+	UpdateScheduledTransaction(context.Context, ScheduledTransaction) error
+}
+
+type UseCase interface {
+	CreateScheduledTransaction(context.Context, CreateScheduledTransactionOptions) (ScheduledTransaction, error)
+	GetScheduledTransactionByID(context.Context, uint) (ScheduledTransaction, error)
+	ListScheduledTransactionsByAccountID(context.Context, uint) ([]ScheduledTransaction, error)
+	UpdateScheduledTransaction(context.Context, UpdateScheduledTransactionOptions) (ScheduledTransaction, error)
+	CancelScheduledTransaction(context.Context, uint) error
+	RunDueScheduledTransactions(context.Context, RunDueOptions) (RunDueResult, error)
+}
@@ -0,0 +1,44 @@
+package schedule
+
+import "time"
+
+type Frequency string
+
+const (
+	DailyFrequency   Frequency = "daily"
+	WeeklyFrequency  Frequency = "weekly"
+	MonthlyFrequency Frequency = "monthly"
+)
+
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int
+}
+
+func (r Recurrence) Valid() bool {
+	switch r.Frequency {
+	case DailyFrequency, WeeklyFrequency, MonthlyFrequency:
+		return r.Interval > 0
+	}
+
+	return false
+}
+
+// Occurrence returns the n-th occurrence, zero based, counted from start. Monthly occurrences keep
+// the start's day clamped to the month's last day, so a schedule set on the 31st runs at every month's end.
+func (r Recurrence) Occurrence(start time.Time, n int) time.Time {
+	switch r.Frequency {
+	case DailyFrequency:
+		return start.AddDate(0, 0, n*r.Interval)
+	case WeeklyFrequency:
+		return start.AddDate(0, 0, 7*n*r.Interval)
+	}
+
+	first := time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	day := start.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurrenceOccurrence(t *testing.T) {
+	start := time.Date(2023, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	suite := []struct {
+		Recurrence Recurrence
+		N          int
+		Expected   time.Time
+	}{
+		{
+			Recurrence: Recurrence{Frequency: DailyFrequency, Interval: 2},
+			N:          3,
+			Expected:   time.Date(2023, time.February, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Recurrence: Recurrence{Frequency: WeeklyFrequency, Interval: 1},
+			N:          1,
+			Expected:   time.Date(2023, time.February, 7, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Recurrence: Recurrence{Frequency: MonthlyFrequency, Interval: 1},
+			N:          1,
+			Expected:   time.Date(2023, time.February, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Recurrence: Recurrence{Frequency: MonthlyFrequency, Interval: 1},
+			N:          2,
+			Expected:   time.Date(2023, time.March, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Recurrence: Recurrence{Frequency: MonthlyFrequency, Interval: 12},
+			N:          1,
+			Expected:   time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, s := range suite {
+		if got, expected := s.Recurrence.Occurrence(start, s.N), s.Expected; !got.Equal(expected) {
+			t.Errorf("unexpected occurrence for %v, got: %v, expected: %v", s.Recurrence, got, expected)
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package schedule
+
+import "time"
+
+type Status string
+
+const (
+	ActiveStatus    Status = "active"
+	CompletedStatus Status = "completed"
+	CanceledStatus  Status = "canceled"
+)
+
+// ScheduledTransaction is a one-off transaction when Recurrence is nil. Occurrences counts the ones already
+// materialized and Version guards concurrent updates made by other replicas.
+type ScheduledTransaction struct {
+	ID              uint
+	AccountID       uint
+	OperationTypeID uint
+	Amount          int64
+	StartAt         time.Time
+	Recurrence      *Recurrence
+	EndAt           *time.Time
+	MaxOccurrences  int
+	Occurrences     int
+	NextRunAt       *time.Time
+	Status          Status
+	Version         int
+	CreatedAt       time.Time
+}
+
+func (st ScheduledTransaction) occurrence(n int) (time.Time, bool) {
+	if st.Recurrence == nil {
+		return st.StartAt, n == 0
+	}
+
+	if st.MaxOccurrences > 0 && n >= st.MaxOccurrences {
+		return time.Time{}, false
+	}
+
+	at := st.Recurrence.Occurrence(st.StartAt, n)
+	if st.EndAt != nil && at.After(*st.EndAt) {
+		return time.Time{}, false
+	}
+
+	return at, true
+}
+
+func (st *ScheduledTransaction) schedule() {
+	if st.Status == CanceledStatus {
+		st.NextRunAt = nil
+		return
+	}
+
+	at, ok := st.occurrence(st.Occurrences)
+	if !ok {
+		st.Status = CompletedStatus
+		st.NextRunAt = nil
+		return
+	}
+
+	st.Status = ActiveStatus
+	st.NextRunAt = &at
+}
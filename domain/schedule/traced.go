@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/trace"
+)
+
+// TracedUseCase starts a span around every call to the wrapped use case.
+type TracedUseCase struct {
+	UseCase
+	tracer trace.Tracer
+}
+
+func (tuc *TracedUseCase) CreateScheduledTransaction(ctx context.Context, opts CreateScheduledTransactionOptions) (ScheduledTransaction, error) {
+	ctx, span := tuc.tracer.Start(ctx, "schedule.CreateScheduledTransaction")
+	result, err := tuc.UseCase.CreateScheduledTransaction(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) GetScheduledTransactionByID(ctx context.Context, id uint) (ScheduledTransaction, error) {
+	ctx, span := tuc.tracer.Start(ctx, "schedule.GetScheduledTransactionByID")
+	result, err := tuc.UseCase.GetScheduledTransactionByID(ctx, id)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) ListScheduledTransactionsByAccountID(ctx context.Context, accountID uint) ([]ScheduledTransaction, error) {
+	ctx, span := tuc.tracer.Start(ctx, "schedule.ListScheduledTransactionsByAccountID")
+	result, err := tuc.UseCase.ListScheduledTransactionsByAccountID(ctx, accountID)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) UpdateScheduledTransaction(ctx context.Context, opts UpdateScheduledTransactionOptions) (ScheduledTransaction, error) {
+	ctx, span := tuc.tracer.Start(ctx, "schedule.UpdateScheduledTransaction")
+	result, err := tuc.UseCase.UpdateScheduledTransaction(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) CancelScheduledTransaction(ctx context.Context, id uint) error {
+	ctx, span := tuc.tracer.Start(ctx, "schedule.CancelScheduledTransaction")
+	err := tuc.UseCase.CancelScheduledTransaction(ctx, id)
+	span.End(err)
+
+	return err
+}
+
+func (tuc *TracedUseCase) RunDueScheduledTransactions(ctx context.Context, opts RunDueOptions) (RunDueResult, error) {
+	ctx, span := tuc.tracer.Start(ctx, "schedule.RunDueScheduledTransactions")
+	result, err := tuc.UseCase.RunDueScheduledTransactions(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func NewTracedUseCase(usecase UseCase, tracer trace.Tracer) *TracedUseCase {
+	return &TracedUseCase{usecase, tracer}
+}
@@ -0,0 +1,245 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/clock"
+	"github/guiferpa/bank/domain/log"
+)
+
+type UseCaseService struct {
+	storage  StorageRepository
+	accounts account.UseCase
+	clock    clock.Clock
+	logger   log.LoggerRepository
+}
+
+func validate(st ScheduledTransaction) error {
+	if st.Amount == 0 {
+		return account.NewDomainError(account.DomainInvalidScheduleErrorCode, "amount can't be zero")
+	}
+
+	if st.Recurrence != nil && !st.Recurrence.Valid() {
+		return account.NewDomainError(account.DomainInvalidScheduleErrorCode, "recurrence must be daily, weekly or monthly with a positive interval")
+	}
+
+	if st.EndAt != nil && st.EndAt.Before(st.StartAt) {
+		return account.NewDomainError(account.DomainInvalidScheduleErrorCode, "end date can't be before start date")
+	}
+
+	if st.MaxOccurrences < 0 {
+		return account.NewDomainError(account.DomainInvalidScheduleErrorCode, "max occurrences can't be negative")
+	}
+
+	return nil
+}
+
+func (ucs *UseCaseService) CreateScheduledTransaction(ctx context.Context, opts CreateScheduledTransactionOptions) (ScheduledTransaction, error) {
+	st := ScheduledTransaction{
+		AccountID:       opts.AccountID,
+		OperationTypeID: opts.OperationTypeID,
+		Amount:          opts.Amount,
+		StartAt:         opts.StartAt.UTC(),
+		Recurrence:      opts.Recurrence,
+		EndAt:           opts.EndAt,
+		MaxOccurrences:  opts.MaxOccurrences,
+		Status:          ActiveStatus,
+	}
+
+	if st.StartAt.Before(ucs.clock.Now()) {
+		return ScheduledTransaction{}, account.NewDomainError(account.DomainInvalidScheduleErrorCode, "start date must be in the future")
+	}
+
+	if err := validate(st); err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	if _, err := ucs.accounts.GetAccountByID(ctx, opts.AccountID); err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	hasOperationType, err := ucs.storage.HasOperationTypeByID(ctx, opts.OperationTypeID)
+	if err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	if !hasOperationType {
+		return ScheduledTransaction{}, account.NewDomainError(account.DomainOperationTypeDoesntExistErrorCode, "operation type doesn't exist")
+	}
+
+	st.schedule()
+
+	id, err := ucs.storage.CreateScheduledTransaction(ctx, st)
+	if err != nil {
+		return ScheduledTransaction{}, err
+	}
+	st.ID = id
+
+	return st, nil
+}
+
+func (ucs *UseCaseService) GetScheduledTransactionByID(ctx context.Context, id uint) (ScheduledTransaction, error) {
+	st, err := ucs.storage.GetScheduledTransactionByID(ctx, id)
+	if err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	if _, err := ucs.accounts.GetAccountByID(ctx, st.AccountID); err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	return st, nil
+}
+
+func (ucs *UseCaseService) ListScheduledTransactionsByAccountID(ctx context.Context, accountID uint) ([]ScheduledTransaction, error) {
+	if _, err := ucs.accounts.GetAccountByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	sts, err := ucs.storage.ListScheduledTransactionsByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sts, nil
+}
+
+func (ucs *UseCaseService) UpdateScheduledTransaction(ctx context.Context, opts UpdateScheduledTransactionOptions) (ScheduledTransaction, error) {
+	st, err := ucs.storage.GetScheduledTransactionByID(ctx, opts.ID)
+	if err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	if _, err := ucs.accounts.GetAccountByID(ctx, st.AccountID); err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	if st.Status != ActiveStatus {
+		return ScheduledTransaction{}, account.NewDomainError(account.DomainScheduleNotActiveErrorCode, fmt.Sprintf("scheduled transaction is %s", st.Status))
+	}
+
+	if opts.Amount != nil {
+		st.Amount = *opts.Amount
+	}
+
+	if opts.EndAt != nil {
+		st.EndAt = opts.EndAt
+	}
+
+	if opts.MaxOccurrences != nil {
+		st.MaxOccurrences = *opts.MaxOccurrences
+	}
+
+	if err := validate(st); err != nil {
+		return ScheduledTransaction{}, err
+	}
+
+	st.schedule()
+
+	if err := ucs.storage.UpdateScheduledTransaction(ctx, st); err != nil {
+		return ScheduledTransaction{}, err
+	}
+	st.Version += 1
+
+	return st, nil
+}
+
+func (ucs *UseCaseService) CancelScheduledTransaction(ctx context.Context, id uint) error {
+	st, err := ucs.storage.GetScheduledTransactionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ucs.accounts.GetAccountByID(ctx, st.AccountID); err != nil {
+		return err
+	}
+
+	if st.Status != ActiveStatus {
+		return account.NewDomainError(account.DomainScheduleNotActiveErrorCode, fmt.Sprintf("scheduled transaction is %s", st.Status))
+	}
+
+	st.Status = CanceledStatus
+	st.schedule()
+
+	return ucs.storage.UpdateScheduledTransaction(ctx, st)
+}
+
+func outdated(err error) bool {
+	cerr, ok := err.(*account.InfraError)
+	return ok && cerr.Code == account.InfraScheduleOutdatedErrorCode
+}
+
+// Declines and other business errors are final for an occurrence, it's skipped so the schedule
+// keeps going. Any other error is returned and the occurrence is retried by the next run.
+func failed(err error) bool {
+	if _, ok := err.(*account.DomainError); ok {
+		return true
+	}
+
+	cerr, ok := err.(*account.InfraError)
+	return ok && cerr.Code == account.InfraAccountNotFoundErrorCode
+}
+
+func duplicated(err error) bool {
+	cerr, ok := err.(*account.InfraError)
+	return ok && cerr.Code == account.InfraTransactionDuplicatedErrorCode
+}
+
+// RunDueScheduledTransactions materializes every occurrence due until opts.At. Each occurrence is keyed
+// by schedule and occurrence number, so replicas running at the same time never create it twice.
+func (ucs *UseCaseService) RunDueScheduledTransactions(ctx context.Context, opts RunDueOptions) (RunDueResult, error) {
+	result := RunDueResult{}
+
+	at := opts.At
+	if at.IsZero() {
+		at = ucs.clock.Now()
+	}
+
+	sts, err := ucs.storage.ListDueScheduledTransactions(ctx, at)
+	if err != nil {
+		return result, err
+	}
+
+	for _, st := range sts {
+		for st.Status == ActiveStatus && st.NextRunAt != nil && !st.NextRunAt.After(at) {
+			_, err := ucs.accounts.CreateTransaction(ctx, account.CreateTransactionOptions{
+				AccountID:       st.AccountID,
+				OperationTypeID: st.OperationTypeID,
+				Amount:          st.Amount,
+				EventDate:       *st.NextRunAt,
+				IdempotencyKey:  fmt.Sprintf("scheduled:%d:%d", st.ID, st.Occurrences),
+				Scheduled:       true,
+			})
+			switch {
+			case err == nil:
+				result.Created += 1
+			case duplicated(err):
+				result.Skipped += 1
+			case failed(err):
+				result.Failures = append(result.Failures, Failure{ScheduleID: st.ID, Occurrence: st.Occurrences, Err: err})
+			default:
+				return result, err
+			}
+
+			st.Occurrences += 1
+			st.schedule()
+
+			if err := ucs.storage.UpdateScheduledTransaction(ctx, st); err != nil {
+				if outdated(err) {
+					break
+				}
+
+				return result, err
+			}
+			st.Version += 1
+		}
+	}
+
+	return result, nil
+}
+
+func NewUseCaseService(storage StorageRepository, accounts account.UseCase, clock clock.Clock, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, accounts, clock, logger}
+}
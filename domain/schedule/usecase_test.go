@@ -0,0 +1,349 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type FakeClock struct {
+	At time.Time
+}
+
+func (fc *FakeClock) Now() time.Time {
+	return fc.At
+}
+
+type MockStorageRepository struct {
+	NCalledUpdateScheduledTransaction   int
+	HasOperationTypeByIDResult          bool
+	ScheduledTransactions               map[uint]ScheduledTransaction
+	UpdateScheduledTransactionErrResult error
+}
+
+func (msr *MockStorageRepository) HasOperationTypeByID(ctx context.Context, operationTypeID uint) (bool, error) {
+	return msr.HasOperationTypeByIDResult, nil
+}
+
+func (msr *MockStorageRepository) CreateScheduledTransaction(ctx context.Context, st ScheduledTransaction) (uint, error) {
+	st.ID = uint(len(msr.ScheduledTransactions) + 1)
+	msr.ScheduledTransactions[st.ID] = st
+	return st.ID, nil
+}
+
+func (msr *MockStorageRepository) GetScheduledTransactionByID(ctx context.Context, id uint) (ScheduledTransaction, error) {
+	st, ok := msr.ScheduledTransactions[id]
+	if !ok {
+		return ScheduledTransaction{}, account.NewInfraError(account.InfraScheduleNotFoundErrorCode, "scheduled transaction not found")
+	}
+	return st, nil
+}
+
+func (msr *MockStorageRepository) ListScheduledTransactionsByAccountID(ctx context.Context, accountID uint) ([]ScheduledTransaction, error) {
+	sts := make([]ScheduledTransaction, 0)
+	for _, st := range msr.ScheduledTransactions {
+		if st.AccountID == accountID {
+			sts = append(sts, st)
+		}
+	}
+	return sts, nil
+}
+
+func (msr *MockStorageRepository) ListDueScheduledTransactions(ctx context.Context, at time.Time) ([]ScheduledTransaction, error) {
+	sts := make([]ScheduledTransaction, 0)
+	for _, st := range msr.ScheduledTransactions {
+		if st.Status == ActiveStatus && st.NextRunAt != nil && !st.NextRunAt.After(at) {
+			sts = append(sts, st)
+		}
+	}
+	return sts, nil
+}
+
+func (msr *MockStorageRepository) UpdateScheduledTransaction(ctx context.Context, st ScheduledTransaction) error {
+	msr.NCalledUpdateScheduledTransaction += 1
+	if msr.UpdateScheduledTransactionErrResult != nil {
+		return msr.UpdateScheduledTransactionErrResult
+	}
+	st.Version += 1
+	msr.ScheduledTransactions[st.ID] = st
+	return nil
+}
+
+type MockAccountUseCase struct {
+	account.UseCase
+
+	Keys                    map[string]bool
+	CreateTransactionResult error
+	Transactions            []account.CreateTransactionOptions
+}
+
+func (mau *MockAccountUseCase) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	return account.Account{ID: accountID}, nil
+}
+
+func (mau *MockAccountUseCase) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	if mau.CreateTransactionResult != nil {
+		return 0, mau.CreateTransactionResult
+	}
+
+	if mau.Keys[opts.IdempotencyKey] {
+		return 0, account.NewInfraError(account.InfraTransactionDuplicatedErrorCode, "transaction already exists")
+	}
+	mau.Keys[opts.IdempotencyKey] = true
+	mau.Transactions = append(mau.Transactions, opts)
+
+	return uint(len(mau.Transactions)), nil
+}
+
+type MockAccountStorageRepository struct {
+	account.StorageRepository
+
+	Transactions []account.CreateTransactionOptions
+}
+
+func (masr *MockAccountStorageRepository) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	return account.Account{ID: accountID}, nil
+}
+
+func (masr *MockAccountStorageRepository) HasOperationTypeByID(ctx context.Context, operationTypeID uint) (bool, error) {
+	return true, nil
+}
+
+func (masr *MockAccountStorageRepository) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	masr.Transactions = append(masr.Transactions, opts)
+	return uint(len(masr.Transactions)), nil
+}
+
+func TestCreateScheduledTransaction(t *testing.T) {
+	now := time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC)
+	endAt := now.AddDate(0, 0, -1)
+
+	suite := []struct {
+		Options           CreateScheduledTransactionOptions
+		ExpectedErrorCode account.ErrorCode
+	}{
+		{
+			Options: CreateScheduledTransactionOptions{AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: now.AddDate(0, 0, 1)},
+		},
+		{
+			Options:           CreateScheduledTransactionOptions{AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: now.AddDate(0, 0, -1)},
+			ExpectedErrorCode: account.DomainInvalidScheduleErrorCode,
+		},
+		{
+			Options:           CreateScheduledTransactionOptions{AccountID: 1, OperationTypeID: 4, Amount: 0, StartAt: now},
+			ExpectedErrorCode: account.DomainInvalidScheduleErrorCode,
+		},
+		{
+			Options:           CreateScheduledTransactionOptions{AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: now, Recurrence: &Recurrence{Frequency: "yearly", Interval: 1}},
+			ExpectedErrorCode: account.DomainInvalidScheduleErrorCode,
+		},
+		{
+			Options:           CreateScheduledTransactionOptions{AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: now, EndAt: &endAt},
+			ExpectedErrorCode: account.DomainInvalidScheduleErrorCode,
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{HasOperationTypeByIDResult: true, ScheduledTransactions: make(map[uint]ScheduledTransaction)}
+		svc := NewUseCaseService(mock, &MockAccountUseCase{}, &FakeClock{now}, nil)
+
+		st, err := svc.CreateScheduledTransaction(context.Background(), s.Options)
+		if s.ExpectedErrorCode != "" {
+			cerr, ok := err.(*account.DomainError)
+			if !ok {
+				t.Errorf("unexpected error, got: %v", err)
+				return
+			}
+
+			if got, expected := cerr.Code, s.ExpectedErrorCode; got != expected {
+				t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+				return
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := *st.NextRunAt, s.Options.StartAt; !got.Equal(expected) {
+			t.Errorf("unexpected next run, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestRunDueScheduledTransactions(t *testing.T) {
+	start := time.Date(2023, time.January, 5, 0, 0, 0, 0, time.UTC)
+	next := start
+
+	mock := &MockStorageRepository{ScheduledTransactions: map[uint]ScheduledTransaction{
+		1: {
+			ID:              1,
+			AccountID:       1,
+			OperationTypeID: 4,
+			Amount:          10_00,
+			StartAt:         start,
+			Recurrence:      &Recurrence{Frequency: MonthlyFrequency, Interval: 1},
+			MaxOccurrences:  3,
+			NextRunAt:       &next,
+			Status:          ActiveStatus,
+		},
+	}}
+	accounts := &MockAccountUseCase{Keys: make(map[string]bool)}
+	svc := NewUseCaseService(mock, accounts, &FakeClock{}, nil)
+
+	result, err := svc.RunDueScheduledTransactions(context.Background(), RunDueOptions{At: time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := result.Created, 2; got != expected {
+		t.Errorf("unexpected created, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	st := mock.ScheduledTransactions[1]
+	if got, expected := *st.NextRunAt, time.Date(2023, time.March, 5, 0, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("unexpected next run, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	result, err = svc.RunDueScheduledTransactions(context.Background(), RunDueOptions{At: time.Date(2023, time.June, 10, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := result.Created, 1; got != expected {
+		t.Errorf("unexpected created, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := mock.ScheduledTransactions[1].Status, CompletedStatus; got != expected {
+		t.Errorf("unexpected status, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := accounts.Transactions[2].IdempotencyKey, "scheduled:1:2"; got != expected {
+		t.Errorf("unexpected idempotency key, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestRunDueScheduledTransactionsOlderThanMaxBackdate(t *testing.T) {
+	now := time.Date(2023, time.March, 10, 0, 0, 0, 0, time.UTC)
+	next := now.Add(-10 * 24 * time.Hour)
+
+	mock := &MockStorageRepository{ScheduledTransactions: map[uint]ScheduledTransaction{
+		1: {ID: 1, AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: next, NextRunAt: &next, Status: ActiveStatus},
+	}}
+	storage := &MockAccountStorageRepository{}
+	clock := &FakeClock{At: now}
+	accounts := account.NewUseCaseService(storage, nil, account.EventDateWindow{MaxBackdate: 168 * time.Hour}, clock, nil)
+	svc := NewUseCaseService(mock, accounts, clock, nil)
+
+	result, err := svc.RunDueScheduledTransactions(context.Background(), RunDueOptions{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := len(result.Failures), 0; got != expected {
+		t.Errorf("unexpected failures, got: %v, expected: %v", result.Failures, expected)
+		return
+	}
+
+	if got, expected := result.Created, 1; got != expected {
+		t.Errorf("unexpected created, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := storage.Transactions[0].EventDate, next; !got.Equal(expected) {
+		t.Errorf("unexpected event date, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestRunDueScheduledTransactionsAlreadyMaterialized(t *testing.T) {
+	start := time.Date(2023, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	mock := &MockStorageRepository{ScheduledTransactions: map[uint]ScheduledTransaction{
+		1: {ID: 1, AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: start, NextRunAt: &start, Status: ActiveStatus},
+	}}
+	accounts := &MockAccountUseCase{Keys: map[string]bool{"scheduled:1:0": true}}
+	svc := NewUseCaseService(mock, accounts, &FakeClock{}, nil)
+
+	result, err := svc.RunDueScheduledTransactions(context.Background(), RunDueOptions{At: start})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := result, (RunDueResult{Skipped: 1}); got.Created != expected.Created || got.Skipped != expected.Skipped {
+		t.Errorf("unexpected result, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := mock.ScheduledTransactions[1].Status, CompletedStatus; got != expected {
+		t.Errorf("unexpected status, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestRunDueScheduledTransactionsDeclined(t *testing.T) {
+	start := time.Date(2023, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	mock := &MockStorageRepository{ScheduledTransactions: map[uint]ScheduledTransaction{
+		1: {ID: 1, AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: start, NextRunAt: &start, Status: ActiveStatus},
+	}}
+	accounts := &MockAccountUseCase{
+		Keys:                    make(map[string]bool),
+		CreateTransactionResult: account.NewDomainError(account.DomainTransactionDeclinedErrorCode, "transaction declined"),
+	}
+	svc := NewUseCaseService(mock, accounts, &FakeClock{}, nil)
+
+	result, err := svc.RunDueScheduledTransactions(context.Background(), RunDueOptions{At: start})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := len(result.Failures), 1; got != expected {
+		t.Errorf("unexpected failures, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := mock.NCalledUpdateScheduledTransaction, 1; got != expected {
+		t.Errorf("unexpected N called UpdateScheduledTransaction, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestRunDueScheduledTransactionsOutdated(t *testing.T) {
+	start := time.Date(2023, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	mock := &MockStorageRepository{
+		ScheduledTransactions: map[uint]ScheduledTransaction{
+			1: {ID: 1, AccountID: 1, OperationTypeID: 4, Amount: 10_00, StartAt: start, Recurrence: &Recurrence{Frequency: DailyFrequency, Interval: 1}, NextRunAt: &start, Status: ActiveStatus},
+		},
+		UpdateScheduledTransactionErrResult: account.NewInfraError(account.InfraScheduleOutdatedErrorCode, "scheduled transaction outdated"),
+	}
+	accounts := &MockAccountUseCase{Keys: make(map[string]bool)}
+	svc := NewUseCaseService(mock, accounts, &FakeClock{}, nil)
+
+	result, err := svc.RunDueScheduledTransactions(context.Background(), RunDueOptions{At: start.AddDate(0, 0, 5)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := result.Created, 1; got != expected {
+		t.Errorf("unexpected created, got: %v, expected: %v", got, expected)
+		return
+	}
+}
@@ -0,0 +1,41 @@
+package statement
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+// From and To are calendar days, both inclusive, read in the account's time zone.
+type ListDailyTotalsOptions struct {
+	AccountID uint
+	From      time.Time
+	To        time.Time
+}
+
+// From and To follow the same rules as in ListDailyTotalsOptions.
+type WriteStatementOptions struct {
+	AccountID uint
+	From      time.Time
+	To        time.Time
+}
+
+// Writer renders a statement while it's read, Begin is called once before the transactions and End once after them.
+type Writer interface {
+	Begin(Statement) error
+	Transaction(account.Transaction) error
+	End(Statement) error
+}
+
+type StorageRepository interface {
+	GetAccountByID(context.Context, uint) (account.Account, error)
+	SumTransactionsByDay(ctx context.Context, accountID uint, from, to time.Time, loc *time.Location) ([]DailyTotal, error)
+	SumTransactionsBefore(ctx context.Context, accountID uint, before time.Time) (int64, error)
+	StreamTransactions(ctx context.Context, accountID uint, from, to time.Time, fn func(account.Transaction) error) error
+}
+
+type UseCase interface {
+	ListDailyTotals(context.Context, ListDailyTotalsOptions) ([]DailyTotal, error)
+	WriteStatement(context.Context, WriteStatementOptions, Writer) error
+}
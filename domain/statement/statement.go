@@ -0,0 +1,25 @@
+package statement
+
+import (
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+// DailyTotal sums the transactions of a day in the account's time zone, Date is that day's midnight.
+type DailyTotal struct {
+	Date   time.Time
+	Amount int64
+	Count  int
+}
+
+// Statement describes the transactions of an account between the calendar days From and To, read in the
+// account's time zone. ClosingBalance and Count are only known once every transaction was written.
+type Statement struct {
+	Account        account.Account
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	Count          int
+}
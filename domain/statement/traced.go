@@ -0,0 +1,33 @@
+package statement
+
+import (
+	"context"
+
+	"github/guiferpa/bank/domain/trace"
+)
+
+// TracedUseCase starts a span around every call to the wrapped use case.
+type TracedUseCase struct {
+	UseCase
+	tracer trace.Tracer
+}
+
+func (tuc *TracedUseCase) ListDailyTotals(ctx context.Context, opts ListDailyTotalsOptions) ([]DailyTotal, error) {
+	ctx, span := tuc.tracer.Start(ctx, "statement.ListDailyTotals")
+	result, err := tuc.UseCase.ListDailyTotals(ctx, opts)
+	span.End(err)
+
+	return result, err
+}
+
+func (tuc *TracedUseCase) WriteStatement(ctx context.Context, opts WriteStatementOptions, w Writer) error {
+	ctx, span := tuc.tracer.Start(ctx, "statement.WriteStatement")
+	err := tuc.UseCase.WriteStatement(ctx, opts, w)
+	span.End(err)
+
+	return err
+}
+
+func NewTracedUseCase(usecase UseCase, tracer trace.Tracer) *TracedUseCase {
+	return &TracedUseCase{usecase, tracer}
+}
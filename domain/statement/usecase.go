@@ -0,0 +1,118 @@
+package statement
+
+import (
+	"context"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/clock"
+	"github/guiferpa/bank/domain/consistency"
+	"github/guiferpa/bank/domain/log"
+)
+
+const DefaultDays = 30
+
+type UseCaseService struct {
+	storage StorageRepository
+	clock   clock.Clock
+	logger  log.LoggerRepository
+}
+
+func midnight(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
+
+// dateRange turns the calendar days from and to, defaulting to the last DefaultDays days, into the
+// instants [start, end) they cover in loc.
+func dateRange(from, to, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = now.In(loc)
+	}
+
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -DefaultDays+1)
+	}
+
+	start, end := midnight(from, loc), midnight(to, loc).AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, account.NewDomainError(account.DomainInvalidDateRangeErrorCode, "from can't be after to")
+	}
+
+	return start, end, nil
+}
+
+func (ucs *UseCaseService) ListDailyTotals(ctx context.Context, opts ListDailyTotalsOptions) ([]DailyTotal, error) {
+	acc, err := ucs.storage.GetAccountByID(ctx, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := account.Authorize(ctx, acc); err != nil {
+		return nil, err
+	}
+
+	loc := acc.Location()
+	start, end, err := dateRange(opts.From, opts.To, ucs.clock.Now(), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := ucs.storage.SumTransactionsByDay(ctx, acc.ID, start, end, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// WriteStatement streams the account's transactions to w without holding them in memory, the closing
+// balance is the opening one plus every transaction written. Its reads are pinned to a single replica, for the
+// opening balance and the transactions to be taken from the same point.
+func (ucs *UseCaseService) WriteStatement(ctx context.Context, opts WriteStatementOptions, w Writer) error {
+	ctx = consistency.WithPin(ctx)
+
+	acc, err := ucs.storage.GetAccountByID(ctx, opts.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if err := account.Authorize(ctx, acc); err != nil {
+		return err
+	}
+
+	loc := acc.Location()
+	start, end, err := dateRange(opts.From, opts.To, ucs.clock.Now(), loc)
+	if err != nil {
+		return err
+	}
+
+	opening, err := ucs.storage.SumTransactionsBefore(ctx, acc.ID, start)
+	if err != nil {
+		return err
+	}
+
+	st := Statement{
+		Account:        acc,
+		From:           start,
+		To:             end.AddDate(0, 0, -1),
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+	}
+	if err := w.Begin(st); err != nil {
+		return err
+	}
+
+	if err := ucs.storage.StreamTransactions(ctx, acc.ID, start, end, func(tx account.Transaction) error {
+		st.ClosingBalance += tx.Amount
+		st.Count += 1
+		return w.Transaction(tx)
+	}); err != nil {
+		return err
+	}
+
+	return w.End(st)
+}
+
+func NewUseCaseService(storage StorageRepository, clock clock.Clock, logger log.LoggerRepository) *UseCaseService {
+	return &UseCaseService{storage, clock, logger}
+}
@@ -0,0 +1,200 @@
+package statement
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type FakeClock struct {
+	At time.Time
+}
+
+func (fc *FakeClock) Now() time.Time {
+	return fc.At
+}
+
+type MockStorageRepository struct {
+	Account      account.Account
+	Opening      int64
+	Transactions []account.Transaction
+	From         time.Time
+	To           time.Time
+	Before       time.Time
+}
+
+func (msr *MockStorageRepository) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	return msr.Account, nil
+}
+
+func (msr *MockStorageRepository) SumTransactionsByDay(ctx context.Context, accountID uint, from, to time.Time, loc *time.Location) ([]DailyTotal, error) {
+	msr.From, msr.To = from, to
+	return []DailyTotal{}, nil
+}
+
+func (msr *MockStorageRepository) SumTransactionsBefore(ctx context.Context, accountID uint, before time.Time) (int64, error) {
+	msr.Before = before
+	return msr.Opening, nil
+}
+
+func (msr *MockStorageRepository) StreamTransactions(ctx context.Context, accountID uint, from, to time.Time, fn func(account.Transaction) error) error {
+	msr.From, msr.To = from, to
+	for _, tx := range msr.Transactions {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type RecorderWriter struct {
+	Begun        Statement
+	Ended        Statement
+	Transactions []account.Transaction
+}
+
+func (rw *RecorderWriter) Begin(st Statement) error {
+	rw.Begun = st
+	return nil
+}
+
+func (rw *RecorderWriter) Transaction(tx account.Transaction) error {
+	rw.Transactions = append(rw.Transactions, tx)
+	return nil
+}
+
+func (rw *RecorderWriter) End(st Statement) error {
+	rw.Ended = st
+	return nil
+}
+
+func TestListDailyTotals(t *testing.T) {
+	now := time.Date(2023, time.March, 10, 1, 0, 0, 0, time.UTC)
+
+	suite := []struct {
+		TimeZone     string
+		From         time.Time
+		To           time.Time
+		ExpectedFrom time.Time
+		ExpectedTo   time.Time
+	}{
+		{
+			TimeZone:     "America/Sao_Paulo",
+			From:         time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			To:           time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			ExpectedFrom: time.Date(2023, time.March, 1, 3, 0, 0, 0, time.UTC),
+			ExpectedTo:   time.Date(2023, time.March, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			TimeZone:     "America/Sao_Paulo",
+			ExpectedFrom: time.Date(2023, time.February, 8, 3, 0, 0, 0, time.UTC),
+			ExpectedTo:   time.Date(2023, time.March, 10, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			TimeZone:     "",
+			ExpectedFrom: time.Date(2023, time.February, 9, 0, 0, 0, 0, time.UTC),
+			ExpectedTo:   time.Date(2023, time.March, 11, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, s := range suite {
+		mock := &MockStorageRepository{Account: account.Account{ID: 1, TimeZone: s.TimeZone}}
+		svc := NewUseCaseService(mock, &FakeClock{now}, nil)
+
+		if _, err := svc.ListDailyTotals(context.Background(), ListDailyTotalsOptions{AccountID: 1, From: s.From, To: s.To}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := mock.From, s.ExpectedFrom; !got.Equal(expected) {
+			t.Errorf("unexpected from, got: %v, expected: %v", got, expected)
+			return
+		}
+
+		if got, expected := mock.To, s.ExpectedTo; !got.Equal(expected) {
+			t.Errorf("unexpected to, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestListDailyTotalsWithInvalidRange(t *testing.T) {
+	mock := &MockStorageRepository{Account: account.Account{ID: 1}}
+	svc := NewUseCaseService(mock, &FakeClock{}, nil)
+
+	_, err := svc.ListDailyTotals(context.Background(), ListDailyTotalsOptions{
+		AccountID: 1,
+		From:      time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+	})
+	cerr, ok := err.(*account.DomainError)
+	if !ok {
+		t.Errorf("unexpected error, got: %v", err)
+		return
+	}
+
+	if got, expected := cerr.Code, account.DomainInvalidDateRangeErrorCode; got != expected {
+		t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestWriteStatement(t *testing.T) {
+	mock := &MockStorageRepository{
+		Account: account.Account{ID: 1, TimeZone: "America/Sao_Paulo"},
+		Opening: 10000,
+		Transactions: []account.Transaction{
+			{ID: 1, AccountID: 1, OperationTypeID: 1, Amount: -2550},
+			{ID: 2, AccountID: 1, OperationTypeID: 4, Amount: 5000},
+		},
+	}
+	svc := NewUseCaseService(mock, &FakeClock{}, nil)
+
+	writer := &RecorderWriter{}
+	err := svc.WriteStatement(context.Background(), WriteStatementOptions{
+		AccountID: 1,
+		From:      time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2023, time.March, 31, 0, 0, 0, 0, time.UTC),
+	}, writer)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := mock.Before, time.Date(2023, time.March, 1, 3, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("unexpected opening balance instant, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := mock.To, time.Date(2023, time.April, 1, 3, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("unexpected to, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := writer.Begun.OpeningBalance, int64(10000); got != expected {
+		t.Errorf("unexpected opening balance, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := writer.Begun.To.Format("2006-01-02"), "2023-03-31"; got != expected {
+		t.Errorf("unexpected statement last day, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := len(writer.Transactions), 2; got != expected {
+		t.Errorf("unexpected transactions written, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := writer.Ended.ClosingBalance, int64(12450); got != expected {
+		t.Errorf("unexpected closing balance, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := writer.Ended.Count, 2; got != expected {
+		t.Errorf("unexpected count, got: %v, expected: %v", got, expected)
+		return
+	}
+}
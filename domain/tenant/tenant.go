@@ -0,0 +1,16 @@
+package tenant
+
+import "context"
+
+type tenantKey struct{}
+
+// NewContext scopes what's done with ctx to the accounts created by the partner tenant id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext tells the tenant ctx is scoped to, the bank's own callers and jobs have none and see every account.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok
+}
@@ -0,0 +1,13 @@
+package trace
+
+import "context"
+
+// Tracer starts spans as children of the one carried by ctx, if any.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span records err, when it's not nil, as the outcome of the operation it ends.
+type Span interface {
+	End(err error)
+}
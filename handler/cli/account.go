@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type AccountOutput struct {
+	ID             uint   `json:"id"`
+	CustomerID     uint   `json:"customer_id"`
+	DocumentNumber string `json:"document_number"`
+	ClosingDay     int    `json:"closing_day"`
+	DueDay         int    `json:"due_day"`
+	TimeZone       string `json:"time_zone"`
+	CreatedAt      string `json:"created_at"`
+}
+
+func outputAccount(w io.Writer, format string, acc account.Account) error {
+	out := AccountOutput{
+		ID:             acc.ID,
+		CustomerID:     acc.CustomerID,
+		DocumentNumber: acc.DocumentNumber,
+		ClosingDay:     acc.ClosingDay,
+		DueDay:         acc.DueDay,
+		TimeZone:       acc.TimeZone,
+		CreatedAt:      acc.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	return output(w, format, out, table{
+		Header: []string{"ID", "CUSTOMER", "DOCUMENT NUMBER", "CLOSING DAY", "DUE DAY", "TIME ZONE", "CREATED AT"},
+		Rows: [][]string{{
+			strconv.FormatUint(uint64(out.ID), 10),
+			strconv.FormatUint(uint64(out.CustomerID), 10),
+			out.DocumentNumber,
+			strconv.Itoa(out.ClosingDay),
+			strconv.Itoa(out.DueDay),
+			out.TimeZone,
+			out.CreatedAt,
+		}},
+	})
+}
+
+func CreateAccount(usecase account.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "create-account",
+		Description: "Create an account and print it",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("create-account", flag.ContinueOnError)
+			documentNumber := flags.String("document-number", "", "holder's document number")
+			customerID := flags.Uint("customer", 0, "customer holding the account, defaults to the one with the same document number")
+			closingDay := flags.Int("closing-day", 0, "billing closing day")
+			dueDay := flags.Int("due-day", 0, "billing due day")
+			timeZone := flags.String("time-zone", "", "IANA time zone, defaults to UTC")
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if *documentNumber == "" {
+				return errors.New("missing flag -document-number")
+			}
+
+			accountID, err := usecase.CreateAccount(ctx, account.CreateAccountOptions{
+				CustomerID:     *customerID,
+				DocumentNumber: *documentNumber,
+				ClosingDay:     *closingDay,
+				DueDay:         *dueDay,
+				TimeZone:       *timeZone,
+			})
+			if err != nil {
+				return err
+			}
+
+			acc, err := usecase.GetAccountByID(ctx, accountID)
+			if err != nil {
+				return err
+			}
+
+			return outputAccount(stdout, *format, acc)
+		},
+	}
+}
+
+func GetAccount(usecase account.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "get-account",
+		Description: "Print an account",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("get-account", flag.ContinueOnError)
+			accountID := flags.Uint("id", 0, "account id")
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			acc, err := usecase.GetAccountByID(ctx, *accountID)
+			if err != nil {
+				return err
+			}
+
+			return outputAccount(stdout, *format, acc)
+		},
+	}
+}
+
+type TransactionOutput struct {
+	ID uint `json:"id"`
+}
+
+func PostTransaction(usecase account.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "post-transaction",
+		Description: "Create a transaction, checked by the account rules",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("post-transaction", flag.ContinueOnError)
+			accountID := flags.Uint("account", 0, "account id")
+			operationTypeID := flags.Uint("operation-type", 0, "operation type id")
+			amount := flags.String("amount", "", "amount as a decimal, e.g. -12.50")
+			eventDate := flags.String("event-date", "", "event date formatted as RFC 3339, defaults to now")
+			idempotencyKey := flags.String("idempotency-key", "", "key that makes retries safe")
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			cents, ok := parseCents(*amount)
+			if !ok || cents == 0 {
+				return fmt.Errorf("invalid value for flag -amount: %q", *amount)
+			}
+
+			opts := account.CreateTransactionOptions{
+				AccountID:       *accountID,
+				OperationTypeID: *operationTypeID,
+				Amount:          cents,
+				IdempotencyKey:  *idempotencyKey,
+			}
+			if *eventDate != "" {
+				t, err := time.Parse(time.RFC3339, *eventDate)
+				if err != nil {
+					return fmt.Errorf("invalid value for flag -event-date: %w", err)
+				}
+				opts.EventDate = t
+			}
+
+			transID, err := usecase.CreateTransaction(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			return output(stdout, *format, TransactionOutput{transID}, table{
+				Header: []string{"ID"},
+				Rows:   [][]string{{strconv.FormatUint(uint64(transID), 10)}},
+			})
+		},
+	}
+}
+
+type OperationTypeOutput struct {
+	ID          uint   `json:"id"`
+	Description string `json:"description"`
+}
+
+func ListOperationTypes(usecase account.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "list-operation-types",
+		Description: "Print every operation type",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("list-operation-types", flag.ContinueOnError)
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			ots, err := usecase.ListOperationTypes(ctx)
+			if err != nil {
+				return err
+			}
+
+			out, t := make([]OperationTypeOutput, 0, len(ots)), table{Header: []string{"ID", "DESCRIPTION"}}
+			for _, ot := range ots {
+				out = append(out, OperationTypeOutput{ot.ID, ot.Description})
+				t.Rows = append(t.Rows, []string{strconv.FormatUint(uint64(ot.ID), 10), ot.Description})
+			}
+
+			return output(stdout, *format, out, t)
+		},
+	}
+}
+
+type BalanceOutput struct {
+	AccountID uint        `json:"account_id"`
+	Balance   json.Number `json:"balance"`
+	At        string      `json:"at"`
+}
+
+func PrintBalances(usecase account.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "balances",
+		Description: "Print the balance of the given accounts",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("balances", flag.ContinueOnError)
+			at := flags.String("at", "", "reference time formatted as RFC 3339, defaults to now")
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if flags.NArg() == 0 {
+				return errors.New("balances takes at least one account id")
+			}
+
+			ref := time.Now().UTC()
+			if *at != "" {
+				t, err := time.Parse(time.RFC3339, *at)
+				if err != nil {
+					return fmt.Errorf("invalid value for flag -at: %w", err)
+				}
+				ref = t
+			}
+
+			out, t := make([]BalanceOutput, 0, flags.NArg()), table{Header: []string{"ACCOUNT", "BALANCE", "AT"}}
+			for _, arg := range flags.Args() {
+				accountID, err := strconv.ParseUint(arg, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid account id %q", arg)
+				}
+
+				balance, err := usecase.GetBalance(ctx, uint(accountID), ref)
+				if err != nil {
+					return fmt.Errorf("account %d: %w", accountID, err)
+				}
+
+				b := BalanceOutput{uint(accountID), json.Number(formatCents(balance)), ref.Format(time.RFC3339)}
+				out = append(out, b)
+				t.Rows = append(t.Rows, []string{arg, b.Balance.String(), b.At})
+			}
+
+			return output(stdout, *format, out, t)
+		},
+	}
+}
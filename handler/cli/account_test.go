@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+func TestAccountCommandsOutput(t *testing.T) {
+	mock := &MockAccountUseCase{}
+
+	suite := []struct {
+		Command  func(account.UseCase, io.Writer) Command
+		Args     []string
+		Expected string
+	}{
+		{
+			Command:  ListOperationTypes,
+			Args:     []string{},
+			Expected: "ID  DESCRIPTION\n1   COMPRA A VISTA\n4   PAGAMENTO\n",
+		},
+		{
+			Command:  ListOperationTypes,
+			Args:     []string{"-output", "json"},
+			Expected: "[\n  {\n    \"id\": 1,\n    \"description\": \"COMPRA A VISTA\"\n  },\n  {\n    \"id\": 4,\n    \"description\": \"PAGAMENTO\"\n  }\n]\n",
+		},
+		{
+			Command:  PrintBalances,
+			Args:     []string{"-at", "2023-03-01T00:00:00Z", "1"},
+			Expected: "ACCOUNT  BALANCE   AT\n1        -1234.56  2023-03-01T00:00:00Z\n",
+		},
+		{
+			Command:  PrintBalances,
+			Args:     []string{"-at", "2023-03-01T00:00:00Z", "-output", "json", "1"},
+			Expected: "[\n  {\n    \"account_id\": 1,\n    \"balance\": -1234.56,\n    \"at\": \"2023-03-01T00:00:00Z\"\n  }\n]\n",
+		},
+	}
+
+	for _, s := range suite {
+		stdout := &bytes.Buffer{}
+		cmd := s.Command(mock, stdout)
+		if err := cmd.Run(context.Background(), s.Args); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := stdout.String(), s.Expected; got != expected {
+			t.Errorf("unexpected %s output, got:\n%v\nexpected:\n%v", cmd.Name, got, expected)
+			return
+		}
+	}
+}
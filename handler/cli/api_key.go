@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/auth"
+)
+
+type APIKeyOutput struct {
+	ID        uint     `json:"id"`
+	Name      string   `json:"name"`
+	Prefix    string   `json:"prefix"`
+	Scopes    []string `json:"scopes"`
+	Tenant    string   `json:"tenant,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	RevokedAt string   `json:"revoked_at,omitempty"`
+	// Key and SigningSecret are only known when it's created.
+	Key           string `json:"key,omitempty"`
+	SigningSecret string `json:"signing_secret,omitempty"`
+}
+
+// row is the columns shared by the tables of every API key command.
+func (out APIKeyOutput) row() []string {
+	tenant := out.Tenant
+	if tenant == "" {
+		tenant = "-"
+	}
+
+	return []string{strconv.FormatUint(uint64(out.ID), 10), out.Name, out.Prefix, strings.Join(out.Scopes, ","), tenant, out.CreatedAt}
+}
+
+func toAPIKeyOutput(key auth.APIKey) APIKeyOutput {
+	out := APIKeyOutput{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    make([]string, 0, len(key.Scopes)),
+		Tenant:    key.Tenant,
+		CreatedAt: key.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, scope := range key.Scopes {
+		out.Scopes = append(out.Scopes, string(scope))
+	}
+	if key.Revoked() {
+		out.RevokedAt = key.RevokedAt.UTC().Format(time.RFC3339)
+	}
+
+	return out
+}
+
+func scopeNames() string {
+	names := make([]string, 0, len(auth.Scopes))
+	for _, scope := range auth.Scopes {
+		names = append(names, string(scope))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func CreateAPIKey(usecase auth.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "create-api-key",
+		Description: "Create an API key and print it, the key isn't shown again",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("create-api-key", flag.ContinueOnError)
+			name := flags.String("name", "", "who or what the key is for")
+			scopes := flags.String("scopes", "", fmt.Sprintf("comma separated scopes granted to the key, out of %s", scopeNames()))
+			tenant := flags.String("tenant", "", "partner tenant the key is restricted to, none for the bank's own callers")
+			signing := flags.Bool("signing", false, "give the key a secret to sign requests with")
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if *name == "" {
+				return errors.New("missing flag -name")
+			}
+
+			if *scopes == "" {
+				return errors.New("missing flag -scopes")
+			}
+
+			opts := auth.CreateAPIKeyOptions{Name: *name, Tenant: strings.TrimSpace(*tenant), Signing: *signing}
+			for _, scope := range strings.Split(*scopes, ",") {
+				opts.Scopes = append(opts.Scopes, auth.Scope(strings.TrimSpace(scope)))
+			}
+
+			key, raw, err := usecase.CreateAPIKey(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			out := toAPIKeyOutput(key)
+			out.Key, out.SigningSecret = raw, key.SigningSecret
+
+			t := table{
+				Header: []string{"ID", "NAME", "PREFIX", "SCOPES", "TENANT", "CREATED AT", "KEY"},
+				Rows:   [][]string{append(out.row(), out.Key)},
+			}
+			if out.SigningSecret != "" {
+				t.Header = append(t.Header, "SIGNING SECRET")
+				t.Rows[0] = append(t.Rows[0], out.SigningSecret)
+			}
+
+			return output(stdout, *format, out, t)
+		},
+	}
+}
+
+func ListAPIKeys(usecase auth.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "list-api-keys",
+		Description: "List the API keys, revoked ones included",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("list-api-keys", flag.ContinueOnError)
+			format := flags.String("output", TableOutput, "output format, table or json")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			keys, err := usecase.ListAPIKeys(ctx)
+			if err != nil {
+				return err
+			}
+
+			outs := make([]APIKeyOutput, 0, len(keys))
+			t := table{Header: []string{"ID", "NAME", "PREFIX", "SCOPES", "TENANT", "CREATED AT", "REVOKED AT"}}
+			for _, key := range keys {
+				out := toAPIKeyOutput(key)
+				outs = append(outs, out)
+
+				revokedAt := out.RevokedAt
+				if revokedAt == "" {
+					revokedAt = "-"
+				}
+				t.Rows = append(t.Rows, append(out.row(), revokedAt))
+			}
+
+			return output(stdout, *format, outs, t)
+		},
+	}
+}
+
+func RevokeAPIKey(usecase auth.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "revoke-api-key",
+		Description: "Revoke an API key, requests with it are rejected from then on",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("revoke-api-key", flag.ContinueOnError)
+			id := flags.Uint("id", 0, "api key id")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if *id == 0 {
+				return errors.New("missing flag -id")
+			}
+
+			if err := usecase.RevokeAPIKey(ctx, *id); err != nil {
+				return err
+			}
+
+			_, err := fmt.Fprintf(stdout, "api key %d revoked\n", *id)
+			return err
+		},
+	}
+}
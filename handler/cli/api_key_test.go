@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/auth"
+)
+
+type MockAuthUseCase struct {
+	auth.UseCase
+}
+
+func (mauc *MockAuthUseCase) CreateAPIKey(ctx context.Context, opts auth.CreateAPIKeyOptions) (auth.APIKey, string, error) {
+	key := auth.APIKey{ID: 1, Name: opts.Name, Prefix: "a1b2c3", Scopes: opts.Scopes, Tenant: opts.Tenant, CreatedAt: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)}
+	if opts.Signing {
+		key.SigningSecret = "5ec2e7"
+	}
+
+	return key, "bank_a1b2c3_secret", nil
+}
+
+func (mauc *MockAuthUseCase) ListAPIKeys(ctx context.Context) ([]auth.APIKey, error) {
+	return []auth.APIKey{
+		{ID: 1, Name: "backoffice", Prefix: "a1b2c3", Scopes: auth.Scopes, CreatedAt: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "retired", Prefix: "d4e5f6", Scopes: []auth.Scope{auth.ReadAccountsScope}, Tenant: "acme", CreatedAt: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), RevokedAt: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}, nil
+}
+
+func (mauc *MockAuthUseCase) RevokeAPIKey(ctx context.Context, id uint) error {
+	return nil
+}
+
+func TestAPIKeyCommandsOutput(t *testing.T) {
+	mock := &MockAuthUseCase{}
+
+	suite := []struct {
+		Command  func(auth.UseCase, io.Writer) Command
+		Args     []string
+		Expected string
+	}{
+		{
+			Command:  CreateAPIKey,
+			Args:     []string{"-name", "partner", "-scopes", "accounts:read, transactions:write", "-tenant", "acme"},
+			Expected: "ID  NAME     PREFIX  SCOPES                            TENANT  CREATED AT            KEY\n1   partner  a1b2c3  accounts:read,transactions:write  acme    2023-03-01T00:00:00Z  bank_a1b2c3_secret\n",
+		},
+		{
+			Command:  CreateAPIKey,
+			Args:     []string{"-name", "partner", "-scopes", "accounts:read", "-signing"},
+			Expected: "ID  NAME     PREFIX  SCOPES         TENANT  CREATED AT            KEY                 SIGNING SECRET\n1   partner  a1b2c3  accounts:read  -       2023-03-01T00:00:00Z  bank_a1b2c3_secret  5ec2e7\n",
+		},
+		{
+			Command:  ListAPIKeys,
+			Args:     []string{},
+			Expected: "ID  NAME        PREFIX  SCOPES                                           TENANT  CREATED AT            REVOKED AT\n1   backoffice  a1b2c3  accounts:read,accounts:write,transactions:write  -       2023-03-01T00:00:00Z  -\n2   retired     d4e5f6  accounts:read                                    acme    2023-03-01T00:00:00Z  2023-03-02T00:00:00Z\n",
+		},
+		{
+			Command:  RevokeAPIKey,
+			Args:     []string{"-id", "2"},
+			Expected: "api key 2 revoked\n",
+		},
+	}
+
+	for _, s := range suite {
+		stdout := &bytes.Buffer{}
+		cmd := s.Command(mock, stdout)
+		if err := cmd.Run(context.Background(), s.Args); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := stdout.String(), s.Expected; got != expected {
+			t.Errorf("unexpected %s output, got:\n%v\nexpected:\n%v", cmd.Name, got, expected)
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github/guiferpa/bank/domain/charges"
+)
+
+func ApplyCharges(usecase charges.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "apply-charges",
+		Description: "Generate interest, late fee and IOF transactions due until the reference time",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("apply-charges", flag.ContinueOnError)
+			at := flags.String("at", "", "reference time formatted as RFC 3339, defaults to now")
+			accountID := flags.Uint("account", 0, "apply charges only for this account")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			opts := charges.ApplyChargesOptions{AccountID: *accountID}
+			if *at != "" {
+				t, err := time.Parse(time.RFC3339, *at)
+				if err != nil {
+					return fmt.Errorf("invalid value for flag -at: %w", err)
+				}
+				opts.At = t
+			}
+
+			result, err := usecase.ApplyCharges(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(stdout, "created: %d, skipped: %d\n", result.Created, result.Skipped)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+type Command struct {
+	Name        string
+	Description string
+	Run         func(ctx context.Context, args []string) error
+}
+
+type UnknownCommandError struct {
+	Name string
+}
+
+func (err *UnknownCommandError) Error() string {
+	if err.Name == "" {
+		return "missing command"
+	}
+
+	return fmt.Sprintf("unknown command %s", err.Name)
+}
+
+func Usage(w io.Writer, program string, commands ...Command) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", program)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.Name, cmd.Description)
+	}
+	tw.Flush()
+}
+
+func Run(ctx context.Context, args []string, commands ...Command) error {
+	if len(args) == 0 {
+		return &UnknownCommandError{}
+	}
+
+	for _, cmd := range commands {
+		if cmd.Name == args[0] {
+			return cmd.Run(ctx, args[1:])
+		}
+	}
+
+	return &UnknownCommandError{args[0]}
+}
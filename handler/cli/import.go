@@ -0,0 +1,560 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+const (
+	AccountsImportKind     = "accounts"
+	TransactionsImportKind = "transactions"
+)
+
+const (
+	CSVImportFormat   = "csv"
+	JSONLImportFormat = "jsonl"
+)
+
+const (
+	AcceptedImportStatus = "accepted"
+	RejectedImportStatus = "rejected"
+	SkippedImportStatus  = "skipped"
+)
+
+const DefaultCheckpointEvery = 100
+
+// importReader yields the fields of each line, a line that can't be read comes with a non-nil error
+// and the reader moves on to the next one. io.EOF ends the file.
+type importReader interface {
+	Next() (line int, fields map[string]string, err error)
+}
+
+type csvImportReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	return &csvImportReader{cr, header}, nil
+}
+
+func (cir *csvImportReader) Next() (int, map[string]string, error) {
+	record, err := cir.r.Read()
+	if err == io.EOF {
+		return 0, nil, err
+	}
+
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		return perr.StartLine, nil, account.NewHandlerInvalidFieldError(account.HandlerBadRequestErrorCode, perr.Err.Error(), "")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	line, _ := cir.r.FieldPos(0)
+	fields := make(map[string]string, len(record))
+	for i, value := range record {
+		if value != "" {
+			fields[cir.header[i]] = value
+		}
+	}
+
+	return line, fields, nil
+}
+
+type jsonlImportReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newJSONLImportReader(r io.Reader) *jsonlImportReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	return &jsonlImportReader{s: s}
+}
+
+// Next flattens merchant and metadata objects into the same merchant_<field> and metadata.<key>
+// names used by CSV columns.
+func (jir *jsonlImportReader) Next() (int, map[string]string, error) {
+	for jir.s.Scan() {
+		jir.line += 1
+
+		data := strings.TrimSpace(jir.s.Text())
+		if data == "" {
+			continue
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(data))
+		decoder.UseNumber()
+
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			return jir.line, nil, account.NewHandlerInvalidFieldError(account.HandlerBadRequestErrorCode, "invalid json line", "")
+		}
+
+		fields := make(map[string]string, len(object))
+		for key, value := range object {
+			if nested, ok := value.(map[string]interface{}); ok && (key == "merchant" || key == "metadata") {
+				prefix := "merchant_"
+				if key == "metadata" {
+					prefix = "metadata."
+				}
+
+				for k, v := range nested {
+					if err := setImportField(fields, prefix+k, v); err != nil {
+						return jir.line, nil, err
+					}
+				}
+				continue
+			}
+
+			if err := setImportField(fields, key, value); err != nil {
+				return jir.line, nil, err
+			}
+		}
+
+		return jir.line, fields, nil
+	}
+
+	if err := jir.s.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	return 0, nil, io.EOF
+}
+
+func setImportField(fields map[string]string, key string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+	case string:
+		fields[key] = v
+	case json.Number:
+		fields[key] = v.String()
+	case bool:
+		fields[key] = strconv.FormatBool(v)
+	default:
+		return account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "unsupported value", key)
+	}
+
+	return nil
+}
+
+var amountPattern = regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`)
+
+// parseCents reads a decimal amount like -12.5 as cents without going through floats.
+func parseCents(value string) (int64, bool) {
+	if !amountPattern.MatchString(value) {
+		return 0, false
+	}
+
+	units, cents, _ := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+	cents = (cents + "00")[:2]
+
+	amount, err := strconv.ParseInt(units+cents, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if strings.HasPrefix(value, "-") {
+		amount = -amount
+	}
+
+	return amount, true
+}
+
+// importFields takes fields out of a line keeping track of the ones left unknown.
+type importFields map[string]string
+
+func (f importFields) take(key string) string {
+	value := f[key]
+	delete(f, key)
+	return value
+}
+
+func (f importFields) uint(key string, required bool) (uint, error) {
+	value := f.take(key)
+	if value == "" {
+		if required {
+			return 0, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "missing value", key)
+		}
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "wrong type", key)
+	}
+
+	return uint(n), nil
+}
+
+func (f importFields) unknown() error {
+	for key := range f {
+		return account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "unknown field", key)
+	}
+
+	return nil
+}
+
+func toImportAccountOptions(fields importFields) (account.CreateAccountOptions, error) {
+	opts := account.CreateAccountOptions{
+		DocumentNumber: fields.take("document_number"),
+		TimeZone:       fields.take("time_zone"),
+	}
+
+	if opts.DocumentNumber == "" {
+		return opts, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "missing value", "document_number")
+	}
+
+	customerID, err := fields.uint("customer_id", false)
+	if err != nil {
+		return opts, err
+	}
+	opts.CustomerID = customerID
+
+	closingDay, err := fields.uint("closing_day", false)
+	if err != nil {
+		return opts, err
+	}
+	opts.ClosingDay = int(closingDay)
+
+	dueDay, err := fields.uint("due_day", false)
+	if err != nil {
+		return opts, err
+	}
+	opts.DueDay = int(dueDay)
+
+	return opts, fields.unknown()
+}
+
+func toImportTransactionOptions(fields importFields) (account.CreateTransactionOptions, error) {
+	opts := account.CreateTransactionOptions{IdempotencyKey: fields.take("idempotency_key")}
+
+	accountID, err := fields.uint("account_id", true)
+	if err != nil {
+		return opts, err
+	}
+	opts.AccountID = accountID
+
+	operationTypeID, err := fields.uint("operation_type_id", true)
+	if err != nil {
+		return opts, err
+	}
+	opts.OperationTypeID = operationTypeID
+
+	amount, ok := parseCents(fields.take("amount"))
+	if !ok || amount == 0 {
+		return opts, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "amount must be a non zero decimal with up to 2 places", "amount")
+	}
+	opts.Amount = amount
+
+	if value := fields.take("event_date"); value != "" {
+		eventDate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return opts, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "field event_date must be formatted as RFC 3339", "event_date")
+		}
+		opts.EventDate = eventDate
+	}
+
+	merchant := account.Merchant{
+		Name:       fields.take("merchant_name"),
+		MCC:        fields.take("merchant_mcc"),
+		City:       fields.take("merchant_city"),
+		Country:    fields.take("merchant_country"),
+		TerminalID: fields.take("merchant_terminal_id"),
+	}
+	if merchant != (account.Merchant{}) {
+		opts.Merchant = &merchant
+	}
+
+	for key, value := range fields {
+		if strings.HasPrefix(key, "metadata.") {
+			if opts.Metadata == nil {
+				opts.Metadata = make(map[string]string)
+			}
+			opts.Metadata[strings.TrimPrefix(key, "metadata.")] = value
+			delete(fields, key)
+		}
+	}
+
+	return opts, fields.unknown()
+}
+
+type importReportLine struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	ID     uint   `json:"id,omitempty"`
+	Error  error  `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Accepted int
+	Rejected int
+	Skipped  int
+}
+
+type importer struct {
+	usecase         account.UseCase
+	kind            string
+	source          string
+	dryRun          bool
+	checkpoint      string
+	checkpointEvery int
+}
+
+// importLine runs a line through the use case, a non-nil error means the import must stop and the
+// line wasn't processed.
+func (im *importer) importLine(ctx context.Context, line int, fields map[string]string) (importReportLine, error) {
+	report := importReportLine{Line: line}
+
+	var id uint
+	var err error
+	switch im.kind {
+	case AccountsImportKind:
+		var opts account.CreateAccountOptions
+		if opts, err = toImportAccountOptions(fields); err == nil {
+			id, err = im.usecase.CreateAccount(ctx, opts)
+		}
+	case TransactionsImportKind:
+		var opts account.CreateTransactionOptions
+		if opts, err = toImportTransactionOptions(fields); err == nil {
+			if opts.IdempotencyKey == "" {
+				opts.IdempotencyKey = fmt.Sprintf("import:%s:%d", im.source, line)
+			}
+			id, err = im.usecase.CreateTransaction(ctx, opts)
+		}
+	}
+
+	if cerr, ok := err.(*account.InfraError); ok {
+		switch cerr.Code {
+		case account.InfraTransactionDuplicatedErrorCode:
+			report.Status, report.Error = SkippedImportStatus, err
+			return report, nil
+		case account.InfraUnknownError:
+			return report, err
+		}
+	}
+
+	if err != nil {
+		report.Status, report.Error = RejectedImportStatus, err
+		return report, nil
+	}
+
+	report.Status, report.ID = AcceptedImportStatus, id
+
+	return report, nil
+}
+
+// run imports the lines after resumeAfter. The checkpoint is saved every checkpointEvery lines and
+// before returning, so lines reported after the last checkpoint may be reported again when resuming.
+func (im *importer) run(ctx context.Context, reader importReader, report *json.Encoder, resumeAfter int) (ImportResult, error) {
+	result, last, pending := ImportResult{}, resumeAfter, 0
+	save := func() error {
+		if im.dryRun || pending == 0 {
+			return nil
+		}
+		pending = 0
+		return writeCheckpoint(im.checkpoint, last)
+	}
+
+	for {
+		line, fields, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var rl importReportLine
+		switch {
+		case line > 0 && line <= resumeAfter:
+			continue
+		case err != nil && line == 0:
+			if serr := save(); serr != nil {
+				return result, serr
+			}
+			return result, err
+		case err != nil:
+			rl = importReportLine{Line: line, Status: RejectedImportStatus, Error: err}
+		default:
+			if rl, err = im.importLine(ctx, line, fields); err != nil {
+				if serr := save(); serr != nil {
+					return result, serr
+				}
+				return result, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+
+		switch rl.Status {
+		case AcceptedImportStatus:
+			result.Accepted += 1
+		case RejectedImportStatus:
+			result.Rejected += 1
+		case SkippedImportStatus:
+			result.Skipped += 1
+		}
+
+		if err := report.Encode(rl); err != nil {
+			return result, err
+		}
+
+		last, pending = line, pending+1
+		if pending >= im.checkpointEvery {
+			if err := save(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, save()
+}
+
+func readCheckpoint(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	line, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+
+	return line, nil
+}
+
+// writeCheckpoint replaces the checkpoint at once, so a crash never leaves it half written.
+func writeCheckpoint(path string, line int) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(line)+"\n"), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func Import(usecase, dryRunUseCase account.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "import",
+		Description: "Import accounts or transactions from a CSV or JSONL file, resuming from the last checkpoint",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("import", flag.ContinueOnError)
+			kind := flags.String("kind", TransactionsImportKind, "what the file holds, accounts or transactions")
+			format := flags.String("format", "", "file format, csv or jsonl, defaults to the file extension")
+			dryRun := flags.Bool("dry-run", false, "validate every line without storing anything")
+			checkpoint := flags.String("checkpoint", "", "checkpoint file, defaults to <file>.checkpoint")
+			checkpointEvery := flags.Int("checkpoint-every", DefaultCheckpointEvery, "lines processed between checkpoints")
+			reportPath := flags.String("report", "", "report file with the result of every line, defaults to <file>.report.jsonl")
+			source := flags.String("source", "", "name used in the default idempotency keys, defaults to the file name")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if flags.NArg() != 1 {
+				return errors.New("import takes exactly one file")
+			}
+			path := flags.Arg(0)
+
+			if *kind != AccountsImportKind && *kind != TransactionsImportKind {
+				return fmt.Errorf("invalid value for flag -kind: %s", *kind)
+			}
+
+			if *format == "" {
+				*format = strings.TrimPrefix(filepath.Ext(path), ".")
+			}
+
+			if *checkpoint == "" {
+				*checkpoint = path + ".checkpoint"
+			}
+
+			if *reportPath == "" {
+				*reportPath = path + ".report.jsonl"
+			}
+
+			if *source == "" {
+				*source = filepath.Base(path)
+			}
+
+			if *checkpointEvery < 1 {
+				*checkpointEvery = 1
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			var reader importReader
+			switch *format {
+			case CSVImportFormat:
+				if reader, err = newCSVImportReader(file); err != nil {
+					return err
+				}
+			case JSONLImportFormat:
+				reader = newJSONLImportReader(file)
+			default:
+				return fmt.Errorf("invalid value for flag -format: %s", *format)
+			}
+
+			im := &importer{usecase: usecase, kind: *kind, source: *source, dryRun: *dryRun, checkpoint: *checkpoint, checkpointEvery: *checkpointEvery}
+			if *dryRun {
+				im.usecase = dryRunUseCase
+			}
+
+			// A dry run always starts from the beginning and leaves the checkpoint alone.
+			resumeAfter := 0
+			if !*dryRun {
+				if resumeAfter, err = readCheckpoint(*checkpoint); err != nil {
+					return err
+				}
+			}
+
+			reportFlags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+			if resumeAfter > 0 {
+				reportFlags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+			}
+			reportFile, err := os.OpenFile(*reportPath, reportFlags, 0644)
+			if err != nil {
+				return err
+			}
+			defer reportFile.Close()
+
+			result, err := im.run(ctx, reader, json.NewEncoder(reportFile), resumeAfter)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(stdout, "accepted: %d, rejected: %d, skipped: %d\n", result.Accepted, result.Rejected, result.Skipped)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+type MockAccountUseCase struct {
+	Transactions []account.CreateTransactionOptions
+	FailAt       int
+}
+
+func (mau *MockAccountUseCase) CreateAccount(ctx context.Context, opts account.CreateAccountOptions) (uint, error) {
+	return 1, nil
+}
+
+func (mau *MockAccountUseCase) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	return account.Account{}, nil
+}
+
+func (mau *MockAccountUseCase) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	if mau.FailAt > 0 && len(mau.Transactions)+1 == mau.FailAt {
+		return 0, account.NewInfraError(account.InfraUnknownError, "connection refused")
+	}
+
+	if opts.OperationTypeID == 9 {
+		return 0, account.NewDomainError(account.DomainOperationTypeDoesntExistErrorCode, "operation type doesn't exist")
+	}
+
+	for _, tx := range mau.Transactions {
+		if tx.IdempotencyKey == opts.IdempotencyKey {
+			return 0, account.NewInfraError(account.InfraTransactionDuplicatedErrorCode, "transaction already exists")
+		}
+	}
+	mau.Transactions = append(mau.Transactions, opts)
+
+	return uint(len(mau.Transactions)), nil
+}
+
+func (mau *MockAccountUseCase) CreateTransactions(ctx context.Context, opts account.CreateTransactionsOptions) ([]account.BatchItemResult, error) {
+	return nil, nil
+}
+
+func (mau *MockAccountUseCase) GetTransactionByID(ctx context.Context, transactionID uint) (account.Transaction, error) {
+	return account.Transaction{}, nil
+}
+
+func (mau *MockAccountUseCase) ListTransactions(ctx context.Context, opts account.ListTransactionsOptions) ([]account.Transaction, error) {
+	return nil, nil
+}
+
+func (mau *MockAccountUseCase) ListOperationTypes(ctx context.Context) ([]account.OperationType, error) {
+	return []account.OperationType{{ID: 1, Description: "COMPRA A VISTA"}, {ID: 4, Description: "PAGAMENTO"}}, nil
+}
+
+func (mau *MockAccountUseCase) GetBalance(ctx context.Context, accountID uint, at time.Time) (int64, error) {
+	return -1_234_56, nil
+}
+
+func TestImportTransactions(t *testing.T) {
+	suite := []struct {
+		Name            string
+		Data            string
+		ExpectedSummary string
+		ExpectedReport  []string
+	}{
+		{
+			Name: "transactions.csv",
+			Data: "account_id,operation_type_id,amount,event_date,merchant_mcc,metadata.legacy_id\n" +
+				"1,1,-12.5,2020-01-02T10:00:00Z,5462,A1\n" +
+				"1,9,-1,,,\n" +
+				"1,1,abc,,,\n" +
+				"1,4,100,,,,\n",
+			ExpectedSummary: "accepted: 1, rejected: 3, skipped: 0\n",
+			ExpectedReport: []string{
+				`{"line":2,"status":"accepted","id":1}`,
+				`{"line":3,"status":"rejected","error":{"code":"domain.2","message":"operation type doesn't exist"}}`,
+				`{"line":4,"status":"rejected","error":{"code":"handler.2","message":"amount must be a non zero decimal with up to 2 places","field":"amount"}}`,
+				`{"line":5,"status":"rejected","error":{"code":"handler.3","message":"wrong number of fields"}}`,
+			},
+		},
+		{
+			Name: "transactions.jsonl",
+			Data: `{"account_id": 1, "operation_type_id": 1, "amount": -0.29, "merchant": {"name": "Padaria Real"}, "metadata": {"legacy_id": "A1"}}` + "\n" +
+				`{"account_id": 1, "operation_type_id": 1, "amount": -0.29, "idempotency_key": "import:transactions.jsonl:1"}` + "\n" +
+				`{"account_id": 1, "operation_type_id": 1, "amount": 1, "branch": "x"}` + "\n",
+			ExpectedSummary: "accepted: 1, rejected: 1, skipped: 1\n",
+			ExpectedReport: []string{
+				`{"line":1,"status":"accepted","id":1}`,
+				`{"line":2,"status":"skipped","error":{"code":"infra.5","message":"transaction already exists"}}`,
+				`{"line":3,"status":"rejected","error":{"code":"handler.2","message":"unknown field","field":"branch"}}`,
+			},
+		},
+	}
+
+	for _, s := range suite {
+		dir := t.TempDir()
+		path := filepath.Join(dir, s.Name)
+		if err := os.WriteFile(path, []byte(s.Data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		mock := &MockAccountUseCase{}
+		stdout := &bytes.Buffer{}
+		if err := Import(mock, mock, stdout).Run(context.Background(), []string{path}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := stdout.String(), s.ExpectedSummary; got != expected {
+			t.Errorf("unexpected summary for %s, got: %v, expected: %v", s.Name, got, expected)
+			return
+		}
+
+		report, err := os.ReadFile(path + ".report.jsonl")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, expected := strings.TrimSpace(string(report)), strings.Join(s.ExpectedReport, "\n"); got != expected {
+			t.Errorf("unexpected report for %s, got:\n%v\nexpected:\n%v", s.Name, got, expected)
+			return
+		}
+	}
+}
+
+func TestImportTransactionsAmountInCents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.jsonl")
+	if err := os.WriteFile(path, []byte(`{"account_id": 1, "operation_type_id": 1, "amount": -0.29}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &MockAccountUseCase{}
+	if err := Import(mock, mock, &bytes.Buffer{}).Run(context.Background(), []string{path}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := mock.Transactions[0].Amount, int64(-29); got != expected {
+		t.Errorf("unexpected amount, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := mock.Transactions[0].IdempotencyKey, "import:transactions.jsonl:1"; got != expected {
+		t.Errorf("unexpected idempotency key, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestImportResumesFromCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.csv")
+	data := "account_id,operation_type_id,amount\n1,1,-1\n1,1,-2\n1,1,-3\n1,1,-4\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &MockAccountUseCase{FailAt: 3}
+	if err := Import(mock, mock, &bytes.Buffer{}).Run(context.Background(), []string{"-checkpoint-every", "1", path}); err == nil {
+		t.Error("expected import to stop on infra error")
+		return
+	}
+
+	checkpoint, err := os.ReadFile(path + ".checkpoint")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := string(checkpoint), "3\n"; got != expected {
+		t.Errorf("unexpected checkpoint, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	mock.FailAt = 0
+	stdout := &bytes.Buffer{}
+	if err := Import(mock, mock, stdout).Run(context.Background(), []string{path}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := stdout.String(), "accepted: 2, rejected: 0, skipped: 0\n"; got != expected {
+		t.Errorf("unexpected summary, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := len(mock.Transactions), 4; got != expected {
+		t.Errorf("unexpected number of transactions, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.csv")
+	if err := os.WriteFile(path, []byte("document_number,closing_day\n12345678900,10\n,10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := &bytes.Buffer{}
+	if err := Import(nil, &MockAccountUseCase{}, stdout).Run(context.Background(), []string{"-kind", "accounts", "-dry-run", path}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := stdout.String(), "accepted: 1, rejected: 1, skipped: 0\n"; got != expected {
+		t.Errorf("unexpected summary, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if _, err := os.Stat(path + ".checkpoint"); !os.IsNotExist(err) {
+		t.Errorf("unexpected checkpoint written on dry run, got: %v", err)
+		return
+	}
+}
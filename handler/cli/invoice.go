@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github/guiferpa/bank/domain/billing"
+)
+
+func CloseInvoices(usecase billing.UseCase, stdout io.Writer) Command {
+	return Command{
+		Name:        "close-invoices",
+		Description: "Close every billing cycle ended until the reference time and settle closed invoices",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("close-invoices", flag.ContinueOnError)
+			at := flags.String("at", "", "reference time formatted as RFC 3339, defaults to now")
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			opts := billing.CloseInvoicesOptions{At: time.Now().UTC()}
+			if *at != "" {
+				t, err := time.Parse(time.RFC3339, *at)
+				if err != nil {
+					return fmt.Errorf("invalid value for flag -at: %w", err)
+				}
+				opts.At = t
+			}
+
+			result, err := usecase.CloseInvoices(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(stdout, "closed: %d, paid: %d, overdue: %d\n", result.Closed, result.Paid, result.Overdue)
+
+			return nil
+		},
+	}
+}
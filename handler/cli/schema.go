@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// SchemaRepository is implemented by storages that own their schema and reference data.
+type SchemaRepository interface {
+	Migrate() error
+	RunSeed() error
+}
+
+func Migrate(schema SchemaRepository, stdout io.Writer) Command {
+	return Command{
+		Name:        "migrate",
+		Description: "Bring the database schema up to date",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if err := schema.Migrate(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(stdout, "migrations done")
+
+			return nil
+		},
+	}
+}
+
+func Seed(schema SchemaRepository, stdout io.Writer) Command {
+	return Command{
+		Name:        "seed",
+		Description: "Insert the reference data, such as operation types, missing in the database",
+		Run: func(ctx context.Context, args []string) error {
+			flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if err := schema.RunSeed(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(stdout, "seed done")
+
+			return nil
+		},
+	}
+}
@@ -1,8 +1,7 @@
 package api
 
 import (
-	"encoding/json"
-	"io"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,6 +17,9 @@ import (
 
 type CreateAccountRequestBody struct {
 	DocumentNumber string `json:"document_number" validate:"not_empty"`
+	ClosingDay     int    `json:"closing_day"`
+	DueDay         int    `json:"due_day"`
+	TimeZone       string `json:"time_zone"`
 }
 
 type CreateAccountResponseBody struct {
@@ -27,63 +29,41 @@ type CreateAccountResponseBody struct {
 
 func CreateAccount(usecase account.UseCase, logger log.LoggerRepository) http.HandlerFunc {
 	validator := gody.NewValidator()
+	rulesErr := validator.AddRules(rule.NotEmpty)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body CreateAccountRequestBody
 		if err := render.DecodeJSON(r.Body, &body); err != nil {
-			render.Status(r, http.StatusBadRequest)
-
-			if err == io.EOF {
-				render.Respond(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "missing request body"))
-				return
-			}
-
-			if _, ok := err.(*json.SyntaxError); ok {
-				render.Respond(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "invalid request body"))
-				return
-			}
-
-			if cerr, ok := err.(*json.UnmarshalTypeError); ok {
-				render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "wrong type", cerr.Field))
-				return
-			}
-
-			render.Respond(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, err.Error()))
+			respondDecodeError(w, r, err)
 			return
 		}
 		defer r.Body.Close()
 
-		if err := validator.AddRules(rule.NotEmpty); err != nil {
-			logger.Error(r.Context(), err.Error())
-			render.Status(r, http.StatusInternalServerError)
-			render.Respond(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, err.Error()))
+		if rulesErr != nil {
+			logger.Error(r.Context(), rulesErr.Error())
+			respondError(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, rulesErr.Error()))
 			return
 		}
 
 		if _, err := validator.Validate(body); err != nil {
-			render.Status(r, http.StatusUnprocessableEntity)
-
 			if cerr, ok := err.(*rule.ErrNotEmpty); ok {
-				render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field))
+				respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field))
 				return
 			}
 
-			render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "", err.Error()))
+			respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "", err.Error()))
 			return
 		}
 
 		options := account.CreateAccountOptions{
 			DocumentNumber: body.DocumentNumber,
+			ClosingDay:     body.ClosingDay,
+			DueDay:         body.DueDay,
+			TimeZone:       body.TimeZone,
 		}
-		accountID, err := usecase.CreateAccount(options)
+		accountID, err := usecase.CreateAccount(r.Context(), options)
 		if err != nil {
-			render.Status(r, http.StatusInternalServerError)
-
-			if cerr, ok := err.(*account.DomainError); ok && cerr.Code == account.DomainAccountAlreadyExistsErrorCode {
-				render.Status(r, http.StatusConflict)
-			}
-
-			render.Respond(w, r, err)
+			respondError(w, r, err)
 			return
 		}
 
@@ -105,21 +85,16 @@ type GetAccountByIDRequestParams struct {
 type GetAccountByIDResponseBody struct {
 	ID             uint   `json:"id"`
 	DocumentNumber string `json:"document_number"`
+	TimeZone       string `json:"time_zone"`
 }
 
 func GetAccountByID(usecase account.UseCase, logger log.LoggerRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := r.Context().Value(httpin.Input).(*GetAccountByIDRequestParams)
 
-		acc, err := usecase.GetAccountByID(params.AccountID)
+		acc, err := usecase.GetAccountByID(r.Context(), params.AccountID)
 		if err != nil {
-			render.Status(r, http.StatusInternalServerError)
-
-			if cerr, ok := err.(*account.InfraError); ok && cerr.Code == account.InfraAccountNotFoundErrorCode {
-				render.Status(r, http.StatusNotFound)
-			}
-
-			render.Respond(w, r, err)
+			respondError(w, r, err)
 			return
 		}
 
@@ -128,6 +103,7 @@ func GetAccountByID(usecase account.UseCase, logger log.LoggerRepository) http.H
 		render.Respond(w, r, GetAccountByIDResponseBody{
 			ID:             acc.ID,
 			DocumentNumber: acc.DocumentNumber,
+			TimeZone:       acc.TimeZone,
 		})
 
 		logger.Info(r.Context(), "account retrieved by id successful")
@@ -135,9 +111,12 @@ func GetAccountByID(usecase account.UseCase, logger log.LoggerRepository) http.H
 }
 
 type CreateAccountTransactionRequestBody struct {
-	AccountID       uint    `json:"account_id" validate:"min=0"`
-	OperationTypeID uint    `json:"operation_type_id" validate:"min=0"`
-	Amount          float64 `json:"amount" validate:"not_zero"`
+	AccountID       uint              `json:"account_id" validate:"min=0"`
+	OperationTypeID uint              `json:"operation_type_id" validate:"min=0"`
+	Amount          float64           `json:"amount" validate:"not_zero"`
+	EventDate       string            `json:"event_date"`
+	Merchant        *MerchantBody     `json:"merchant"`
+	Metadata        map[string]string `json:"metadata"`
 }
 
 type CreateAccountTransactionResponseBody struct {
@@ -171,79 +150,78 @@ func (r *NotZeroRule) Validate(field, value, _ string) (bool, error) {
 	return true, nil
 }
 
-func CreateAccountTransaction(usecase account.UseCase, logger log.LoggerRepository) http.HandlerFunc {
-	validator := gody.NewValidator()
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var body CreateAccountTransactionRequestBody
-		if err := render.DecodeJSON(r.Body, &body); err != nil {
-			render.Status(r, http.StatusBadRequest)
+// toCreateTransactionOptions checks a transaction body with the validator built for CreateAccountTransaction.
+func toCreateTransactionOptions(validator *gody.Validator, body CreateAccountTransactionRequestBody) (account.CreateTransactionOptions, *account.HandlerInvalidFieldError) {
+	if _, err := validator.Validate(body); err != nil {
+		if cerr, ok := err.(*rule.ErrMin); ok {
+			return account.CreateTransactionOptions{}, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field)
+		}
 
-			if err == io.EOF {
-				render.Respond(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "missing request body"))
-				return
-			}
+		if cerr, ok := err.(*NotZeroError); ok {
+			return account.CreateTransactionOptions{}, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field)
+		}
 
-			if _, ok := err.(*json.SyntaxError); ok {
-				render.Respond(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "invalid request body"))
-				return
-			}
+		return account.CreateTransactionOptions{}, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "", err.Error())
+	}
 
-			if cerr, ok := err.(*json.UnmarshalTypeError); ok {
-				render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "wrong type", cerr.Field))
-				return
-			}
+	options := account.CreateTransactionOptions{
+		AccountID:       body.AccountID,
+		OperationTypeID: body.OperationTypeID,
+		Amount:          int64(body.Amount * 100),
+		Merchant:        body.Merchant.toDomain(),
+		Metadata:        body.Metadata,
+	}
 
-			render.Respond(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, err.Error()))
-			return
+	if body.EventDate != "" {
+		eventDate, err := time.Parse(time.RFC3339, body.EventDate)
+		if err != nil {
+			return account.CreateTransactionOptions{}, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "field event_date must be formatted as RFC 3339", "event_date")
 		}
-		defer r.Body.Close()
+		options.EventDate = eventDate
+	}
 
-		if err := validator.AddRules(rule.Min, &NotZeroRule{}); err != nil {
-			logger.Error(r.Context(), err.Error())
-			render.Status(r, http.StatusInternalServerError)
-			render.Respond(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, err.Error()))
-			return
-		}
+	return options, nil
+}
 
-		if _, err := validator.Validate(body); err != nil {
-			render.Status(r, http.StatusUnprocessableEntity)
+func newTransactionValidator() (*gody.Validator, error) {
+	validator := gody.NewValidator()
+	if err := validator.AddRules(rule.Min, &NotZeroRule{}); err != nil {
+		return nil, err
+	}
 
-			if cerr, ok := err.(*rule.ErrMin); ok {
-				render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field))
-				return
-			}
+	return validator, nil
+}
 
-			if cerr, ok := err.(*NotZeroError); ok {
-				render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field))
-				return
-			}
+func CreateAccountTransaction(usecase account.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	validator, rulesErr := newTransactionValidator()
 
-			render.Respond(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "", err.Error()))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body CreateAccountTransactionRequestBody
+		if err := render.DecodeJSON(r.Body, &body); err != nil {
+			respondDecodeError(w, r, err)
 			return
 		}
+		defer r.Body.Close()
 
-		options := account.CreateTransactionOptions{
-			AccountID:       body.AccountID,
-			OperationTypeID: body.OperationTypeID,
-			Amount:          int64(body.Amount * 100),
-			EventDate:       time.Now(),
+		if rulesErr != nil {
+			logger.Error(r.Context(), rulesErr.Error())
+			respondError(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, rulesErr.Error()))
+			return
 		}
-		transID, err := usecase.CreateTransaction(options)
-		if err != nil {
-			render.Status(r, http.StatusInternalServerError)
 
-			if cerr, ok := err.(*account.DomainError); ok {
-				if cerr.Code == account.DomainOperationTypeDoesntExistErrorCode {
-					render.Status(r, http.StatusNotFound)
-				}
-			}
+		options, verr := toCreateTransactionOptions(validator, body)
+		if verr != nil {
+			respondError(w, r, verr)
+			return
+		}
 
-			if cerr, ok := err.(*account.InfraError); ok && cerr.Code == account.InfraAccountNotFoundErrorCode {
-				render.Status(r, http.StatusNotFound)
+		transID, err := usecase.CreateTransaction(r.Context(), options)
+		if err != nil {
+			if cerr, ok := err.(*account.DomainError); ok && cerr.Code == account.DomainTransactionDeclinedErrorCode {
+				logger.Warn(r.Context(), fmt.Sprintf("transaction declined for account %d by rule %s", options.AccountID, cerr.RuleID))
 			}
 
-			render.Respond(w, r, err)
+			respondError(w, r, err)
 			return
 		}
 
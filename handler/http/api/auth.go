@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/tenant"
+	"github/guiferpa/bank/pkg/signing"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// MaxBufferedBodyBytes bounds the body a middleware reads before the handler, such as to check a signature,
+// since the client may not be authenticated yet.
+const MaxBufferedBodyBytes = 1 << 20
+
+// bufferBody reads the body of r and puts it back for the handler, a body over MaxBufferedBodyBytes fails with
+// *http.MaxBytesError.
+func bufferBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBufferedBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// respondBodyError answers a body bufferBody couldn't read.
+func respondBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var merr *http.MaxBytesError
+	if errors.As(err, &merr) {
+		respondError(w, r, account.NewHandlerError(account.HandlerPayloadTooLargeErrorCode, fmt.Sprintf("request body can't be larger than %d bytes", merr.Limit)))
+		return
+	}
+
+	respondError(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "invalid request body"))
+}
+
+// signature reads the body of a signed request to put it in canonical form, the handlers get it back untouched.
+// A timestamp which isn't unix seconds is left zero, far out of any window.
+func signature(w http.ResponseWriter, r *http.Request) (*auth.Signature, error) {
+	timestamp, _ := strconv.ParseInt(r.Header.Get(signing.TimestampHeader), 10, 64)
+
+	body, err := bufferBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, nonce := r.Header.Get(signing.KeyIDHeader), r.Header.Get(signing.NonceHeader)
+	return &auth.Signature{
+		KeyID:     keyID,
+		Timestamp: time.Unix(timestamp, 0),
+		Nonce:     nonce,
+		Payload:   signing.Canonical(r.Method, r.URL.RequestURI(), timestamp, nonce, body, keyID),
+		MAC:       r.Header.Get(signing.SignatureHeader),
+	}, nil
+}
+
+func credentials(w http.ResponseWriter, r *http.Request) (auth.Credentials, error) {
+	creds := auth.Credentials{APIKey: r.Header.Get(APIKeyHeader)}
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		creds.Token = strings.TrimSpace(token)
+	}
+
+	if r.Header.Get(signing.SignatureHeader) != "" {
+		sig, err := signature(w, r)
+		if err != nil {
+			return auth.Credentials{}, err
+		}
+		creds.Signature = sig
+	}
+
+	return creds, nil
+}
+
+// AuthenticationMiddleware lets through only requests with a valid API key, in X-API-Key, bearer token or
+// signature, as pkg/signing makes them. Their principal goes in the context for the handlers and in the logger
+// context for the logs, a partner's tenant scopes the context to the accounts it created.
+func AuthenticationMiddleware(usecase auth.UseCase, logger log.LoggerRepository) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			creds, err := credentials(w, r)
+			if err != nil {
+				respondBodyError(w, r, err)
+				return
+			}
+
+			p, err := usecase.Authenticate(r.Context(), creds)
+			if err != nil {
+				var derr *account.DomainError
+				if errors.As(err, &derr) && derr.Code == account.DomainInvalidCredentialsErrorCode {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="bank"`)
+					respondError(w, r, account.NewHandlerError(account.HandlerUnauthorizedErrorCode, derr.Message))
+					return
+				}
+
+				logger.Error(r.Context(), err.Error())
+				respondError(w, r, err)
+				return
+			}
+
+			if cctx, ok := r.Context().Value(log.LoggerContextKey).(*log.LoggerContext); ok {
+				cctx.Principal = p.String()
+			}
+
+			ctx := auth.NewContext(r.Context(), p)
+			if p.Tenant != "" {
+				ctx = tenant.NewContext(ctx, p.Tenant)
+			}
+
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
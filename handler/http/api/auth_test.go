@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/pkg/signing"
+)
+
+type MockAuthUseCase struct {
+	auth.UseCase
+}
+
+func (mauc MockAuthUseCase) Authenticate(ctx context.Context, creds auth.Credentials) (auth.Principal, error) {
+	switch {
+	case creds.APIKey == "bank_a1b2_secret":
+		return auth.Principal{Kind: auth.APIKeyPrincipalKind, ID: "a1b2"}, nil
+	case creds.Token == "token":
+		return auth.Principal{Kind: auth.TokenPrincipalKind, ID: "partner"}, nil
+	case creds.APIKey == "bank_down_secret":
+		return auth.Principal{}, account.NewInfraError(account.InfraUnknownError, "connection refused")
+	default:
+		return auth.Principal{}, account.NewDomainError(account.DomainInvalidCredentialsErrorCode, "invalid credentials")
+	}
+}
+
+type MockLoggerRepository struct{}
+
+func (mlr MockLoggerRepository) Error(ctx context.Context, msg string) {}
+
+func (mlr MockLoggerRepository) Warn(ctx context.Context, msg string) {}
+
+func (mlr MockLoggerRepository) Info(ctx context.Context, msg string) {}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	suite := []struct {
+		Name      string
+		Headers   map[string]string
+		Status    int
+		Code      account.ErrorCode
+		Principal string
+	}{
+		{"Without credentials", nil, http.StatusUnauthorized, account.HandlerUnauthorizedErrorCode, ""},
+		{"Invalid API key", map[string]string{APIKeyHeader: "bank_a1b2_forged"}, http.StatusUnauthorized, account.HandlerUnauthorizedErrorCode, ""},
+		{"Valid API key", map[string]string{APIKeyHeader: "bank_a1b2_secret"}, http.StatusOK, "", "api_key:a1b2"},
+		{"Valid bearer token", map[string]string{"Authorization": "Bearer token"}, http.StatusOK, "", "token:partner"},
+		{"Basic credentials", map[string]string{"Authorization": "Basic dXNlcjpwd2Q="}, http.StatusUnauthorized, account.HandlerUnauthorizedErrorCode, ""},
+		{"Storage down", map[string]string{APIKeyHeader: "bank_down_secret"}, http.StatusInternalServerError, account.InfraUnknownError, ""},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			var principal string
+			handler := AuthenticationMiddleware(MockAuthUseCase{}, MockLoggerRepository{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if p, ok := auth.FromContext(r.Context()); ok {
+					principal = p.String()
+				}
+			}))
+
+			cctx := &log.LoggerContext{}
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/1", nil)
+			r = r.WithContext(context.WithValue(r.Context(), log.LoggerContextKey, cctx))
+			for name, value := range s.Headers {
+				r.Header.Set(name, value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got, expected := w.Code, s.Status; got != expected {
+				t.Errorf("unexpected status code, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if s.Code != "" {
+				var body account.HandlerError
+				if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := body.Code, s.Code; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+			}
+
+			if got, expected := principal, s.Principal; got != expected {
+				t.Errorf("unexpected principal in the context, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := cctx.Principal, s.Principal; got != expected {
+				t.Errorf("unexpected principal in the logger context, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+type MockSignatureAuthUseCase struct {
+	auth.UseCase
+}
+
+func (msauc MockSignatureAuthUseCase) Authenticate(ctx context.Context, creds auth.Credentials) (auth.Principal, error) {
+	if sig := creds.Signature; sig != nil && sig.KeyID == "a1b2" && signing.MAC("5ec2e7", sig.Payload) == sig.MAC {
+		return auth.Principal{Kind: auth.APIKeyPrincipalKind, ID: sig.KeyID}, nil
+	}
+
+	return auth.Principal{}, account.NewDomainError(account.DomainInvalidCredentialsErrorCode, "invalid signature")
+}
+
+func TestAuthenticationMiddlewareSignature(t *testing.T) {
+	suite := []struct {
+		Name   string
+		Secret string
+		Tamper func(r *http.Request)
+		Status int
+	}{
+		{"Signed request", "5ec2e7", func(r *http.Request) {}, http.StatusOK},
+		{"Body too large", "5ec2e7", func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(strings.Repeat("a", MaxBufferedBodyBytes+1)))
+		}, http.StatusRequestEntityTooLarge},
+		{"Signed by another secret", "forged", func(r *http.Request) {}, http.StatusUnauthorized},
+		{"Body changed after signing", "5ec2e7", func(r *http.Request) { r.Body = io.NopCloser(strings.NewReader(`{"amount":1000}`)) }, http.StatusUnauthorized},
+		{"Path changed after signing", "5ec2e7", func(r *http.Request) { r.URL.Path = "/api/v1/accounts/2" }, http.StatusUnauthorized},
+		{"Method changed after signing", "5ec2e7", func(r *http.Request) { r.Method = http.MethodPut }, http.StatusUnauthorized},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			var body string
+			handler := AuthenticationMiddleware(MockSignatureAuthUseCase{}, MockLoggerRepository{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/accounts/1?atomic=true", strings.NewReader(`{"amount":10}`))
+			if err := signing.Sign(r, "a1b2", s.Secret, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			s.Tamper(r)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got, expected := w.Code, s.Status; got != expected {
+				t.Errorf("unexpected status code, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if s.Status != http.StatusOK {
+				return
+			}
+
+			if got, expected := body, `{"amount":10}`; got != expected {
+				t.Errorf("unexpected body seen by the handler, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/customer"
+	"github/guiferpa/bank/domain/log"
+
+	"github.com/ggicci/httpin"
+	"github.com/go-chi/render"
+	"github.com/guiferpa/gody/v2"
+	"github.com/guiferpa/gody/v2/rule"
+)
+
+const BirthDateLayout = "2006-01-02"
+
+type CustomerAddressBody struct {
+	Street       string `json:"street,omitempty"`
+	Number       string `json:"number,omitempty"`
+	Complement   string `json:"complement,omitempty"`
+	Neighborhood string `json:"neighborhood,omitempty"`
+	City         string `json:"city,omitempty"`
+	State        string `json:"state,omitempty"`
+	ZipCode      string `json:"zip_code,omitempty"`
+	Country      string `json:"country,omitempty"`
+}
+
+type CreateCustomerRequestBody struct {
+	DocumentNumber string              `json:"document_number" validate:"not_empty"`
+	Name           string              `json:"name" validate:"not_empty"`
+	BirthDate      string              `json:"birth_date"`
+	Address        CustomerAddressBody `json:"address"`
+}
+
+type CreateCustomerResponseBody struct {
+	ID             uint                `json:"id"`
+	DocumentNumber string              `json:"document_number"`
+	Name           string              `json:"name"`
+	BirthDate      string              `json:"birth_date,omitempty"`
+	Address        CustomerAddressBody `json:"address"`
+}
+
+func CreateCustomer(usecase customer.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	validator := gody.NewValidator()
+	rulesErr := validator.AddRules(rule.NotEmpty)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body CreateCustomerRequestBody
+		if err := render.DecodeJSON(r.Body, &body); err != nil {
+			respondDecodeError(w, r, err)
+			return
+		}
+		defer r.Body.Close()
+
+		if rulesErr != nil {
+			logger.Error(r.Context(), rulesErr.Error())
+			respondError(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, rulesErr.Error()))
+			return
+		}
+
+		if _, err := validator.Validate(body); err != nil {
+			if cerr, ok := err.(*rule.ErrNotEmpty); ok {
+				respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, cerr.Error(), cerr.Field))
+				return
+			}
+
+			respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "", err.Error()))
+			return
+		}
+
+		var birthDate time.Time
+		if body.BirthDate != "" {
+			bd, err := time.Parse(BirthDateLayout, body.BirthDate)
+			if err != nil {
+				respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "field birth_date must be formatted as YYYY-MM-DD", "birth_date"))
+				return
+			}
+			birthDate = bd
+		}
+
+		options := customer.CreateCustomerOptions{
+			DocumentNumber: body.DocumentNumber,
+			Name:           body.Name,
+			BirthDate:      birthDate,
+			Address: customer.Address{
+				Street:       body.Address.Street,
+				Number:       body.Address.Number,
+				Complement:   body.Address.Complement,
+				Neighborhood: body.Address.Neighborhood,
+				City:         body.Address.City,
+				State:        body.Address.State,
+				ZipCode:      body.Address.ZipCode,
+				Country:      body.Address.Country,
+			},
+		}
+		customerID, err := usecase.CreateCustomer(r.Context(), options)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+
+		render.Respond(w, r, CreateCustomerResponseBody{
+			ID:             customerID,
+			DocumentNumber: options.DocumentNumber,
+			Name:           options.Name,
+			BirthDate:      body.BirthDate,
+			Address:        body.Address,
+		})
+
+		logger.Info(r.Context(), "customer created successful")
+	})
+}
+
+type CustomerRequestParams struct {
+	CustomerID uint `in:"path=id"`
+}
+
+type GetCustomerByIDResponseBody struct {
+	ID             uint                `json:"id"`
+	DocumentNumber string              `json:"document_number"`
+	Name           string              `json:"name"`
+	BirthDate      string              `json:"birth_date,omitempty"`
+	Address        CustomerAddressBody `json:"address"`
+}
+
+func GetCustomerByID(usecase customer.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*CustomerRequestParams)
+
+		cus, err := usecase.GetCustomerByID(r.Context(), params.CustomerID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		body := GetCustomerByIDResponseBody{
+			ID:             cus.ID,
+			DocumentNumber: cus.DocumentNumber,
+			Name:           cus.Name,
+			Address: CustomerAddressBody{
+				Street:       cus.Address.Street,
+				Number:       cus.Address.Number,
+				Complement:   cus.Address.Complement,
+				Neighborhood: cus.Address.Neighborhood,
+				City:         cus.Address.City,
+				State:        cus.Address.State,
+				ZipCode:      cus.Address.ZipCode,
+				Country:      cus.Address.Country,
+			},
+		}
+		if !cus.BirthDate.IsZero() {
+			body.BirthDate = cus.BirthDate.Format(BirthDateLayout)
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, body)
+
+		logger.Info(r.Context(), "customer retrieved by id successful")
+	}
+}
+
+type CustomerAccountResponseBody struct {
+	ID             uint   `json:"id"`
+	CustomerID     uint   `json:"customer_id"`
+	DocumentNumber string `json:"document_number"`
+}
+
+func CreateCustomerAccount(usecase customer.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*CustomerRequestParams)
+
+		acc, err := usecase.OpenAccount(r.Context(), customer.OpenAccountOptions{CustomerID: params.CustomerID})
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+
+		render.Respond(w, r, CustomerAccountResponseBody{
+			ID:             acc.ID,
+			CustomerID:     acc.CustomerID,
+			DocumentNumber: acc.DocumentNumber,
+		})
+
+		logger.Info(r.Context(), "customer account created successful")
+	}
+}
+
+func ListCustomerAccounts(usecase customer.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*CustomerRequestParams)
+
+		accs, err := usecase.ListAccounts(r.Context(), params.CustomerID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		body := make([]CustomerAccountResponseBody, 0, len(accs))
+		for _, acc := range accs {
+			body = append(body, CustomerAccountResponseBody{
+				ID:             acc.ID,
+				CustomerID:     acc.CustomerID,
+				DocumentNumber: acc.DocumentNumber,
+			})
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, body)
+
+		logger.Info(r.Context(), "customer accounts listed successful")
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github/guiferpa/bank/domain/health"
+
+	"github.com/go-chi/render"
+)
+
+const DefaultReadinessTimeout = 2 * time.Second
+
+type HealthCheckResponseBody struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type HealthResponseBody struct {
+	Status string                    `json:"status"`
+	Checks []HealthCheckResponseBody `json:"checks,omitempty"`
+}
+
+// Healthz only tells the process is up and serving, dependencies are left to Readyz.
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	render.Status(r, http.StatusOK)
+	render.Respond(w, r, HealthResponseBody{Status: string(health.UpStatus)})
+}
+
+func Readyz(usecase health.UseCase, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		report := usecase.Readiness(ctx)
+
+		body := HealthResponseBody{Status: string(report.Status), Checks: make([]HealthCheckResponseBody, 0, len(report.Checks))}
+		for _, check := range report.Checks {
+			body.Checks = append(body.Checks, HealthCheckResponseBody{
+				Name:      check.Name,
+				Status:    string(check.Status),
+				Error:     check.Error,
+				LatencyMS: float64(check.Latency.Microseconds()) / 1000,
+			})
+		}
+
+		render.Status(r, http.StatusOK)
+		if report.Status != health.UpStatus {
+			render.Status(r, http.StatusServiceUnavailable)
+		}
+
+		render.Respond(w, r, body)
+	}
+}
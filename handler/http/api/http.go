@@ -5,23 +5,48 @@ import (
 	"errors"
 	"fmt"
 	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/billing"
+	"github/guiferpa/bank/domain/consistency"
+	"github/guiferpa/bank/domain/customer"
+	"github/guiferpa/bank/domain/health"
 	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/metrics"
+	"github/guiferpa/bank/domain/ratelimit"
+	"github/guiferpa/bank/domain/schedule"
+	"github/guiferpa/bank/domain/statement"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/ggicci/httpin"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func init() {
 	httpin.UseGochiURLParam("path", chi.URLParam)
 }
 
-func HTTPResponseLoggerMiddleware(logger log.LoggerRepository) func(h http.Handler) http.Handler {
+// HTTPResponseLoggerMiddleware logs every response but the ones to skipPaths, such as probes hit every few seconds.
+func HTTPResponseLoggerMiddleware(logger log.LoggerRepository, skipPaths ...string) func(h http.Handler) http.Handler {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				h.ServeHTTP(w, r)
+				return
+			}
+
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
 			t := time.Now()
@@ -36,6 +61,8 @@ func HTTPResponseLoggerMiddleware(logger log.LoggerRepository) func(h http.Handl
 				ctxvalue, _ := r.Context().Value(log.LoggerContextKey).(*log.LoggerContext)
 				value := &log.LoggerContext{
 					RequestID: ctxvalue.RequestID,
+					Principal: ctxvalue.Principal,
+					Error:     ctxvalue.Error,
 					Payload: map[string]interface{}{
 						"response_time": elapsed,
 						"method":        r.Method,
@@ -47,7 +74,39 @@ func HTTPResponseLoggerMiddleware(logger log.LoggerRepository) func(h http.Handl
 						"host":          r.Host,
 					},
 				}
-				logger.Info(context.WithValue(r.Context(), log.LoggerContextKey, value), fmt.Sprintf("%s - %d - %s://%s/%s in %s", r.Method, ww.Status(), scheme, r.Host, r.RequestURI, elapsed))
+				msg := fmt.Sprintf("%s - %d - %s://%s/%s in %s", r.Method, ww.Status(), scheme, r.Host, r.RequestURI, elapsed)
+				if value.Error != "" {
+					logger.Error(context.WithValue(r.Context(), log.LoggerContextKey, value), msg)
+					return
+				}
+				logger.Info(context.WithValue(r.Context(), log.LoggerContextKey, value), msg)
+			}()
+
+			h.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// HTTPMetricsMiddleware observes every request by its route pattern, so /accounts/1 and /accounts/2 share a series.
+func HTTPMetricsMiddleware(m metrics.MetricsRepository) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			t := time.Now()
+
+			defer func() {
+				route := "not_found"
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				m.ObserveHTTPRequest(r.Method, route, status, time.Since(t))
 			}()
 
 			h.ServeHTTP(ww, r)
@@ -55,6 +114,30 @@ func HTTPResponseLoggerMiddleware(logger log.LoggerRepository) func(h http.Handl
 	}
 }
 
+// HTTPTracingMiddleware starts a server span for every request, continuing the trace of a W3C traceparent
+// header when there's one. The span is renamed by the route pattern once chi has routed the request.
+func HTTPTracingMiddleware(tp trace.TracerProvider) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		routed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(fmt.Sprintf("%s %s", r.Method, rctx.RoutePattern()))
+				span.SetAttributes(attribute.String("http.route", rctx.RoutePattern()))
+			}
+		})
+
+		return otelhttp.NewHandler(routed, "",
+			otelhttp.WithTracerProvider(tp),
+			otelhttp.WithPropagators(propagation.TraceContext{}),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method
+			}),
+		)
+	}
+}
+
 func SetRequestContextMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestId := r.Header.Get("X-Request-ID")
@@ -68,29 +151,142 @@ func SetRequestContextMiddleware(h http.Handler) http.Handler {
 	})
 }
 
-func NewHTTPHandler(usecase account.UseCase, logger log.LoggerRepository) http.Handler {
+// ReadYourWritesHeader set to true makes a request read from the primary database, for a client which has
+// just written and can't wait for the replicas to catch up.
+const ReadYourWritesHeader = "X-Read-Your-Writes"
+
+// ReadConsistencyMiddleware sends the reads of requests which write, or ask for it, to the primary database,
+// the other ones may be served by a replica.
+func ReadConsistencyMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primary, _ := strconv.ParseBool(r.Header.Get(ReadYourWritesHeader))
+		if primary || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			r = r.WithContext(consistency.WithPrimary(r.Context()))
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+type NewHTTPHandlerOptions struct {
+	AccountUseCase   account.UseCase
+	CustomerUseCase  customer.UseCase
+	BillingUseCase   billing.UseCase
+	ScheduleUseCase  schedule.UseCase
+	StatementUseCase statement.UseCase
+	HealthUseCase    health.UseCase
+	// AuthUseCase, when set, authenticates every request to the API, probes and metrics aren't, and authorizes
+	// it by the scope its route requires.
+	AuthUseCase auth.UseCase
+	// RateLimitUseCase, when set, limits the requests to the API matching RateLimitRules.
+	RateLimitUseCase ratelimit.UseCase
+	RateLimitRules   []RateLimitRule
+	BatchMaxItems    int
+	Metrics          metrics.MetricsRepository
+	TracerProvider   trace.TracerProvider
+	MetricsHandler   http.Handler
+	Logger           log.LoggerRepository
+}
+
+func NewHTTPHandler(opts NewHTTPHandlerOptions) http.Handler {
+	usecase, logger := opts.AccountUseCase, opts.Logger
+
 	router := chi.NewRouter()
 
-	router.Use(render.SetContentType(render.ContentTypeJSON), SetRequestContextMiddleware, HTTPResponseLoggerMiddleware(logger))
+	// Tracing goes first so the request ID and every log after it already see the span.
+	if opts.TracerProvider != nil {
+		router.Use(HTTPTracingMiddleware(opts.TracerProvider))
+	}
+
+	// render answers JSON whatever the Accept header says, the statement writes its negotiated format
+	// straight to the response. Errors are answered as problems by respondError.
+	router.Use(render.SetContentType(render.ContentTypeJSON), SetRequestContextMiddleware, ReadConsistencyMiddleware, HTTPResponseLoggerMiddleware(logger, "/healthz", "/readyz", "/metrics"))
+
+	if opts.Metrics != nil {
+		router.Use(HTTPMetricsMiddleware(opts.Metrics))
+	}
 
 	httpin.ReplaceDefaultErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
 		var invalidFieldError *httpin.InvalidFieldError
 
-		render.Status(r, http.StatusBadRequest)
-
 		if errors.As(err, &invalidFieldError) {
-			render.Respond(w, r, account.NewHandlerInvalidParamError(account.HandlerInvalidPathParam, "invalid path parameter", invalidFieldError.Field))
+			respondError(w, r, account.NewHandlerInvalidParamError(account.HandlerInvalidPathParam, "invalid path parameter", invalidFieldError.Field))
 			return
 		}
 
-		render.Respond(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, err.Error()))
+		respondError(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, err.Error()))
 	})
 
+	router.Get("/healthz", Healthz)
+	router.Get("/readyz", Readyz(opts.HealthUseCase, DefaultReadinessTimeout))
+
+	if opts.MetricsHandler != nil {
+		router.Method(http.MethodGet, "/metrics", opts.MetricsHandler)
+	}
+
+	// Without authentication there's no principal to authorize, every route is open.
+	read, writeAccounts, writeTransactions := noPolicy, noPolicy, noPolicy
+	if opts.AuthUseCase != nil {
+		read = AuthorizationMiddleware(auth.ReadAccountsScope, logger)
+		writeAccounts = AuthorizationMiddleware(auth.WriteAccountsScope, logger)
+		writeTransactions = AuthorizationMiddleware(auth.WriteTransactionsScope, logger)
+	}
+
 	router.Route("/api/v1", func(v1 chi.Router) {
+		if opts.AuthUseCase != nil {
+			v1.Use(AuthenticationMiddleware(opts.AuthUseCase, logger))
+		}
+
+		// After authentication, so a client's requests share a bucket whatever address they come from.
+		if opts.RateLimitUseCase != nil {
+			v1.Use(RateLimitMiddleware(opts.RateLimitUseCase, opts.RateLimitRules, logger))
+		}
+
 		v1.Route("/accounts", func(r chi.Router) {
-			r.Post("/", CreateAccount(usecase, logger))
-			r.With(httpin.NewInput(GetAccountByIDRequestParams{})).Get("/{id}", GetAccountByID(usecase, logger))
-			r.Post("/transaction", CreateAccountTransaction(usecase, logger))
+			r.With(writeAccounts).Post("/", CreateAccount(usecase, logger))
+			r.With(read, httpin.NewInput(GetAccountByIDRequestParams{})).Get("/{id}", GetAccountByID(usecase, logger))
+			r.With(read, httpin.NewInput(ListAccountInvoicesRequestParams{})).Get("/{id}/invoices", ListAccountInvoices(opts.BillingUseCase, logger))
+			r.With(read, httpin.NewInput(ListAccountTransactionsRequestParams{})).Get("/{id}/transactions", ListAccountTransactions(usecase, logger))
+			r.With(read, httpin.NewInput(ListAccountDailyTotalsRequestParams{})).Get("/{id}/daily-totals", ListAccountDailyTotals(opts.StatementUseCase, logger))
+			r.With(read, httpin.NewInput(GetAccountStatementRequestParams{})).Get("/{id}/statement", GetAccountStatement(opts.StatementUseCase, logger))
+			r.With(writeTransactions).Post("/transaction", CreateAccountTransaction(usecase, logger))
+
+			r.With(httpin.NewInput(AccountScheduledTransactionsRequestParams{})).Route("/{id}/scheduled-transactions", func(r chi.Router) {
+				r.With(writeTransactions).Post("/", CreateScheduledTransaction(opts.ScheduleUseCase, logger))
+				r.With(read).Get("/", ListAccountScheduledTransactions(opts.ScheduleUseCase, logger))
+			})
+		})
+
+		v1.Route("/customers", func(r chi.Router) {
+			r.With(writeAccounts).Post("/", CreateCustomer(opts.CustomerUseCase, logger))
+
+			r.With(httpin.NewInput(CustomerRequestParams{})).Route("/{id}", func(r chi.Router) {
+				r.With(read).Get("/", GetCustomerByID(opts.CustomerUseCase, logger))
+				r.With(writeAccounts).Post("/accounts", CreateCustomerAccount(opts.CustomerUseCase, logger))
+				r.With(read).Get("/accounts", ListCustomerAccounts(opts.CustomerUseCase, logger))
+			})
+		})
+
+		v1.Route("/invoices", func(r chi.Router) {
+			r.With(read, httpin.NewInput(GetInvoiceByIDRequestParams{})).Get("/{id}", GetInvoiceByID(opts.BillingUseCase, logger))
+		})
+
+		batchMaxItems := opts.BatchMaxItems
+		if batchMaxItems == 0 {
+			batchMaxItems = DefaultBatchMaxItems
+		}
+		v1.With(writeTransactions, httpin.NewInput(CreateTransactionsBatchRequestParams{})).Post("/transactions:batch", CreateTransactionsBatch(usecase, batchMaxItems, logger))
+
+		v1.Route("/transactions", func(r chi.Router) {
+			r.With(read, httpin.NewInput(GetTransactionByIDRequestParams{})).Get("/{id}", GetTransactionByID(usecase, logger))
+		})
+
+		v1.Route("/scheduled-transactions", func(r chi.Router) {
+			r.With(httpin.NewInput(ScheduledTransactionRequestParams{})).Route("/{id}", func(r chi.Router) {
+				r.With(read).Get("/", GetScheduledTransactionByID(opts.ScheduleUseCase, logger))
+				r.With(writeTransactions).Patch("/", UpdateScheduledTransaction(opts.ScheduleUseCase, logger))
+				r.With(writeTransactions).Delete("/", CancelScheduledTransaction(opts.ScheduleUseCase, logger))
+			})
 		})
 	})
 
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"github/guiferpa/bank/domain/consistency"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type MockMetricsRepository struct {
+	Requests map[string]int
+}
+
+func (mmr *MockMetricsRepository) ObserveHTTPRequest(method, route string, status int, elapsed time.Duration) {
+	mmr.Requests[method+" "+route+" "+http.StatusText(status)] += 1
+}
+
+func (mmr *MockMetricsRepository) IncAccountsCreated() {}
+
+func (mmr *MockMetricsRepository) IncTransactionsCreated(operationTypeID uint) {}
+
+func (mmr *MockMetricsRepository) IncTransactionsDeclined(reason string) {}
+
+func (mmr *MockMetricsRepository) ObserveQuery(operation, table string, elapsed time.Duration) {}
+
+func TestHTTPMetricsMiddleware(t *testing.T) {
+	metrics := &MockMetricsRepository{Requests: make(map[string]int)}
+
+	router := chi.NewRouter()
+	router.Use(HTTPMetricsMiddleware(metrics))
+	router.Get("/accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, target := range []string{"/accounts/1", "/accounts/2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	suite := []struct {
+		Key      string
+		Expected int
+	}{
+		{"GET /accounts/{id} No Content", 2},
+		{"GET not_found Not Found", 1},
+	}
+
+	for _, s := range suite {
+		if got, expected := metrics.Requests[s.Key], s.Expected; got != expected {
+			t.Errorf("unexpected requests for %q, got: %v, expected: %v", s.Key, got, expected)
+			return
+		}
+	}
+}
+
+func TestHTTPTracingMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var traceID string
+	router := chi.NewRouter()
+	router.Use(HTTPTracingMiddleware(tp))
+	router.Get("/accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.SpanContextFromContext(r.Context()).TraceID().String()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if got, expected := len(spans), 1; got != expected {
+		t.Errorf("unexpected number of spans, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := spans[0].Name, "GET /accounts/{id}"; got != expected {
+		t.Errorf("unexpected span name, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := spans[0].SpanContext.TraceID().String(), "4bf92f3577b34da6a3ce929d0e0e4736"; got != expected {
+		t.Errorf("unexpected trace id, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := traceID, "4bf92f3577b34da6a3ce929d0e0e4736"; got != expected {
+		t.Errorf("unexpected trace id in handler context, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestReadConsistencyMiddleware(t *testing.T) {
+	suite := []struct {
+		Method   string
+		Header   string
+		Expected bool
+	}{
+		{http.MethodGet, "", false},
+		{http.MethodGet, "true", true},
+		{http.MethodGet, "false", false},
+		{http.MethodHead, "", false},
+		{http.MethodPost, "", true},
+		{http.MethodPut, "", true},
+		{http.MethodDelete, "", true},
+	}
+
+	for _, s := range suite {
+		var got bool
+		handler := ReadConsistencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = consistency.Primary(r.Context())
+		}))
+
+		r := httptest.NewRequest(s.Method, "/api/v1/accounts/1", nil)
+		if s.Header != "" {
+			r.Header.Set(ReadYourWritesHeader, s.Header)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		if expected := s.Expected; got != expected {
+			t.Errorf("unexpected primary read for %s with %q, got: %v, expected: %v", s.Method, s.Header, got, expected)
+			return
+		}
+	}
+}
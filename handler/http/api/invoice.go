@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+
+	"github/guiferpa/bank/domain/billing"
+	"github/guiferpa/bank/domain/log"
+
+	"github.com/ggicci/httpin"
+	"github.com/go-chi/render"
+)
+
+const InvoiceDateLayout = "2006-01-02"
+
+type InvoiceTotalResponseBody struct {
+	OperationTypeID uint    `json:"operation_type_id"`
+	Amount          float64 `json:"amount"`
+}
+
+type InvoiceResponseBody struct {
+	ID          uint                       `json:"id"`
+	AccountID   uint                       `json:"account_id"`
+	PeriodStart string                     `json:"period_start"`
+	PeriodEnd   string                     `json:"period_end"`
+	DueDate     string                     `json:"due_date"`
+	Status      string                     `json:"status"`
+	Total       float64                    `json:"total"`
+	PaidAmount  float64                    `json:"paid_amount"`
+	Totals      []InvoiceTotalResponseBody `json:"totals"`
+}
+
+func NewInvoiceResponseBody(inv billing.Invoice) InvoiceResponseBody {
+	totals := make([]InvoiceTotalResponseBody, 0, len(inv.Totals))
+	for _, t := range inv.Totals {
+		totals = append(totals, InvoiceTotalResponseBody{
+			OperationTypeID: t.OperationTypeID,
+			Amount:          float64(t.Amount) / 100,
+		})
+	}
+
+	return InvoiceResponseBody{
+		ID:          inv.ID,
+		AccountID:   inv.AccountID,
+		PeriodStart: inv.PeriodStart.Format(InvoiceDateLayout),
+		PeriodEnd:   inv.PeriodEnd.Format(InvoiceDateLayout),
+		DueDate:     inv.DueDate.Format(InvoiceDateLayout),
+		Status:      string(inv.Status),
+		Total:       float64(inv.Total) / 100,
+		PaidAmount:  float64(inv.PaidAmount) / 100,
+		Totals:      totals,
+	}
+}
+
+type ListAccountInvoicesRequestParams struct {
+	AccountID uint `in:"path=id"`
+}
+
+func ListAccountInvoices(usecase billing.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*ListAccountInvoicesRequestParams)
+
+		invs, err := usecase.ListInvoicesByAccountID(r.Context(), params.AccountID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		body := make([]InvoiceResponseBody, 0, len(invs))
+		for _, inv := range invs {
+			body = append(body, NewInvoiceResponseBody(inv))
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, body)
+
+		logger.Info(r.Context(), "account invoices listed successful")
+	}
+}
+
+type GetInvoiceByIDRequestParams struct {
+	InvoiceID uint `in:"path=id"`
+}
+
+func GetInvoiceByID(usecase billing.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*GetInvoiceByIDRequestParams)
+
+		inv, err := usecase.GetInvoiceByID(r.Context(), params.InvoiceID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, NewInvoiceResponseBody(inv))
+
+		logger.Info(r.Context(), "invoice retrieved by id successful")
+	}
+}
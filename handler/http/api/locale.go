@@ -0,0 +1,150 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+// Locale holds what changes between languages in printable statements.
+type Locale struct {
+	Tag            string
+	DateLayout     string
+	DateTimeLayout string
+	DecimalSep     string
+	ThousandsSep   string
+	CurrencyFormat string
+	OperationTypes map[uint]string
+	Labels         map[string]string
+}
+
+const DefaultLocaleTag = "en-US"
+
+var Locales = map[string]*Locale{
+	"en-US": {
+		Tag:            "en-US",
+		DateLayout:     "01/02/2006",
+		DateTimeLayout: "01/02/2006 3:04 PM",
+		DecimalSep:     ".",
+		ThousandsSep:   ",",
+		CurrencyFormat: "R$%s",
+		OperationTypes: map[uint]string{
+			account.CashPurchaseOperationTypeID:        "Cash purchase",
+			account.InstallmentPurchaseOperationTypeID: "Installment purchase",
+			account.WithdrawalOperationTypeID:          "Withdrawal",
+			account.PaymentOperationTypeID:             "Payment",
+			account.InterestOperationTypeID:            "Revolving interest",
+			account.LateFeeOperationTypeID:             "Late fee",
+			account.IOFOperationTypeID:                 "IOF",
+		},
+		Labels: map[string]string{
+			"title":           "Account statement",
+			"account":         "Account",
+			"document_number": "Document number",
+			"period":          "Period",
+			"to":              "to",
+			"opening_balance": "Opening balance",
+			"closing_balance": "Closing balance",
+			"date":            "Date",
+			"operation_type":  "Type",
+			"description":     "Description",
+			"amount":          "Amount",
+			"balance":         "Balance",
+			"transactions":    "Transactions",
+			"generated_at":    "Generated at",
+		},
+	},
+	"pt-BR": {
+		Tag:            "pt-BR",
+		DateLayout:     "02/01/2006",
+		DateTimeLayout: "02/01/2006 15:04",
+		DecimalSep:     ",",
+		ThousandsSep:   ".",
+		CurrencyFormat: "R$ %s",
+		OperationTypes: map[uint]string{
+			account.CashPurchaseOperationTypeID:        "Compra à vista",
+			account.InstallmentPurchaseOperationTypeID: "Compra parcelada",
+			account.WithdrawalOperationTypeID:          "Saque",
+			account.PaymentOperationTypeID:             "Pagamento",
+			account.InterestOperationTypeID:            "Juros rotativo",
+			account.LateFeeOperationTypeID:             "Multa por atraso",
+			account.IOFOperationTypeID:                 "IOF",
+		},
+		Labels: map[string]string{
+			"title":           "Extrato da conta",
+			"account":         "Conta",
+			"document_number": "Documento",
+			"period":          "Período",
+			"to":              "a",
+			"opening_balance": "Saldo inicial",
+			"closing_balance": "Saldo final",
+			"date":            "Data",
+			"operation_type":  "Tipo",
+			"description":     "Descrição",
+			"amount":          "Valor",
+			"balance":         "Saldo",
+			"transactions":    "Lançamentos",
+			"generated_at":    "Gerado em",
+		},
+	},
+}
+
+// negotiateLocale picks the locale the Accept-Language header prefers, DefaultLocaleTag when none matches.
+func negotiateLocale(acceptLanguage string) *Locale {
+	best, bestQ := Locales[DefaultLocaleTag], 0.0
+	for _, f := range acceptFields(acceptLanguage) {
+		if f.q <= bestQ {
+			continue
+		}
+
+		for tag, locale := range Locales {
+			lang := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+			if f.value == strings.ToLower(tag) || f.value == lang || strings.HasPrefix(f.value, lang+"-") {
+				best, bestQ = locale, f.q
+			}
+		}
+	}
+
+	return best
+}
+
+func (l *Locale) Label(key string) string {
+	return l.Labels[key]
+}
+
+func (l *Locale) OperationType(id uint) string {
+	if label, ok := l.OperationTypes[id]; ok {
+		return label
+	}
+
+	return fmt.Sprintf("#%d", id)
+}
+
+func (l *Locale) Date(t time.Time) string {
+	return t.Format(l.DateLayout)
+}
+
+func (l *Locale) DateTime(t time.Time) string {
+	return t.Format(l.DateTimeLayout)
+}
+
+// Money formats an amount in cents with the locale's separators, e.g. -R$ 1.234,56 in pt-BR.
+func (l *Locale) Money(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	units := fmt.Sprintf("%d", amount/100)
+	var sb strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			sb.WriteString(l.ThousandsSep)
+		}
+		sb.WriteRune(digit)
+	}
+
+	return sign + fmt.Sprintf(l.CurrencyFormat, fmt.Sprintf("%s%s%02d", sb.String(), l.DecimalSep, amount%100))
+}
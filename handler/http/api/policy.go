@@ -0,0 +1,31 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/log"
+)
+
+func noPolicy(h http.Handler) http.Handler {
+	return h
+}
+
+// AuthorizationMiddleware lets through only the principals granted scope, the route's policy. Which accounts a
+// partner tenant reaches is up to the domain, the route only knows what kind of operation it is.
+func AuthorizationMiddleware(scope auth.Scope, logger log.LoggerRepository) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := auth.FromContext(r.Context())
+			if !ok || !p.HasScope(scope) {
+				logger.Warn(r.Context(), fmt.Sprintf("%s %s denied for lacking scope %s", r.Method, r.URL.Path, scope))
+				respondError(w, r, account.NewHandlerError(account.HandlerForbiddenErrorCode, fmt.Sprintf("scope %s is required", scope)))
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
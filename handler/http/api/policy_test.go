@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/customer"
+)
+
+type MockPolicyAuthUseCase struct {
+	auth.UseCase
+}
+
+// Authenticate takes the API key as the name of the principal it belongs to.
+func (mpauc MockPolicyAuthUseCase) Authenticate(ctx context.Context, creds auth.Credentials) (auth.Principal, error) {
+	principals := map[string]auth.Principal{
+		"reader": {ID: "reader", Scopes: []auth.Scope{auth.ReadAccountsScope}},
+		"opener": {ID: "opener", Scopes: []auth.Scope{auth.WriteAccountsScope}},
+		"payer":  {ID: "payer", Scopes: []auth.Scope{auth.WriteTransactionsScope}},
+		"nobody": {ID: "nobody"},
+		"acme":   {ID: "acme", Scopes: auth.Scopes, Tenant: "acme"},
+	}
+
+	p, ok := principals[creds.APIKey]
+	if !ok {
+		return auth.Principal{}, account.NewDomainError(account.DomainInvalidCredentialsErrorCode, "invalid api key")
+	}
+	p.Kind = auth.APIKeyPrincipalKind
+
+	return p, nil
+}
+
+// MockPolicyAccountUseCase has account 1 created by the acme tenant and account 2 by the bank.
+type MockPolicyAccountUseCase struct {
+	account.UseCase
+}
+
+func (mpauc MockPolicyAccountUseCase) CreateAccount(ctx context.Context, opts account.CreateAccountOptions) (uint, error) {
+	return 3, nil
+}
+
+func (mpauc MockPolicyAccountUseCase) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
+	acc := account.Account{ID: accountID, DocumentNumber: "12345678900"}
+	if accountID == 1 {
+		acc.Tenant = "acme"
+	}
+
+	if err := account.Authorize(ctx, acc); err != nil {
+		return account.Account{}, err
+	}
+
+	return acc, nil
+}
+
+func (mpauc MockPolicyAccountUseCase) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	if _, err := mpauc.GetAccountByID(ctx, opts.AccountID); err != nil {
+		return 0, err
+	}
+
+	return 1, nil
+}
+
+// MockPolicyCustomerStorageRepository has customer 1 created by the acme tenant and customer 2 by the bank.
+type MockPolicyCustomerStorageRepository struct {
+	customer.StorageRepository
+}
+
+func (mpcsr MockPolicyCustomerStorageRepository) GetCustomerByID(ctx context.Context, customerID uint) (customer.Customer, error) {
+	cus := customer.Customer{ID: customerID, DocumentNumber: "12345678900", Name: "Jane Doe"}
+	if customerID == 1 {
+		cus.Tenant = "acme"
+	}
+
+	return cus, nil
+}
+
+func TestPolicyMatrix(t *testing.T) {
+	handler := NewHTTPHandler(NewHTTPHandlerOptions{
+		AccountUseCase:  MockPolicyAccountUseCase{},
+		CustomerUseCase: customer.NewUseCaseService(MockPolicyCustomerStorageRepository{}, MockPolicyAccountUseCase{}, nil),
+		AuthUseCase:     MockPolicyAuthUseCase{},
+		Logger:          MockLoggerRepository{},
+	})
+
+	suite := []struct {
+		Principal string
+		Method    string
+		Path      string
+		Body      string
+		Status    int
+		Code      account.ErrorCode
+	}{
+		{"reader", http.MethodGet, "/api/v1/accounts/2", "", http.StatusOK, ""},
+		{"opener", http.MethodGet, "/api/v1/accounts/2", "", http.StatusForbidden, account.HandlerForbiddenErrorCode},
+		{"payer", http.MethodGet, "/api/v1/accounts/2", "", http.StatusForbidden, account.HandlerForbiddenErrorCode},
+		{"nobody", http.MethodGet, "/api/v1/accounts/2", "", http.StatusForbidden, account.HandlerForbiddenErrorCode},
+
+		{"reader", http.MethodPost, "/api/v1/accounts", `{"document_number":"12345678900"}`, http.StatusForbidden, account.HandlerForbiddenErrorCode},
+		{"opener", http.MethodPost, "/api/v1/accounts", `{"document_number":"12345678900"}`, http.StatusCreated, ""},
+		{"payer", http.MethodPost, "/api/v1/accounts", `{"document_number":"12345678900"}`, http.StatusForbidden, account.HandlerForbiddenErrorCode},
+		{"nobody", http.MethodPost, "/api/v1/accounts", `{"document_number":"12345678900"}`, http.StatusForbidden, account.HandlerForbiddenErrorCode},
+
+		{"reader", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":2,"operation_type_id":4,"amount":10}`, http.StatusForbidden, account.HandlerForbiddenErrorCode},
+		{"opener", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":2,"operation_type_id":4,"amount":10}`, http.StatusForbidden, account.HandlerForbiddenErrorCode},
+		{"payer", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":2,"operation_type_id":4,"amount":10}`, http.StatusCreated, ""},
+		{"nobody", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":2,"operation_type_id":4,"amount":10}`, http.StatusForbidden, account.HandlerForbiddenErrorCode},
+
+		{"reader", http.MethodGet, "/api/v1/accounts/1", "", http.StatusOK, ""},
+		{"acme", http.MethodGet, "/api/v1/accounts/1", "", http.StatusOK, ""},
+		{"acme", http.MethodGet, "/api/v1/accounts/2", "", http.StatusForbidden, account.DomainAccountForbiddenErrorCode},
+		{"acme", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":1,"operation_type_id":4,"amount":10}`, http.StatusCreated, ""},
+		{"acme", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":2,"operation_type_id":4,"amount":10}`, http.StatusForbidden, account.DomainAccountForbiddenErrorCode},
+
+		{"reader", http.MethodGet, "/api/v1/customers/1", "", http.StatusOK, ""},
+		{"acme", http.MethodGet, "/api/v1/customers/1", "", http.StatusOK, ""},
+		{"acme", http.MethodGet, "/api/v1/customers/2", "", http.StatusForbidden, account.DomainCustomerForbiddenErrorCode},
+		{"opener", http.MethodPost, "/api/v1/customers/2/accounts", "", http.StatusCreated, ""},
+		{"acme", http.MethodPost, "/api/v1/customers/1/accounts", "", http.StatusCreated, ""},
+		{"acme", http.MethodPost, "/api/v1/customers/2/accounts", "", http.StatusForbidden, account.DomainCustomerForbiddenErrorCode},
+	}
+
+	for _, s := range suite {
+		t.Run(strings.Join([]string{s.Principal, s.Method, s.Path}, " "), func(t *testing.T) {
+			r := httptest.NewRequest(s.Method, s.Path, strings.NewReader(s.Body))
+			r.Header.Set(APIKeyHeader, s.Principal)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got, expected := w.Code, s.Status; got != expected {
+				t.Errorf("unexpected status code, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if s.Code == "" {
+				return
+			}
+
+			var body account.HandlerError
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got, expected := body.Code, s.Code; got != expected {
+				t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+)
+
+// ProblemContentType is how every error is answered, as RFC 7807 describes.
+const ProblemContentType = "application/problem+json"
+
+// ErrorTypeBaseURI documents the error codes, the type of a problem is it followed by the code.
+const ErrorTypeBaseURI = "https://github.com/guiferpa/bank/blob/main/docs/errors.md#"
+
+type ErrorDefinition struct {
+	Status int
+	Title  string
+	// Internal hides the message of the error from the client, it's logged with the response instead.
+	Internal bool
+}
+
+// Errors is the registry of every error code the API answers, a code out of it is answered as handler.1.
+var Errors = map[account.ErrorCode]ErrorDefinition{
+	account.HandlerUnknwonErrorCode:         {http.StatusInternalServerError, "Internal server error", true},
+	account.HandlerInvalidPayloadErrorCode:  {http.StatusUnprocessableEntity, "Invalid request payload", false},
+	account.HandlerBadRequestErrorCode:      {http.StatusBadRequest, "Bad request", false},
+	account.HandlerInvalidPathParam:         {http.StatusBadRequest, "Invalid path parameter", false},
+	account.HandlerNotAcceptableErrorCode:   {http.StatusNotAcceptable, "Not acceptable", false},
+	account.HandlerUnauthorizedErrorCode:    {http.StatusUnauthorized, "Unauthorized", false},
+	account.HandlerForbiddenErrorCode:       {http.StatusForbidden, "Forbidden", false},
+	account.HandlerTooManyRequestsErrorCode: {http.StatusTooManyRequests, "Too many requests", false},
+	account.HandlerPayloadTooLargeErrorCode: {http.StatusRequestEntityTooLarge, "Payload too large", false},
+
+	account.DomainAccountAlreadyExistsErrorCode:     {http.StatusConflict, "Account already exists", false},
+	account.DomainOperationTypeDoesntExistErrorCode: {http.StatusNotFound, "Operation type doesn't exist", false},
+	account.DomainCustomerAlreadyExistsErrorCode:    {http.StatusConflict, "Customer already exists", false},
+	account.DomainInvalidBillingDayErrorCode:        {http.StatusUnprocessableEntity, "Invalid billing day", false},
+	account.DomainTransactionDeclinedErrorCode:      {http.StatusUnprocessableEntity, "Transaction declined", false},
+	account.DomainInvalidScheduleErrorCode:          {http.StatusUnprocessableEntity, "Invalid schedule", false},
+	account.DomainScheduleNotActiveErrorCode:        {http.StatusConflict, "Scheduled transaction isn't active", false},
+	account.DomainInvalidEventDateErrorCode:         {http.StatusUnprocessableEntity, "Invalid event date", false},
+	account.DomainInvalidTimeZoneErrorCode:          {http.StatusUnprocessableEntity, "Invalid time zone", false},
+	account.DomainInvalidDateRangeErrorCode:         {http.StatusBadRequest, "Invalid date range", false},
+	account.DomainInvalidMerchantErrorCode:          {http.StatusUnprocessableEntity, "Invalid merchant", false},
+	account.DomainInvalidMetadataErrorCode:          {http.StatusUnprocessableEntity, "Invalid metadata", false},
+	account.DomainInvalidCredentialsErrorCode:       {http.StatusUnauthorized, "Invalid credentials", false},
+	account.DomainInvalidAPIKeyErrorCode:            {http.StatusUnprocessableEntity, "Invalid API key", false},
+	account.DomainAccountForbiddenErrorCode:         {http.StatusForbidden, "Account belongs to another tenant", false},
+	account.DomainCustomerForbiddenErrorCode:        {http.StatusForbidden, "Customer belongs to another tenant", false},
+
+	account.InfraUnknownError:                   {http.StatusInternalServerError, "Internal server error", true},
+	account.InfraAccountNotFoundErrorCode:       {http.StatusNotFound, "Account not found", false},
+	account.InfraCustomerNotFoundErrorCode:      {http.StatusNotFound, "Customer not found", false},
+	account.InfraInvoiceNotFoundErrorCode:       {http.StatusNotFound, "Invoice not found", false},
+	account.InfraTransactionDuplicatedErrorCode: {http.StatusConflict, "Transaction already exists", false},
+	account.InfraScheduleNotFoundErrorCode:      {http.StatusNotFound, "Scheduled transaction not found", false},
+	account.InfraScheduleOutdatedErrorCode:      {http.StatusConflict, "Scheduled transaction outdated", false},
+	account.InfraTransactionNotFoundErrorCode:   {http.StatusNotFound, "Transaction not found", false},
+	account.InfraAPIKeyNotFoundErrorCode:        {http.StatusNotFound, "API key not found", false},
+}
+
+// Violation is a field of the body, or a parameter, which isn't valid.
+type Violation struct {
+	Field     string `json:"field,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Detail    string `json:"detail"`
+}
+
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     account.ErrorCode `json:"code"`
+	RuleID   string            `json:"rule_id,omitempty"`
+	Errors   []Violation       `json:"errors,omitempty"`
+	// internal is the message hidden from the client.
+	internal string
+}
+
+// NewProblem describes err by the registry, an error not coming from the account errors is taken as handler.1.
+func NewProblem(err error) Problem {
+	var (
+		code   = account.HandlerUnknwonErrorCode
+		detail = err.Error()
+		p      Problem
+	)
+
+	var (
+		herr *account.HandlerError
+		ferr *account.HandlerInvalidFieldError
+		perr *account.HandlerInvalidParamError
+		derr *account.DomainError
+		ierr *account.InfraError
+	)
+	switch {
+	case errors.As(err, &herr):
+		code = herr.Code
+	case errors.As(err, &ferr):
+		code = ferr.Code
+		if ferr.Field != "" {
+			p.Errors = []Violation{{Field: ferr.Field, Detail: ferr.Message}}
+		}
+	case errors.As(err, &perr):
+		code = perr.Code
+		if perr.Param != "" {
+			p.Errors = []Violation{{Parameter: perr.Param, Detail: perr.Message}}
+		}
+	case errors.As(err, &derr):
+		code, p.RuleID = derr.Code, derr.RuleID
+	case errors.As(err, &ierr):
+		code = ierr.Code
+	}
+
+	def, ok := Errors[code]
+	if !ok {
+		code, def = account.HandlerUnknwonErrorCode, Errors[account.HandlerUnknwonErrorCode]
+	}
+
+	p.Type, p.Title, p.Status, p.Code = ErrorTypeBaseURI+string(code), def.Title, def.Status, code
+	if def.Internal {
+		p.internal, p.Errors = detail, nil
+	} else {
+		p.Detail = detail
+	}
+
+	return p
+}
+
+// problem describes err for the response to r. A hidden message goes to the logger context, for the response
+// log to tell what happened.
+func problem(r *http.Request, err error) *Problem {
+	p := NewProblem(err)
+	if cctx, ok := r.Context().Value(log.LoggerContextKey).(*log.LoggerContext); ok && p.internal != "" {
+		cctx.Error = p.internal
+	}
+
+	return &p
+}
+
+// respondError answers err as a problem, its status coming from the registry.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	p := problem(r, err)
+	p.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+func respondDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == io.EOF {
+		respondError(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "missing request body"))
+		return
+	}
+
+	if _, ok := err.(*json.SyntaxError); ok {
+		respondError(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "invalid request body"))
+		return
+	}
+
+	if cerr, ok := err.(*json.UnmarshalTypeError); ok {
+		respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "wrong type", cerr.Field))
+		return
+	}
+
+	respondError(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, err.Error()))
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+)
+
+func TestRespondError(t *testing.T) {
+	suite := []struct {
+		Name     string
+		Err      error
+		Expected Problem
+		Logged   string
+	}{
+		{
+			"Domain error",
+			account.NewDomainError(account.DomainAccountAlreadyExistsErrorCode, "account already exists"),
+			Problem{ErrorTypeBaseURI + "domain.1", "Account already exists", http.StatusConflict, "account already exists", "/api/v1/accounts", account.DomainAccountAlreadyExistsErrorCode, "", nil, ""},
+			"",
+		},
+		{
+			"Declined transaction",
+			&account.DomainError{Code: account.DomainTransactionDeclinedErrorCode, Message: "transaction declined", RuleID: "velocity"},
+			Problem{ErrorTypeBaseURI + "domain.5", "Transaction declined", http.StatusUnprocessableEntity, "transaction declined", "/api/v1/accounts", account.DomainTransactionDeclinedErrorCode, "velocity", nil, ""},
+			"",
+		},
+		{
+			"Invalid field",
+			account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "field document_number cannot be empty", "document_number"),
+			Problem{ErrorTypeBaseURI + "handler.2", "Invalid request payload", http.StatusUnprocessableEntity, "field document_number cannot be empty", "/api/v1/accounts", account.HandlerInvalidPayloadErrorCode, "", []Violation{{Field: "document_number", Detail: "field document_number cannot be empty"}}, ""},
+			"",
+		},
+		{
+			"Invalid parameter",
+			account.NewHandlerInvalidParamError(account.HandlerBadRequestErrorCode, "parameter from must be formatted as YYYY-MM-DD", "from"),
+			Problem{ErrorTypeBaseURI + "handler.3", "Bad request", http.StatusBadRequest, "parameter from must be formatted as YYYY-MM-DD", "/api/v1/accounts", account.HandlerBadRequestErrorCode, "", []Violation{{Parameter: "from", Detail: "parameter from must be formatted as YYYY-MM-DD"}}, ""},
+			"",
+		},
+		{
+			"Unknown infra error",
+			account.NewInfraError(account.InfraUnknownError, "ERROR: relation \"accounts\" does not exist (SQLSTATE 42P01)"),
+			Problem{ErrorTypeBaseURI + "infra.1", "Internal server error", http.StatusInternalServerError, "", "/api/v1/accounts", account.InfraUnknownError, "", nil, ""},
+			"ERROR: relation \"accounts\" does not exist (SQLSTATE 42P01)",
+		},
+		{
+			"Error out of the account errors",
+			errors.New("dial tcp: connection refused"),
+			Problem{ErrorTypeBaseURI + "handler.1", "Internal server error", http.StatusInternalServerError, "", "/api/v1/accounts", account.HandlerUnknwonErrorCode, "", nil, ""},
+			"dial tcp: connection refused",
+		},
+		{
+			"Code out of the registry",
+			account.NewDomainError("domain.0", "made up"),
+			Problem{ErrorTypeBaseURI + "handler.1", "Internal server error", http.StatusInternalServerError, "", "/api/v1/accounts", account.HandlerUnknwonErrorCode, "", nil, ""},
+			"made up",
+		},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			cctx := &log.LoggerContext{}
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/accounts", nil)
+			r = r.WithContext(context.WithValue(r.Context(), log.LoggerContextKey, cctx))
+
+			w := httptest.NewRecorder()
+			respondError(w, r, s.Err)
+
+			if got, expected := w.Code, s.Expected.Status; got != expected {
+				t.Errorf("unexpected status code, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := w.Header().Get("Content-Type"), ProblemContentType; got != expected {
+				t.Errorf("unexpected content type, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			var body Problem
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got, expected := body, s.Expected; !reflect.DeepEqual(got, expected) {
+				t.Errorf("unexpected problem, got: %+v, expected: %+v", got, expected)
+				return
+			}
+
+			if got, expected := cctx.Error, s.Logged; got != expected {
+				t.Errorf("unexpected error in the logger context, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestErrorsRegistry(t *testing.T) {
+	for code, def := range Errors {
+		if def.Status < 400 || def.Status > 599 || def.Title == "" {
+			t.Errorf("unexpected definition of %s, got: %+v", code, def)
+		}
+
+		if got, expected := def.Internal, def.Status >= 500; got != expected {
+			t.Errorf("unexpected internal flag of %s, got: %v, expected: %v", code, got, expected)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/ratelimit"
+)
+
+type RateLimitKey string
+
+const (
+	// ClientRateLimitKey gives each principal a bucket, each remote address when authentication is off.
+	ClientRateLimitKey RateLimitKey = "client"
+	// AccountRateLimitKey gives each account a bucket, taken from the account_id of the body or the {id} of an
+	// /accounts/{id} route. Requests with neither aren't limited by the rule.
+	AccountRateLimitKey RateLimitKey = "account"
+)
+
+// RateLimitRule limits the requests to the route of Method and Route, a pattern as the router has it, such as
+// /api/v1/accounts/{id}/transactions.
+type RateLimitRule struct {
+	Method string
+	Route  string
+	Key    RateLimitKey
+	Limit  ratelimit.Limit
+}
+
+func (rule RateLimitRule) String() string {
+	return fmt.Sprintf("%s %s %s", rule.Method, rule.Route, rule.Key)
+}
+
+// ParseRateLimitRule reads a rule written as "<method> <route> <client|account> <requests>/<period> [burst]",
+// such as "POST /api/v1/accounts/transaction account 10/1m 20".
+func ParseRateLimitRule(s string) (RateLimitRule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 && len(fields) != 5 {
+		return RateLimitRule{}, fmt.Errorf("rate limit rule %q must be <method> <route> <client|account> <requests>/<period> [burst]", s)
+	}
+
+	rule := RateLimitRule{Method: strings.ToUpper(fields[0]), Route: fields[1], Key: RateLimitKey(fields[2])}
+	if !strings.HasPrefix(rule.Route, "/") {
+		return RateLimitRule{}, fmt.Errorf("rate limit rule %q has a route not starting with /", s)
+	}
+
+	if rule.Key != ClientRateLimitKey && rule.Key != AccountRateLimitKey {
+		return RateLimitRule{}, fmt.Errorf("rate limit rule %q is keyed by %q, expected client or account", s, rule.Key)
+	}
+
+	requests, period, ok := strings.Cut(fields[3], "/")
+	if !ok {
+		return RateLimitRule{}, fmt.Errorf("rate limit rule %q has a rate not written as <requests>/<period>", s)
+	}
+
+	var err error
+	if rule.Limit.Requests, err = strconv.Atoi(requests); err != nil || rule.Limit.Requests < 1 {
+		return RateLimitRule{}, fmt.Errorf("rate limit rule %q must allow a positive number of requests", s)
+	}
+
+	if rule.Limit.Period, err = time.ParseDuration(period); err != nil || rule.Limit.Period <= 0 {
+		return RateLimitRule{}, fmt.Errorf("rate limit rule %q must have a positive period such as 1m", s)
+	}
+
+	if len(fields) == 5 {
+		if rule.Limit.Burst, err = strconv.Atoi(fields[4]); err != nil || rule.Limit.Burst < 1 {
+			return RateLimitRule{}, fmt.Errorf("rate limit rule %q must have a positive burst", s)
+		}
+	}
+
+	return rule, nil
+}
+
+func ParseRateLimitRules(ss []string) ([]RateLimitRule, error) {
+	rules := make([]RateLimitRule, 0, len(ss))
+	for _, s := range ss {
+		rule, err := ParseRateLimitRule(s)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// matchRoute compares path to route segment by segment, a {param} segment matching any and being returned by
+// its name.
+func matchRoute(route, path string) (map[string]string, bool) {
+	rs, ps := strings.Split(strings.Trim(route, "/"), "/"), strings.Split(strings.Trim(path, "/"), "/")
+	if len(rs) != len(ps) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, r := range rs {
+		if strings.HasPrefix(r, "{") && strings.HasSuffix(r, "}") {
+			params[strings.Trim(r, "{}")] = ps[i]
+			continue
+		}
+
+		if r != ps[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func clientID(r *http.Request) string {
+	if p, ok := auth.FromContext(r.Context()); ok {
+		return p.String()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// accountID reads the body, whatever its Content-Type since handlers decode it as JSON anyway, and puts it back
+// for the handler.
+func accountID(w http.ResponseWriter, r *http.Request, route string, params map[string]string) (string, error) {
+	if strings.Contains(route, "/accounts/{id}") {
+		return params["id"], nil
+	}
+
+	body, err := bufferBody(w, r)
+	if err != nil {
+		return "", err
+	}
+
+	var dest struct {
+		AccountID json.Number `json:"account_id"`
+	}
+	if err := json.Unmarshal(body, &dest); err != nil {
+		return "", nil
+	}
+
+	return dest.AccountID.String(), nil
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// RateLimitMiddleware takes a token from the bucket of every rule matching the request, the RateLimit headers
+// tell about the one with the fewest left. A request turned down by a rule has the tokens taken by the others
+// refunded, so it doesn't count against them. A store failing doesn't turn requests down, it's logged and the
+// request goes through.
+func RateLimitMiddleware(usecase ratelimit.UseCase, rules []RateLimitRule, logger log.LoggerRepository) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			type taken struct {
+				key   string
+				limit ratelimit.Limit
+			}
+
+			var (
+				tightest *ratelimit.Decision
+				takens   []taken
+			)
+			for _, rule := range rules {
+				if rule.Method != r.Method {
+					continue
+				}
+
+				params, ok := matchRoute(rule.Route, r.URL.Path)
+				if !ok {
+					continue
+				}
+
+				id := clientID(r)
+				if rule.Key == AccountRateLimitKey {
+					var err error
+					if id, err = accountID(w, r, rule.Route, params); err != nil {
+						respondBodyError(w, r, err)
+						return
+					}
+
+					if id == "" {
+						continue
+					}
+				}
+
+				key := fmt.Sprintf("%s:%s", rule, id)
+				d, err := usecase.Allow(r.Context(), key, rule.Limit)
+				if err != nil {
+					logger.Error(r.Context(), fmt.Sprintf("rate limit of %s not applied: %s", rule, err))
+					continue
+				}
+
+				if tightest == nil || !d.Allowed || (tightest.Allowed && d.Remaining < tightest.Remaining) {
+					tightest = &d
+				}
+
+				if !d.Allowed {
+					logger.Warn(r.Context(), fmt.Sprintf("rate limit of %s exceeded by %s", rule, id))
+					for _, t := range takens {
+						if err := usecase.Refund(r.Context(), t.key, t.limit); err != nil {
+							logger.Error(r.Context(), fmt.Sprintf("token of %s not refunded: %s", t.key, err))
+						}
+					}
+					break
+				}
+				takens = append(takens, taken{key, rule.Limit})
+			}
+
+			if tightest == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(tightest.Reset))
+
+			if !tightest.Allowed {
+				w.Header().Set("Retry-After", seconds(tightest.RetryAfter))
+				respondError(w, r, account.NewHandlerError(account.HandlerTooManyRequestsErrorCode, fmt.Sprintf("rate limit exceeded, retry in %s seconds", seconds(tightest.RetryAfter))))
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/ratelimit"
+)
+
+// MockRateLimitUseCase keeps its buckets in a map and never lets time pass, so a bucket is never refilled.
+type MockRateLimitUseCase struct {
+	buckets map[string]ratelimit.Bucket
+	down    bool
+}
+
+func (mrluc *MockRateLimitUseCase) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error) {
+	if mrluc.down {
+		return ratelimit.Decision{}, errors.New("connection refused")
+	}
+
+	b, d := limit.Take(mrluc.buckets[key], time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC))
+	mrluc.buckets[key] = b
+
+	return d, nil
+}
+
+func (mrluc *MockRateLimitUseCase) Refund(ctx context.Context, key string, limit ratelimit.Limit) error {
+	mrluc.buckets[key] = limit.Refund(mrluc.buckets[key])
+	return nil
+}
+
+func TestParseRateLimitRule(t *testing.T) {
+	suite := []struct {
+		Rule     string
+		Expected RateLimitRule
+		Valid    bool
+	}{
+		{"POST /api/v1/accounts/transaction account 10/1m 20", RateLimitRule{"POST", "/api/v1/accounts/transaction", AccountRateLimitKey, ratelimit.Limit{Requests: 10, Period: time.Minute, Burst: 20}}, true},
+		{"get /api/v1/accounts/{id} client 5/1s", RateLimitRule{"GET", "/api/v1/accounts/{id}", ClientRateLimitKey, ratelimit.Limit{Requests: 5, Period: time.Second}}, true},
+		{"POST /api/v1/accounts/transaction 10/1m", RateLimitRule{}, false},
+		{"POST api/v1/accounts client 10/1m", RateLimitRule{}, false},
+		{"POST /api/v1/accounts tenant 10/1m", RateLimitRule{}, false},
+		{"POST /api/v1/accounts client 10", RateLimitRule{}, false},
+		{"POST /api/v1/accounts client 0/1m", RateLimitRule{}, false},
+		{"POST /api/v1/accounts client 10/0s", RateLimitRule{}, false},
+		{"POST /api/v1/accounts client 10/minute", RateLimitRule{}, false},
+		{"POST /api/v1/accounts client 10/1m -1", RateLimitRule{}, false},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Rule, func(t *testing.T) {
+			rule, err := ParseRateLimitRule(s.Rule)
+			if got, expected := err == nil, s.Valid; got != expected {
+				t.Errorf("unexpected parsing, got error: %v, expected valid: %v", err, expected)
+				return
+			}
+
+			if got, expected := rule, s.Expected; got != expected {
+				t.Errorf("unexpected rule, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rules, err := ParseRateLimitRules([]string{
+		"POST /api/v1/accounts/transaction client 4/1m",
+		"POST /api/v1/accounts/transaction account 2/1m",
+		"GET /api/v1/accounts/{id} account 1/1m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		Principal   string
+		Method      string
+		Path        string
+		ContentType string
+		Body        string
+		Status      int
+		Remaining   string
+	}
+
+	suite := []struct {
+		Name     string
+		Down     bool
+		Requests []request
+	}{
+		{"Account limit reached before the client one", false, []request{
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":1}`, http.StatusOK, "1"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":1}`, http.StatusOK, "0"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":1}`, http.StatusTooManyRequests, "0"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":2}`, http.StatusOK, "1"},
+		}},
+		{"Client limit reached across accounts", false, []request{
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":1}`, http.StatusOK, "1"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":2}`, http.StatusOK, "1"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":3}`, http.StatusOK, "1"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":4}`, http.StatusOK, "0"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":5}`, http.StatusTooManyRequests, "0"},
+			{"c3d4", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":5}`, http.StatusOK, "1"},
+		}},
+		{"Account taken from the path", false, []request{
+			{"a1b2", http.MethodGet, "/api/v1/accounts/1", "", "", http.StatusOK, "0"},
+			{"c3d4", http.MethodGet, "/api/v1/accounts/1", "", "", http.StatusTooManyRequests, "0"},
+			{"c3d4", http.MethodGet, "/api/v1/accounts/2/", "", "", http.StatusOK, "0"},
+		}},
+		{"Routes without a rule", false, []request{
+			{"a1b2", http.MethodGet, "/api/v1/accounts/1/transactions", "", "", http.StatusOK, ""},
+			{"a1b2", http.MethodPost, "/api/v1/accounts", "application/json", `{"account_id":1}`, http.StatusOK, ""},
+		}},
+		{"Account taken from a body sent without Content-Type", false, []request{
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "", `{"account_id":1}`, http.StatusOK, "1"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "text/plain", `{"account_id":1}`, http.StatusOK, "0"},
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "", `{"account_id":1}`, http.StatusTooManyRequests, "0"},
+		}},
+		{"Body too large", false, []request{
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", strings.Repeat(" ", MaxBufferedBodyBytes+1), http.StatusRequestEntityTooLarge, ""},
+		}},
+		{"Store down", true, []request{
+			{"a1b2", http.MethodPost, "/api/v1/accounts/transaction", "application/json", `{"account_id":1}`, http.StatusOK, ""},
+		}},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			usecase := &MockRateLimitUseCase{buckets: make(map[string]ratelimit.Bucket), down: s.Down}
+			var body string
+			handler := RateLimitMiddleware(usecase, rules, MockLoggerRepository{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+			}))
+
+			for i, req := range s.Requests {
+				body = ""
+				r := httptest.NewRequest(req.Method, req.Path, strings.NewReader(req.Body))
+				if req.ContentType != "" {
+					r.Header.Set("Content-Type", req.ContentType)
+				}
+				r = r.WithContext(auth.NewContext(r.Context(), auth.Principal{Kind: auth.APIKeyPrincipalKind, ID: req.Principal}))
+
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+
+				if got, expected := w.Code, req.Status; got != expected {
+					t.Errorf("request %d: unexpected status code, got: %v, expected: %v", i, got, expected)
+					return
+				}
+
+				if got, expected := w.Header().Get("RateLimit-Remaining"), req.Remaining; got != expected {
+					t.Errorf("request %d: unexpected remaining header, got: %v, expected: %v", i, got, expected)
+					return
+				}
+
+				if req.Status == http.StatusOK {
+					if got, expected := body, req.Body; got != expected {
+						t.Errorf("request %d: unexpected body seen by the handler, got: %v, expected: %v", i, got, expected)
+						return
+					}
+					continue
+				}
+
+				if req.Status == http.StatusRequestEntityTooLarge {
+					continue
+				}
+
+				if w.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d: missing retry after header", i)
+					return
+				}
+
+				var herr account.HandlerError
+				if err := json.NewDecoder(w.Body).Decode(&herr); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := herr.Code, account.HandlerTooManyRequestsErrorCode; got != expected {
+					t.Errorf("request %d: unexpected error code, got: %v, expected: %v", i, got, expected)
+					return
+				}
+			}
+		})
+	}
+}
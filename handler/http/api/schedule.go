@@ -0,0 +1,247 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/schedule"
+
+	"github.com/ggicci/httpin"
+	"github.com/go-chi/render"
+)
+
+type ScheduleRecurrenceBody struct {
+	Frequency string `json:"frequency"`
+	Interval  int    `json:"interval"`
+}
+
+type CreateScheduledTransactionRequestBody struct {
+	OperationTypeID uint                    `json:"operation_type_id"`
+	Amount          float64                 `json:"amount"`
+	StartAt         string                  `json:"start_at"`
+	Recurrence      *ScheduleRecurrenceBody `json:"recurrence"`
+	EndAt           string                  `json:"end_at"`
+	MaxOccurrences  int                     `json:"max_occurrences"`
+}
+
+type UpdateScheduledTransactionRequestBody struct {
+	Amount         *float64 `json:"amount"`
+	EndAt          *string  `json:"end_at"`
+	MaxOccurrences *int     `json:"max_occurrences"`
+}
+
+type ScheduledTransactionResponseBody struct {
+	ID              uint                    `json:"id"`
+	AccountID       uint                    `json:"account_id"`
+	OperationTypeID uint                    `json:"operation_type_id"`
+	Amount          float64                 `json:"amount"`
+	StartAt         string                  `json:"start_at"`
+	Recurrence      *ScheduleRecurrenceBody `json:"recurrence,omitempty"`
+	EndAt           string                  `json:"end_at,omitempty"`
+	MaxOccurrences  int                     `json:"max_occurrences,omitempty"`
+	Occurrences     int                     `json:"occurrences"`
+	NextRunAt       string                  `json:"next_run_at,omitempty"`
+	Status          string                  `json:"status"`
+}
+
+func NewScheduledTransactionResponseBody(st schedule.ScheduledTransaction) ScheduledTransactionResponseBody {
+	body := ScheduledTransactionResponseBody{
+		ID:              st.ID,
+		AccountID:       st.AccountID,
+		OperationTypeID: st.OperationTypeID,
+		Amount:          float64(st.Amount) / 100,
+		StartAt:         st.StartAt.Format(time.RFC3339),
+		MaxOccurrences:  st.MaxOccurrences,
+		Occurrences:     st.Occurrences,
+		Status:          string(st.Status),
+	}
+
+	if st.Recurrence != nil {
+		body.Recurrence = &ScheduleRecurrenceBody{Frequency: string(st.Recurrence.Frequency), Interval: st.Recurrence.Interval}
+	}
+
+	if st.EndAt != nil {
+		body.EndAt = st.EndAt.UTC().Format(time.RFC3339)
+	}
+
+	if st.NextRunAt != nil {
+		body.NextRunAt = st.NextRunAt.UTC().Format(time.RFC3339)
+	}
+
+	return body
+}
+
+func parseScheduleTime(w http.ResponseWriter, r *http.Request, value, field string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, "field "+field+" must be formatted as RFC 3339", field))
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+type AccountScheduledTransactionsRequestParams struct {
+	AccountID uint `in:"path=id"`
+}
+
+func CreateScheduledTransaction(usecase schedule.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*AccountScheduledTransactionsRequestParams)
+
+		var body CreateScheduledTransactionRequestBody
+		if err := render.DecodeJSON(r.Body, &body); err != nil {
+			respondDecodeError(w, r, err)
+			return
+		}
+		defer r.Body.Close()
+
+		startAt, ok := parseScheduleTime(w, r, body.StartAt, "start_at")
+		if !ok {
+			return
+		}
+
+		options := schedule.CreateScheduledTransactionOptions{
+			AccountID:       params.AccountID,
+			OperationTypeID: body.OperationTypeID,
+			Amount:          int64(body.Amount * 100),
+			StartAt:         startAt,
+			MaxOccurrences:  body.MaxOccurrences,
+		}
+
+		if body.Recurrence != nil {
+			options.Recurrence = &schedule.Recurrence{
+				Frequency: schedule.Frequency(body.Recurrence.Frequency),
+				Interval:  body.Recurrence.Interval,
+			}
+			if options.Recurrence.Interval == 0 {
+				options.Recurrence.Interval = 1
+			}
+		}
+
+		if body.EndAt != "" {
+			endAt, ok := parseScheduleTime(w, r, body.EndAt, "end_at")
+			if !ok {
+				return
+			}
+			options.EndAt = &endAt
+		}
+
+		st, err := usecase.CreateScheduledTransaction(r.Context(), options)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+
+		render.Respond(w, r, NewScheduledTransactionResponseBody(st))
+
+		logger.Info(r.Context(), "scheduled transaction created successful")
+	}
+}
+
+func ListAccountScheduledTransactions(usecase schedule.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*AccountScheduledTransactionsRequestParams)
+
+		sts, err := usecase.ListScheduledTransactionsByAccountID(r.Context(), params.AccountID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		body := make([]ScheduledTransactionResponseBody, 0, len(sts))
+		for _, st := range sts {
+			body = append(body, NewScheduledTransactionResponseBody(st))
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, body)
+
+		logger.Info(r.Context(), "account scheduled transactions listed successful")
+	}
+}
+
+type ScheduledTransactionRequestParams struct {
+	ScheduledTransactionID uint `in:"path=id"`
+}
+
+func GetScheduledTransactionByID(usecase schedule.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*ScheduledTransactionRequestParams)
+
+		st, err := usecase.GetScheduledTransactionByID(r.Context(), params.ScheduledTransactionID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, NewScheduledTransactionResponseBody(st))
+
+		logger.Info(r.Context(), "scheduled transaction retrieved by id successful")
+	}
+}
+
+func UpdateScheduledTransaction(usecase schedule.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*ScheduledTransactionRequestParams)
+
+		var body UpdateScheduledTransactionRequestBody
+		if err := render.DecodeJSON(r.Body, &body); err != nil {
+			respondDecodeError(w, r, err)
+			return
+		}
+		defer r.Body.Close()
+
+		options := schedule.UpdateScheduledTransactionOptions{
+			ID:             params.ScheduledTransactionID,
+			MaxOccurrences: body.MaxOccurrences,
+		}
+
+		if body.Amount != nil {
+			amount := int64(*body.Amount * 100)
+			options.Amount = &amount
+		}
+
+		if body.EndAt != nil {
+			endAt, ok := parseScheduleTime(w, r, *body.EndAt, "end_at")
+			if !ok {
+				return
+			}
+			options.EndAt = &endAt
+		}
+
+		st, err := usecase.UpdateScheduledTransaction(r.Context(), options)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, NewScheduledTransactionResponseBody(st))
+
+		logger.Info(r.Context(), "scheduled transaction updated successful")
+	}
+}
+
+func CancelScheduledTransaction(usecase schedule.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*ScheduledTransactionRequestParams)
+
+		if err := usecase.CancelScheduledTransaction(r.Context(), params.ScheduledTransactionID); err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		logger.Info(r.Context(), "scheduled transaction canceled successful")
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/statement"
+
+	"github.com/ggicci/httpin"
+	"github.com/go-chi/render"
+)
+
+const StatementDateLayout = "2006-01-02"
+
+type ListAccountDailyTotalsRequestParams struct {
+	AccountID uint   `in:"path=id"`
+	From      string `in:"query=from"`
+	To        string `in:"query=to"`
+}
+
+type DailyTotalResponseBody struct {
+	Date   string  `json:"date"`
+	Amount float64 `json:"amount"`
+	Count  int     `json:"count"`
+}
+
+func parseStatementDate(w http.ResponseWriter, r *http.Request, value, param string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+
+	t, err := time.Parse(StatementDateLayout, value)
+	if err != nil {
+		respondError(w, r, account.NewHandlerInvalidParamError(account.HandlerBadRequestErrorCode, "parameter "+param+" must be formatted as YYYY-MM-DD", param))
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+func ListAccountDailyTotals(usecase statement.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*ListAccountDailyTotalsRequestParams)
+
+		from, ok := parseStatementDate(w, r, params.From, "from")
+		if !ok {
+			return
+		}
+
+		to, ok := parseStatementDate(w, r, params.To, "to")
+		if !ok {
+			return
+		}
+
+		totals, err := usecase.ListDailyTotals(r.Context(), statement.ListDailyTotalsOptions{AccountID: params.AccountID, From: from, To: to})
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		body := make([]DailyTotalResponseBody, 0, len(totals))
+		for _, t := range totals {
+			body = append(body, DailyTotalResponseBody{
+				Date:   t.Date.Format(StatementDateLayout),
+				Amount: float64(t.Amount) / 100,
+				Count:  t.Count,
+			})
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, body)
+
+		logger.Info(r.Context(), "account daily totals listed successful")
+	}
+}
+
+type GetAccountStatementRequestParams struct {
+	AccountID uint   `in:"path=id"`
+	From      string `in:"query=from"`
+	To        string `in:"query=to"`
+}
+
+func newStatementWriter(w http.ResponseWriter, r *http.Request, contentType string) (statement.Writer, *responseWriter) {
+	switch contentType {
+	case CSVContentType:
+		sw := &csvStatementWriter{responseWriter: responseWriter{w: w}}
+		return sw, &sw.responseWriter
+	case OFXContentType:
+		sw := &ofxStatementWriter{responseWriter: responseWriter{w: w}, now: time.Now()}
+		return sw, &sw.responseWriter
+	case HTMLContentType, TextContentType:
+		tmpl := templateExecutor(statementHTMLTemplate)
+		if contentType == TextContentType {
+			tmpl = statementTextTemplate
+		}
+		sw := &templateStatementWriter{
+			responseWriter: responseWriter{w: w},
+			tmpl:           tmpl,
+			contentType:    contentType,
+			locale:         negotiateLocale(r.Header.Get("Accept-Language")),
+			now:            time.Now(),
+		}
+		return sw, &sw.responseWriter
+	default:
+		sw := &jsonStatementWriter{responseWriter: responseWriter{w: w}}
+		return sw, &sw.responseWriter
+	}
+}
+
+func GetAccountStatement(usecase statement.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*GetAccountStatementRequestParams)
+
+		contentType, ok := negotiate(r.Header.Get("Accept"), JSONContentType, CSVContentType, OFXContentType, HTMLContentType, TextContentType)
+		if !ok {
+			respondError(w, r, account.NewHandlerError(account.HandlerNotAcceptableErrorCode, "statement is available as application/json, text/csv, application/x-ofx, text/html or text/plain"))
+			return
+		}
+
+		from, ok := parseStatementDate(w, r, params.From, "from")
+		if !ok {
+			return
+		}
+
+		to, ok := parseStatementDate(w, r, params.To, "to")
+		if !ok {
+			return
+		}
+
+		sw, rw := newStatementWriter(w, r, contentType)
+		if err := usecase.WriteStatement(r.Context(), statement.WriteStatementOptions{AccountID: params.AccountID, From: from, To: to}, sw); err != nil {
+			// Rows were already sent with a 200, aborting drops the connection so the client can't take a
+			// truncated statement for a complete one.
+			if rw.began {
+				logger.Error(r.Context(), err.Error())
+				panic(http.ErrAbortHandler)
+			}
+
+			respondError(w, r, err)
+			return
+		}
+
+		logger.Info(r.Context(), "account statement written successful")
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/statement"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+var (
+	statementHTMLTemplate = htmltemplate.Must(htmltemplate.ParseFS(templatesFS, "templates/statement.html"))
+	statementTextTemplate = texttemplate.Must(texttemplate.ParseFS(templatesFS, "templates/statement.txt"))
+	statementCSS          = mustReadAsset("templates/statement.css")
+)
+
+func mustReadAsset(name string) string {
+	data, err := templatesFS.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(data)
+}
+
+type templateExecutor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+type statementView struct {
+	Locale    *Locale
+	Statement statement.Statement
+	Now       time.Time
+	CSS       htmltemplate.CSS
+}
+
+type transactionView struct {
+	Locale      *Locale
+	Transaction account.Transaction
+	Date        time.Time
+	Description string
+	Balance     int64
+}
+
+// templateStatementWriter renders the printable statements, executing the begin, transaction and end
+// templates as the statement is read so rows are still streamed.
+type templateStatementWriter struct {
+	responseWriter
+	tmpl        templateExecutor
+	contentType string
+	locale      *Locale
+	now         time.Time
+	loc         *time.Location
+	balance     int64
+}
+
+func (tw *templateStatementWriter) view(st statement.Statement) statementView {
+	return statementView{tw.locale, st, tw.now.In(tw.loc), htmltemplate.CSS(statementCSS)}
+}
+
+func (tw *templateStatementWriter) Begin(st statement.Statement) error {
+	tw.w.Header().Set("Content-Language", tw.locale.Tag)
+	tw.begin(tw.contentType+"; charset=utf-8", "")
+	tw.loc, tw.balance = st.Account.Location(), st.OpeningBalance
+
+	return tw.tmpl.ExecuteTemplate(tw.w, "begin", tw.view(st))
+}
+
+func (tw *templateStatementWriter) Transaction(tx account.Transaction) error {
+	tw.balance += tx.Amount
+
+	view := transactionView{Locale: tw.locale, Transaction: tx, Date: tx.EventDate.In(tw.loc), Balance: tw.balance}
+	if tx.Merchant != nil {
+		view.Description = tx.Merchant.Name
+	}
+
+	return tw.tmpl.ExecuteTemplate(tw.w, "transaction", view)
+}
+
+func (tw *templateStatementWriter) End(st statement.Statement) error {
+	return tw.tmpl.ExecuteTemplate(tw.w, "end", tw.view(st))
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/statement"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func writeStatement(t *testing.T, sw statement.Writer) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := statement.Statement{
+		Account:        account.Account{ID: 1, DocumentNumber: "12345678900", TimeZone: "America/Sao_Paulo"},
+		From:           time.Date(2023, time.March, 1, 0, 0, 0, 0, loc),
+		To:             time.Date(2023, time.March, 31, 0, 0, 0, 0, loc),
+		OpeningBalance: 1_234_567_89,
+	}
+	txs := []account.Transaction{
+		{ID: 10, OperationTypeID: account.CashPurchaseOperationTypeID, Amount: -25_50, EventDate: time.Date(2023, time.March, 2, 2, 30, 0, 0, time.UTC), Merchant: &account.Merchant{Name: "Padaria <Real> & Cia"}},
+		{ID: 11, OperationTypeID: account.InstallmentPurchaseOperationTypeID, Amount: -1_500_00, EventDate: time.Date(2023, time.March, 15, 18, 0, 0, 0, time.UTC)},
+		{ID: 12, OperationTypeID: account.PaymentOperationTypeID, Amount: 2_000_00, EventDate: time.Date(2023, time.March, 20, 12, 0, 0, 0, time.UTC)},
+	}
+
+	if err := sw.Begin(st); err != nil {
+		t.Fatal(err)
+	}
+
+	st.ClosingBalance = st.OpeningBalance
+	for _, tx := range txs {
+		if err := sw.Transaction(tx); err != nil {
+			t.Fatal(err)
+		}
+		st.ClosingBalance += tx.Amount
+		st.Count += 1
+	}
+
+	if err := sw.End(st); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatementTemplates(t *testing.T) {
+	suite := []struct {
+		ContentType string
+		Template    templateExecutor
+		Locale      string
+		Golden      string
+	}{
+		{HTMLContentType, statementHTMLTemplate, "pt-BR", "statement.pt-BR.html.golden"},
+		{HTMLContentType, statementHTMLTemplate, "en-US", "statement.en-US.html.golden"},
+		{TextContentType, statementTextTemplate, "pt-BR", "statement.pt-BR.txt.golden"},
+		{TextContentType, statementTextTemplate, "en-US", "statement.en-US.txt.golden"},
+	}
+
+	for _, s := range suite {
+		rec := httptest.NewRecorder()
+		writeStatement(t, &templateStatementWriter{
+			responseWriter: responseWriter{w: rec},
+			tmpl:           s.Template,
+			contentType:    s.ContentType,
+			locale:         Locales[s.Locale],
+			now:            time.Date(2023, time.April, 1, 13, 5, 0, 0, time.UTC),
+		})
+
+		golden := filepath.Join("testdata", s.Golden)
+		if *update {
+			if err := os.WriteFile(golden, rec.Body.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got := rec.Body.String(); got != string(expected) {
+			t.Errorf("unexpected %s statement, got:\n%v\nexpected:\n%v", s.Golden, got, string(expected))
+			return
+		}
+
+		if got, expected := rec.Header().Get("Content-Language"), s.Locale; got != expected {
+			t.Errorf("unexpected content language, got: %v, expected: %v", got, expected)
+			return
+		}
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	suite := []struct {
+		AcceptLanguage string
+		Expected       string
+	}{
+		{"", "en-US"},
+		{"pt-BR", "pt-BR"},
+		{"pt", "pt-BR"},
+		{"pt-PT;q=0.9, en;q=0.8", "pt-BR"},
+		{"fr-FR, en-GB;q=0.5, pt;q=0.4", "en-US"},
+		{"fr-FR", "en-US"},
+	}
+
+	for _, s := range suite {
+		if got, expected := negotiateLocale(s.AcceptLanguage).Tag, s.Expected; got != expected {
+			t.Errorf("unexpected locale for %q, got: %v, expected: %v", s.AcceptLanguage, got, expected)
+			return
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/statement"
+)
+
+const (
+	JSONContentType = "application/json"
+	CSVContentType  = "text/csv"
+	OFXContentType  = "application/x-ofx"
+	HTMLContentType = "text/html"
+	TextContentType = "text/plain"
+)
+
+const (
+	OFXBankID   = "bank"
+	OFXCurrency = "BRL"
+)
+
+type acceptField struct {
+	value string
+	q     float64
+}
+
+// acceptFields parses Accept-like headers, lowercased values with their quality, skipping malformed ones.
+func acceptFields(header string) []acceptField {
+	fields := make([]acceptField, 0)
+	for _, field := range strings.Split(header, ",") {
+		value, params, err := mime.ParseMediaType(strings.TrimSpace(field))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		fields = append(fields, acceptField{value, q})
+	}
+
+	return fields
+}
+
+// negotiate picks the offer the Accept header prefers, the first offer when it's empty.
+func negotiate(accept string, offers ...string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, f := range acceptFields(accept) {
+		for _, offer := range offers {
+			if f.q > bestQ && matchMediaType(f.value, offer) {
+				best, bestQ = offer, f.q
+			}
+		}
+	}
+
+	return best, best != ""
+}
+
+func matchMediaType(mediaType, offer string) bool {
+	if mediaType == "*/*" || mediaType == offer {
+		return true
+	}
+
+	return strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaType, "*"))
+}
+
+// formatCents writes an amount in cents with two decimal places and no float rounding.
+func formatCents(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+func statementFilename(st statement.Statement, ext string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", st.Account.ID, st.From.Format(StatementDateLayout), st.To.Format(StatementDateLayout), ext)
+}
+
+// responseWriter sends the headers on Begin, after that errors can't change the response status anymore.
+type responseWriter struct {
+	w     http.ResponseWriter
+	began bool
+}
+
+func (rw *responseWriter) begin(contentType, filename string) {
+	rw.w.Header().Set("Content-Type", contentType)
+	if filename != "" {
+		rw.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	rw.w.WriteHeader(http.StatusOK)
+	rw.began = true
+}
+
+type jsonStatementWriter struct {
+	responseWriter
+	n int
+}
+
+func (jw *jsonStatementWriter) Begin(st statement.Statement) error {
+	jw.begin(JSONContentType+"; charset=utf-8", "")
+
+	_, err := fmt.Fprintf(jw.w, `{"account_id":%d,"from":%q,"to":%q,"opening_balance":%s,"transactions":[`,
+		st.Account.ID, st.From.Format(StatementDateLayout), st.To.Format(StatementDateLayout), formatCents(st.OpeningBalance))
+	return err
+}
+
+func (jw *jsonStatementWriter) Transaction(tx account.Transaction) error {
+	data, err := json.Marshal(NewTransactionResponseBody(tx))
+	if err != nil {
+		return err
+	}
+
+	if jw.n > 0 {
+		if _, err := io.WriteString(jw.w, ","); err != nil {
+			return err
+		}
+	}
+	jw.n += 1
+
+	_, err = jw.w.Write(data)
+	return err
+}
+
+func (jw *jsonStatementWriter) End(st statement.Statement) error {
+	_, err := fmt.Fprintf(jw.w, `],"closing_balance":%s,"count":%d}`+"\n", formatCents(st.ClosingBalance), st.Count)
+	return err
+}
+
+// csvStatementWriter writes one row per transaction with the running balance, between an opening and
+// a closing balance row. Dates are days in the account's time zone.
+type csvStatementWriter struct {
+	responseWriter
+	cw      *csv.Writer
+	loc     *time.Location
+	balance int64
+}
+
+func (cw *csvStatementWriter) Begin(st statement.Statement) error {
+	cw.begin(CSVContentType+"; charset=utf-8", statementFilename(st, "csv"))
+	cw.cw = csv.NewWriter(cw.w)
+	cw.loc, cw.balance = st.Account.Location(), st.OpeningBalance
+
+	if err := cw.cw.Write([]string{"date", "transaction_id", "operation_type_id", "description", "amount", "balance"}); err != nil {
+		return err
+	}
+
+	return cw.cw.Write([]string{st.From.Format(StatementDateLayout), "", "", "opening balance", "", formatCents(st.OpeningBalance)})
+}
+
+func (cw *csvStatementWriter) Transaction(tx account.Transaction) error {
+	cw.balance += tx.Amount
+
+	description := ""
+	if tx.Merchant != nil {
+		description = tx.Merchant.Name
+	}
+
+	return cw.cw.Write([]string{
+		tx.EventDate.In(cw.loc).Format(StatementDateLayout),
+		strconv.FormatUint(uint64(tx.ID), 10),
+		strconv.FormatUint(uint64(tx.OperationTypeID), 10),
+		description,
+		formatCents(tx.Amount),
+		formatCents(cw.balance),
+	})
+}
+
+func (cw *csvStatementWriter) End(st statement.Statement) error {
+	if err := cw.cw.Write([]string{st.To.Format(StatementDateLayout), "", "", "closing balance", "", formatCents(st.ClosingBalance)}); err != nil {
+		return err
+	}
+
+	cw.cw.Flush()
+	return cw.cw.Error()
+}
+
+const ofxDateLayout = "20060102150405.000"
+
+func ofxDate(t time.Time) string {
+	return t.UTC().Format(ofxDateLayout) + "[0:GMT]"
+}
+
+func ofxText(value string, max int) string {
+	if runes := []rune(value); len(runes) > max {
+		value = string(runes[:max])
+	}
+
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(value))
+	return sb.String()
+}
+
+// ofxStatementWriter writes an OFX 2.2 bank statement. The closing balance goes to LEDGERBAL and the
+// opening one to BALLIST, since OFX has no dedicated element for it.
+type ofxStatementWriter struct {
+	responseWriter
+	now time.Time
+}
+
+func (ow *ofxStatementWriter) Begin(st statement.Statement) error {
+	ow.begin(OFXContentType, statementFilename(st, "ofx"))
+
+	_, err := fmt.Fprintf(ow.w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF><BANKACCTFROM><BANKID>%s</BANKID><ACCTID>%d</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, ofxDate(ow.now), OFXCurrency, OFXBankID, st.Account.ID, ofxDate(st.From), ofxDate(st.To.AddDate(0, 0, 1)))
+	return err
+}
+
+func (ow *ofxStatementWriter) Transaction(tx account.Transaction) error {
+	trnType := "CREDIT"
+	if tx.Amount < 0 {
+		trnType = "DEBIT"
+	}
+
+	name := ""
+	if tx.Merchant != nil && tx.Merchant.Name != "" {
+		name = "<NAME>" + ofxText(tx.Merchant.Name, 32) + "</NAME>"
+	}
+
+	_, err := fmt.Fprintf(ow.w, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%d</FITID>%s</STMTTRN>\n",
+		trnType, ofxDate(tx.EventDate), formatCents(tx.Amount), tx.ID, name)
+	return err
+}
+
+func (ow *ofxStatementWriter) End(st statement.Statement) error {
+	end := ofxDate(st.To.AddDate(0, 0, 1))
+
+	_, err := fmt.Fprintf(ow.w, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+<BALLIST><BAL><NAME>Opening balance</NAME><DESC>Balance before %s</DESC><BALTYPE>DOLLAR</BALTYPE><VALUE>%s</VALUE><DTASOF>%s</DTASOF></BAL></BALLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`, formatCents(st.ClosingBalance), end, st.From.Format(StatementDateLayout), formatCents(st.OpeningBalance), ofxDate(st.From))
+	return err
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/log"
+
+	"github.com/ggicci/httpin"
+	"github.com/go-chi/render"
+)
+
+type MerchantBody struct {
+	Name       string `json:"name,omitempty"`
+	MCC        string `json:"mcc,omitempty"`
+	City       string `json:"city,omitempty"`
+	Country    string `json:"country,omitempty"`
+	TerminalID string `json:"terminal_id,omitempty"`
+}
+
+func (mb *MerchantBody) toDomain() *account.Merchant {
+	if mb == nil {
+		return nil
+	}
+
+	return &account.Merchant{Name: mb.Name, MCC: mb.MCC, City: mb.City, Country: mb.Country, TerminalID: mb.TerminalID}
+}
+
+type TransactionResponseBody struct {
+	ID              uint              `json:"id"`
+	AccountID       uint              `json:"account_id"`
+	OperationTypeID uint              `json:"operation_type_id"`
+	Amount          float64           `json:"amount"`
+	EventDate       string            `json:"event_date"`
+	Merchant        *MerchantBody     `json:"merchant,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+func NewTransactionResponseBody(tx account.Transaction) TransactionResponseBody {
+	body := TransactionResponseBody{
+		ID:              tx.ID,
+		AccountID:       tx.AccountID,
+		OperationTypeID: tx.OperationTypeID,
+		Amount:          float64(tx.Amount) / 100,
+		EventDate:       tx.EventDate.Format(time.RFC3339),
+		Metadata:        tx.Metadata,
+	}
+
+	if m := tx.Merchant; m != nil {
+		body.Merchant = &MerchantBody{Name: m.Name, MCC: m.MCC, City: m.City, Country: m.Country, TerminalID: m.TerminalID}
+	}
+
+	return body
+}
+
+type GetTransactionByIDRequestParams struct {
+	TransactionID uint `in:"path=id"`
+}
+
+func GetTransactionByID(usecase account.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*GetTransactionByIDRequestParams)
+
+		tx, err := usecase.GetTransactionByID(r.Context(), params.TransactionID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, NewTransactionResponseBody(tx))
+
+		logger.Info(r.Context(), "transaction retrieved by id successful")
+	}
+}
+
+type ListAccountTransactionsRequestParams struct {
+	AccountID uint   `in:"path=id"`
+	MCC       string `in:"query=mcc"`
+	From      string `in:"query=from"`
+	To        string `in:"query=to"`
+}
+
+func ListAccountTransactions(usecase account.UseCase, logger log.LoggerRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*ListAccountTransactionsRequestParams)
+
+		options := account.ListTransactionsOptions{AccountID: params.AccountID, MCC: params.MCC}
+		for _, p := range []struct {
+			Value string
+			Dest  *time.Time
+		}{{params.From, &options.From}, {params.To, &options.To}} {
+			if p.Value == "" {
+				continue
+			}
+
+			t, err := time.Parse(time.RFC3339, p.Value)
+			if err != nil {
+				respondError(w, r, account.NewHandlerError(account.HandlerBadRequestErrorCode, "parameters from and to must be formatted as RFC 3339"))
+				return
+			}
+			*p.Dest = t
+		}
+
+		txs, err := usecase.ListTransactions(r.Context(), options)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		body := make([]TransactionResponseBody, 0, len(txs))
+		for _, tx := range txs {
+			body = append(body, NewTransactionResponseBody(tx))
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.Respond(w, r, body)
+
+		logger.Info(r.Context(), "account transactions listed successful")
+	}
+}
+
+const DefaultBatchMaxItems = 500
+
+type CreateTransactionsBatchRequestParams struct {
+	Atomic bool `in:"query=atomic"`
+}
+
+type CreateTransactionsBatchRequestBody struct {
+	Items []CreateAccountTransactionRequestBody `json:"items"`
+}
+
+type BatchItemResponseBody struct {
+	Index  int      `json:"index"`
+	Status string   `json:"status"`
+	ID     uint     `json:"id,omitempty"`
+	Error  *Problem `json:"error,omitempty"`
+}
+
+type CreateTransactionsBatchResponseBody struct {
+	Items []BatchItemResponseBody `json:"items"`
+}
+
+// CreateTransactionsBatch answers 201 when every item is created, 207 when only some of them are
+// and 422 when none is.
+func CreateTransactionsBatch(usecase account.UseCase, maxItems int, logger log.LoggerRepository) http.HandlerFunc {
+	validator, rulesErr := newTransactionValidator()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(httpin.Input).(*CreateTransactionsBatchRequestParams)
+
+		var body CreateTransactionsBatchRequestBody
+		if err := render.DecodeJSON(r.Body, &body); err != nil {
+			respondDecodeError(w, r, err)
+			return
+		}
+		defer r.Body.Close()
+
+		if rulesErr != nil {
+			logger.Error(r.Context(), rulesErr.Error())
+			respondError(w, r, account.NewHandlerError(account.HandlerUnknwonErrorCode, rulesErr.Error()))
+			return
+		}
+
+		if len(body.Items) == 0 || len(body.Items) > maxItems {
+			respondError(w, r, account.NewHandlerInvalidFieldError(account.HandlerInvalidPayloadErrorCode, fmt.Sprintf("batch must have from 1 to %d items", maxItems), "items"))
+			return
+		}
+
+		results := make([]BatchItemResponseBody, len(body.Items))
+		items := make([]account.CreateTransactionOptions, 0, len(body.Items))
+		indexes := make([]int, 0, len(body.Items))
+		for i, item := range body.Items {
+			options, verr := toCreateTransactionOptions(validator, item)
+			if verr != nil {
+				results[i] = BatchItemResponseBody{Index: i, Status: string(account.RejectedBatchItemStatus), Error: problem(r, verr)}
+				continue
+			}
+
+			items = append(items, options)
+			indexes = append(indexes, i)
+		}
+
+		var created []account.BatchItemResult
+		if len(items) > 0 && !(params.Atomic && len(items) < len(body.Items)) {
+			var err error
+			if created, err = usecase.CreateTransactions(r.Context(), account.CreateTransactionsOptions{Items: items, Atomic: params.Atomic}); err != nil {
+				respondError(w, r, err)
+				return
+			}
+		}
+
+		for n, i := range indexes {
+			result := BatchItemResponseBody{Index: i, Status: string(account.AbortedBatchItemStatus)}
+			if n < len(created) {
+				result.Status, result.ID = string(created[n].Status), created[n].ID
+				if created[n].Err != nil {
+					result.Error = problem(r, created[n].Err)
+				}
+			}
+			results[i] = result
+		}
+
+		ncreated := 0
+		for _, result := range results {
+			if result.Status == string(account.CreatedBatchItemStatus) {
+				ncreated += 1
+			}
+		}
+
+		switch ncreated {
+		case len(results):
+			render.Status(r, http.StatusCreated)
+		case 0:
+			render.Status(r, http.StatusUnprocessableEntity)
+		default:
+			render.Status(r, http.StatusMultiStatus)
+		}
+
+		render.Respond(w, r, CreateTransactionsBatchResponseBody{Items: results})
+
+		logger.Info(r.Context(), fmt.Sprintf("transactions batch handled, %d of %d created", ncreated, len(results)))
+	}
+}
@@ -0,0 +1,230 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github/guiferpa/bank/domain/auth"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Only asymmetric algorithms are accepted, the JWKS holds public keys.
+var (
+	rsaMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	ecMethods  = []string{"ES256", "ES384", "ES512"}
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func decodeInt(field, value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid %s", field)
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt("n", k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeInt("e", k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeInt("x", k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeInt("y", k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point isn't on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// ParseJWKS reads the signing keys of a JSON Web Key Set by their key ID, keys meant for encryption are left out.
+func ParseJWKS(data []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwks key %d: %w", i, err)
+		}
+
+		if _, ok := keys[k.Kid]; ok {
+			return nil, fmt.Errorf("invalid jwks: duplicated kid %q", k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("invalid jwks: no signing keys")
+	}
+
+	return keys, nil
+}
+
+// Scope is space separated as in OAuth 2.0, Tenant makes the subject a partner.
+type claims struct {
+	jwt.RegisteredClaims
+	Name   string `json:"name,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+type Verifier struct {
+	keys     map[string]interface{}
+	issuer   string
+	audience string
+	parser   *jwt.Parser
+}
+
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := v.keys[kid]
+	if !ok && kid == "" && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			key, ok = only, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); ok {
+			return key, nil
+		}
+		if _, ok := token.Method.(*jwt.SigningMethodRSAPSS); ok {
+			return key, nil
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("algorithm %s doesn't match the key %q", token.Method.Alg(), kid)
+}
+
+// Verify accepts a token signed by a key of the set, not expired and, when they're configured, from the issuer
+// and for the audience. Its subject is the principal, granted the scopes of the token which this API knows.
+func (v *Verifier) Verify(ctx context.Context, raw string) (auth.Principal, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(raw, &c, v.key); err != nil {
+		return auth.Principal{}, err
+	}
+
+	if c.ExpiresAt == nil {
+		return auth.Principal{}, errors.New("token has no expiration")
+	}
+
+	if c.Subject == "" {
+		return auth.Principal{}, errors.New("token has no subject")
+	}
+
+	if v.issuer != "" && !c.VerifyIssuer(v.issuer, true) {
+		return auth.Principal{}, errors.New("token has an unexpected issuer")
+	}
+
+	if v.audience != "" && !c.VerifyAudience(v.audience, true) {
+		return auth.Principal{}, errors.New("token has an unexpected audience")
+	}
+
+	scopes := make([]auth.Scope, 0)
+	for _, s := range strings.Fields(c.Scope) {
+		if scope, err := auth.ParseScope(s); err == nil {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return auth.Principal{Kind: auth.TokenPrincipalKind, ID: c.Subject, Name: c.Name, Scopes: scopes, Tenant: c.Tenant}, nil
+}
+
+type NewVerifierOptions struct {
+	// JWKSFile is a JSON Web Key Set with the public keys tokens are signed by.
+	JWKSFile string
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+}
+
+func NewVerifier(opts NewVerifierOptions) (*Verifier, error) {
+	data, err := os.ReadFile(opts.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verifier{
+		keys:     keys,
+		issuer:   opts.Issuer,
+		audience: opts.Audience,
+		parser:   jwt.NewParser(jwt.WithValidMethods(append(append([]string{}, rsaMethods...), ecMethods...))),
+	}, nil
+}
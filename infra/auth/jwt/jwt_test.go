@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/auth"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, c jwt.Claims) string {
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
+
+func TestVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewVerifier(NewVerifierOptions{JWKSFile: path, Issuer: "https://idp.example.com", Audience: "bank"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	valid := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "partner-1",
+			Issuer:    "https://idp.example.com",
+			Audience:  jwt.ClaimStrings{"bank"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Name:   "Partner",
+		Scope:  "accounts:read payments:write",
+		Tenant: "partner",
+	}
+	with := func(change func(c *claims)) claims {
+		c := valid
+		change(&c)
+		return c
+	}
+
+	suite := []struct {
+		Name  string
+		Token string
+		Valid bool
+	}{
+		{"RS256 signed by a key of the set", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, valid), true},
+		{"ES256 signed by a key of the set", sign(t, jwt.SigningMethodES256, "ec", ecKey, valid), true},
+		{"Signed by a key out of the set", sign(t, jwt.SigningMethodRS256, "rsa", otherKey, valid), false},
+		{"Unknown kid", sign(t, jwt.SigningMethodRS256, "other", rsaKey, valid), false},
+		{"Algorithm not matching the key", sign(t, jwt.SigningMethodES256, "rsa", ecKey, valid), false},
+		{"Symmetric algorithm", sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), valid), false},
+		{"Unsigned", sign(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, valid), false},
+		{"Expired", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c *claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) })), false},
+		{"Without expiration", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c *claims) { c.ExpiresAt = nil })), false},
+		{"Without subject", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c *claims) { c.Subject = "" })), false},
+		{"Another issuer", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c *claims) { c.Issuer = "https://evil.example.com" })), false},
+		{"Another audience", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c *claims) { c.Audience = jwt.ClaimStrings{"other"} })), false},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			p, err := verifier.Verify(context.Background(), s.Token)
+			if got, expected := err == nil, s.Valid; got != expected {
+				t.Errorf("unexpected verification, got error: %v, expected valid: %v", err, expected)
+				return
+			}
+
+			if !s.Valid {
+				return
+			}
+
+			expected := auth.Principal{Kind: auth.TokenPrincipalKind, ID: "partner-1", Name: "Partner", Scopes: []auth.Scope{auth.ReadAccountsScope}, Tenant: "partner"}
+			if got := p; !reflect.DeepEqual(got, expected) {
+				t.Errorf("unexpected principal, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
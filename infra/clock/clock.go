@@ -0,0 +1,25 @@
+package clock
+
+import "time"
+
+type SystemClock struct{}
+
+func (sc *SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+func NewSystemClock() *SystemClock {
+	return &SystemClock{}
+}
+
+type FixedClock struct {
+	at time.Time
+}
+
+func (fc *FixedClock) Now() time.Time {
+	return fc.at
+}
+
+func NewFixedClock(at time.Time) *FixedClock {
+	return &FixedClock{at}
+}
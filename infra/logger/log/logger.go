@@ -5,6 +5,7 @@ import (
 	"github/guiferpa/bank/domain/log"
 	"os"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -13,32 +14,78 @@ type Logger struct {
 	logger *zap.Logger
 }
 
+// traceID is left out of the entry when ctx carries no span.
+func traceID(ctx context.Context) zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return zap.Skip()
+	}
+
+	return zap.String("trace_id", sc.TraceID().String())
+}
+
+// principal is left out of the entry when the request isn't authenticated.
+func principal(cctx *log.LoggerContext) zap.Field {
+	if cctx == nil || cctx.Principal == "" {
+		return zap.Skip()
+	}
+
+	return zap.String("principal", cctx.Principal)
+}
+
+// loggerContext is empty when ctx carries none, as the ones of background work such as health checks.
+func loggerContext(ctx context.Context) *log.LoggerContext {
+	if cctx, ok := ctx.Value(log.LoggerContextKey).(*log.LoggerContext); ok && cctx != nil {
+		return cctx
+	}
+
+	return &log.LoggerContext{}
+}
+
 func (l *Logger) Error(ctx context.Context, msg string) {
-	cctx, _ := ctx.Value(log.LoggerContextKey).(*log.LoggerContext)
+	cctx := loggerContext(ctx)
 
 	l.logger.Error(msg,
 		zap.String("request_id", cctx.RequestID),
+		principal(cctx),
+		traceID(ctx),
 	)
 }
 
 func (l *Logger) Warn(ctx context.Context, msg string) {
-	cctx, _ := ctx.Value(log.LoggerContextKey).(*log.LoggerContext)
+	cctx := loggerContext(ctx)
 
 	l.logger.Warn(msg,
 		zap.String("request_id", cctx.RequestID),
+		principal(cctx),
+		traceID(ctx),
 	)
 }
 
 func (l *Logger) Info(ctx context.Context, msg string) {
-	cctx, _ := ctx.Value(log.LoggerContextKey).(*log.LoggerContext)
+	cctx := loggerContext(ctx)
 
 	l.logger.Info(msg,
 		zap.String("request_id", cctx.RequestID),
+		principal(cctx),
+		traceID(ctx),
 		zap.Any("payload", cctx.Payload),
 	)
 }
 
-func NewLogger() *Logger {
+type NewLoggerOptions struct {
+	// Level is one of debug, info, warn or error, debug when it's empty.
+	Level string
+}
+
+func NewLogger(opts NewLoggerOptions) (*Logger, error) {
+	level := zapcore.DebugLevel
+	if opts.Level != "" {
+		if err := level.UnmarshalText([]byte(opts.Level)); err != nil {
+			return nil, err
+		}
+	}
+
 	core := zapcore.NewTee(
 		zapcore.NewCore(
 			zapcore.NewJSONEncoder(zapcore.EncoderConfig{
@@ -54,11 +101,11 @@ func NewLogger() *Logger {
 				EncodeCaller: zapcore.FullCallerEncoder,
 			}),
 			zapcore.Lock(os.Stdout),
-			zapcore.DebugLevel,
+			level,
 		),
 	)
 
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1), zap.AddStacktrace(zap.ErrorLevel))
 
-	return &Logger{logger}
+	return &Logger{logger}, nil
 }
@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const Namespace = "bank"
+
+type Metrics struct {
+	registry            *prometheus.Registry
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	accountsCreated     prometheus.Counter
+	transactionsCreated *prometheus.CounterVec
+	transactionsDecline *prometheus.CounterVec
+	queryDuration       *prometheus.HistogramVec
+}
+
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) IncAccountsCreated() {
+	m.accountsCreated.Inc()
+}
+
+func (m *Metrics) IncTransactionsCreated(operationTypeID uint) {
+	m.transactionsCreated.WithLabelValues(strconv.FormatUint(uint64(operationTypeID), 10)).Inc()
+}
+
+func (m *Metrics) IncTransactionsDeclined(reason string) {
+	m.transactionsDecline.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) ObserveQuery(operation, table string, elapsed time.Duration) {
+	m.queryDuration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+}
+
+// RegisterDB exposes the pool stats of db, sql.DB.Stats() is read on every scrape.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		accountsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "accounts_created_total",
+			Help:      "Accounts created.",
+		}),
+		transactionsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "transactions_created_total",
+			Help:      "Transactions created by operation type.",
+		}, []string{"operation_type_id"}),
+		transactionsDecline: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "transactions_declined_total",
+			Help:      "Transactions declined by the rule which declined them.",
+		}, []string{"reason"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "storage_query_duration_seconds",
+			Help:      "Storage query latency by operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.accountsCreated,
+		m.transactionsCreated,
+		m.transactionsDecline,
+		m.queryDuration,
+	)
+
+	return m
+}
@@ -0,0 +1,51 @@
+package prometheus
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+
+	m.ObserveHTTPRequest("POST", "/api/v1/accounts/", 201, 10*time.Millisecond)
+	m.ObserveHTTPRequest("POST", "/api/v1/accounts/", 201, 20*time.Millisecond)
+	m.IncAccountsCreated()
+	m.IncTransactionsCreated(4)
+	m.IncTransactionsDeclined("velocity")
+	m.ObserveQuery("create", "accounts", time.Millisecond)
+
+	suite := []struct {
+		Name     string
+		Got      float64
+		Expected float64
+	}{
+		{"http_requests_total", testutil.ToFloat64(m.httpRequests.WithLabelValues("POST", "/api/v1/accounts/", "201")), 2},
+		{"accounts_created_total", testutil.ToFloat64(m.accountsCreated), 1},
+		{"transactions_created_total", testutil.ToFloat64(m.transactionsCreated.WithLabelValues("4")), 1},
+		{"transactions_declined_total", testutil.ToFloat64(m.transactionsDecline.WithLabelValues("velocity")), 1},
+	}
+
+	for _, s := range suite {
+		if got, expected := s.Got, s.Expected; got != expected {
+			t.Errorf("unexpected %s, got: %v, expected: %v", s.Name, got, expected)
+			return
+		}
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(w.Body)
+	for _, name := range []string{"bank_http_request_duration_seconds_bucket", "bank_storage_query_duration_seconds_count"} {
+		if !strings.Contains(string(body), name) {
+			t.Errorf("expected %s in exposition", name)
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github/guiferpa/bank/domain/fraud"
+)
+
+type RuleSpec struct {
+	ID              string   `json:"id"`
+	Type            string   `json:"type"`
+	Window          string   `json:"window"`
+	MaxTransactions int64    `json:"max_transactions"`
+	OperationTypeID uint     `json:"operation_type_id"`
+	MaxAmount       int64    `json:"max_amount"`
+	DocumentNumbers []string `json:"document_numbers"`
+}
+
+type RulesFile struct {
+	Rules []RuleSpec `json:"rules"`
+}
+
+func Parse(data []byte) ([]fraud.Rule, error) {
+	var file RulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	rules := make([]fraud.Rule, 0, len(file.Rules))
+	for _, spec := range file.Rules {
+		var window time.Duration
+		if spec.Window != "" {
+			w, err := time.ParseDuration(spec.Window)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window for rule %q: %w", spec.ID, err)
+			}
+			window = w
+		}
+
+		rules = append(rules, fraud.Rule{
+			ID:              spec.ID,
+			Type:            fraud.RuleType(spec.Type),
+			Window:          window,
+			MaxTransactions: spec.MaxTransactions,
+			OperationTypeID: spec.OperationTypeID,
+			MaxAmount:       spec.MaxAmount,
+			DocumentNumbers: spec.DocumentNumbers,
+		})
+	}
+
+	return rules, nil
+}
+
+func Load(path string) ([]fraud.Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
@@ -0,0 +1,53 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/fraud"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`{
+		"rules": [
+			{"id": "velocity", "type": "velocity", "window": "1m", "max_transactions": 5},
+			{"id": "withdrawal-daily", "type": "amount_limit", "operation_type_id": 3, "max_amount": 100000},
+			{"id": "blocked", "type": "blocklist", "document_numbers": ["123"]}
+		]
+	}`)
+
+	rules, err := Parse(data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := len(rules), 3; got != expected {
+		t.Errorf("unexpected number of rules, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := rules[0].Window, time.Minute; got != expected {
+		t.Errorf("unexpected window, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := rules[1].Type, fraud.AmountLimitRuleType; got != expected {
+		t.Errorf("unexpected rule type, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := rules[1].Window, time.Duration(0); got != expected {
+		t.Errorf("unexpected window, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestParseWithInvalidWindow(t *testing.T) {
+	data := []byte(`{"rules": [{"id": "velocity", "type": "velocity", "window": "one minute", "max_transactions": 5}]}`)
+
+	if _, err := Parse(data); err == nil {
+		t.Error("unexpected nil error")
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+
+	"github/guiferpa/bank/domain/account"
+)
+
+// Storage reads from the wrapped storage and discards every write, so the domain rules run for real
+// without changing anything. Writes are remembered to catch duplicates within the same run.
+type Storage struct {
+	account.StorageRepository
+
+	mu              sync.Mutex
+	documentNumbers map[string]bool
+	idempotencyKeys map[string]bool
+}
+
+func (s *Storage) CreateAccount(ctx context.Context, opts account.CreateAccountOptions) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.documentNumbers[opts.DocumentNumber] = true
+
+	return 0, nil
+}
+
+func (s *Storage) HasAccountByDocumentNumber(ctx context.Context, documentNumber string) (bool, error) {
+	s.mu.Lock()
+	has := s.documentNumbers[documentNumber]
+	s.mu.Unlock()
+
+	if has {
+		return true, nil
+	}
+
+	return s.StorageRepository.HasAccountByDocumentNumber(ctx, documentNumber)
+}
+
+func (s *Storage) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if opts.IdempotencyKey != "" {
+		if s.idempotencyKeys[opts.IdempotencyKey] {
+			return 0, account.NewInfraError(account.InfraTransactionDuplicatedErrorCode, "transaction already exists")
+		}
+		s.idempotencyKeys[opts.IdempotencyKey] = true
+	}
+
+	return 0, nil
+}
+
+func (s *Storage) CreateTransactions(ctx context.Context, items []account.CreateTransactionOptions) ([]uint, error) {
+	return make([]uint, len(items)), nil
+}
+
+func NewStorage(storage account.StorageRepository) *Storage {
+	return &Storage{StorageRepository: storage, documentNumbers: make(map[string]bool), idempotencyKeys: make(map[string]bool)}
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github/guiferpa/bank/domain/ratelimit"
+)
+
+type bucketEntry struct {
+	bucket ratelimit.Bucket
+	fullAt time.Time
+}
+
+// BucketStorage keeps rate limit buckets in the process memory, it fits a single replica deploy since every
+// replica would let through its own share of requests.
+type BucketStorage struct {
+	mu       sync.Mutex
+	buckets  map[string]bucketEntry
+	purgedAt time.Time
+}
+
+// Take forgets, at most once a minute, the buckets full again, the same as the ones never taken from.
+func (bs *BucketStorage) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if now.Sub(bs.purgedAt) > time.Minute {
+		for k, entry := range bs.buckets {
+			if entry.fullAt.Before(now) {
+				delete(bs.buckets, k)
+			}
+		}
+		bs.purgedAt = now
+	}
+
+	bucket, d := limit.Take(bs.buckets[key].bucket, now)
+	bs.buckets[key] = bucketEntry{bucket, now.Add(d.Reset)}
+
+	return d, nil
+}
+
+func (bs *BucketStorage) Refund(ctx context.Context, key string, limit ratelimit.Limit) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if entry, ok := bs.buckets[key]; ok {
+		entry.bucket = limit.Refund(entry.bucket)
+		bs.buckets[key] = entry
+	}
+
+	return nil
+}
+
+func NewBucketStorage() *BucketStorage {
+	return &BucketStorage{buckets: make(map[string]bucketEntry)}
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github/guiferpa/bank/domain/fraud"
+)
+
+type counterEntry struct {
+	counter   fraud.Counter
+	expiresAt time.Time
+}
+
+// CounterStorage keeps fraud counters in the process memory, it fits a single replica deploy
+// since every replica would have its own counters.
+type CounterStorage struct {
+	mu       sync.Mutex
+	counters map[fraud.CounterKey]counterEntry
+	purgedAt time.Time
+}
+
+// IncrementCounter forgets, at most once a minute, the counters of the windows gone by.
+func (cs *CounterStorage) IncrementCounter(ctx context.Context, key fraud.CounterKey, delta fraud.Counter, expiresAt time.Time) (fraud.Counter, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if now := time.Now(); now.Sub(cs.purgedAt) > time.Minute {
+		for k, entry := range cs.counters {
+			if entry.expiresAt.Before(now) {
+				delete(cs.counters, k)
+			}
+		}
+		cs.purgedAt = now
+	}
+
+	entry := cs.counters[key]
+	entry.counter.Count += delta.Count
+	entry.counter.Amount += delta.Amount
+	entry.expiresAt = expiresAt
+	cs.counters[key] = entry
+
+	return entry.counter, nil
+}
+
+func NewCounterStorage() *CounterStorage {
+	return &CounterStorage{counters: make(map[fraud.CounterKey]counterEntry)}
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type nonceEntry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+// nonceQueue orders the nonces by expiration, the first to expire on top.
+type nonceQueue []nonceEntry
+
+func (q nonceQueue) Len() int            { return len(q) }
+func (q nonceQueue) Less(i, j int) bool  { return q[i].expiresAt.Before(q[j].expiresAt) }
+func (q nonceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nonceQueue) Push(x interface{}) { *q = append(*q, x.(nonceEntry)) }
+
+func (q *nonceQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// NonceStorage keeps the nonces of signed requests in the process memory, it fits a single replica deploy
+// since a request replayed against another replica wouldn't be caught.
+type NonceStorage struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	queue  nonceQueue
+}
+
+// RememberNonce forgets the expired nonces first, popping them from the queue instead of going through them all.
+func (ns *NonceStorage) RememberNonce(ctx context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	now := time.Now()
+	for ns.queue.Len() > 0 && ns.queue[0].expiresAt.Before(now) {
+		delete(ns.nonces, heap.Pop(&ns.queue).(nonceEntry).nonce)
+	}
+
+	if _, ok := ns.nonces[nonce]; ok {
+		return false, nil
+	}
+	ns.nonces[nonce] = expiresAt
+	heap.Push(&ns.queue, nonceEntry{nonce, expiresAt})
+
+	return true, nil
+}
+
+func NewNonceStorage() *NonceStorage {
+	return &NonceStorage{nonces: make(map[string]time.Time)}
+}
@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"github/guiferpa/bank/domain/account"
+
 	"gorm.io/gorm"
 )
 
@@ -8,9 +10,29 @@ type Account struct {
 	gorm.Model
 
 	ID             uint   `gorm:"primaryKey;autoIncrement"`
-	DocumentNumber string `gorm:"index;unique"`
+	CustomerID     uint   `gorm:"index"`
+	DocumentNumber string `gorm:"index"`
+	ClosingDay     int    `gorm:"not null;default:1"`
+	DueDay         int    `gorm:"not null;default:10"`
+	TimeZone       string `gorm:"size:64;not null;default:UTC"`
+	Tenant         string `gorm:"size:64;index"`
+	// Legacy tells the accounts opened without a customer, only one of them is allowed per holder.
+	Legacy bool `gorm:"not null;default:false"`
 }
 
 func (a *Account) TableName() string {
 	return "accounts"
 }
+
+func toDomainAccount(model Account) account.Account {
+	return account.Account{
+		ID:             model.ID,
+		CustomerID:     model.CustomerID,
+		DocumentNumber: model.DocumentNumber,
+		ClosingDay:     model.ClosingDay,
+		DueDay:         model.DueDay,
+		TimeZone:       model.TimeZone,
+		Tenant:         model.Tenant,
+		CreatedAt:      model.CreatedAt,
+	}
+}
@@ -1,11 +1,59 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"github/guiferpa/bank/domain/account"
+
 	"gorm.io/gorm"
 )
 
+type Merchant struct {
+	Name       string `json:"name,omitempty"`
+	MCC        string `json:"mcc,omitempty"`
+	City       string `json:"city,omitempty"`
+	Country    string `json:"country,omitempty"`
+	TerminalID string `json:"terminal_id,omitempty"`
+}
+
+func (m Merchant) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *Merchant) Scan(value interface{}) error {
+	return scanJSON(value, m)
+}
+
+type Metadata map[string]string
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(m)
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	return scanJSON(value, m)
+}
+
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+
+	return fmt.Errorf("unsupported type %T for jsonb column", value)
+}
+
 type AccountTransaction struct {
 	gorm.Model
 
@@ -14,6 +62,10 @@ type AccountTransaction struct {
 	OperationTypeID uint
 	Amount          int64
 	EventDate       time.Time
+	EventDateOffset int
+	IdempotencyKey  *string   `gorm:"size:128;uniqueIndex"`
+	Merchant        *Merchant `gorm:"type:jsonb"`
+	Metadata        Metadata  `gorm:"type:jsonb"`
 
 	Account       Account       `gorm:"foreignKey:AccountID"`
 	OperationType OperationType `gorm:"foreignKey:OperationTypeID"`
@@ -22,3 +74,49 @@ type AccountTransaction struct {
 func (at *AccountTransaction) TableName() string {
 	return "transactions"
 }
+
+const TransactionsBatchSize = 500
+
+func toTransactionModel(opts account.CreateTransactionOptions) *AccountTransaction {
+	model := &AccountTransaction{
+		AccountID:       opts.AccountID,
+		OperationTypeID: opts.OperationTypeID,
+		Amount:          opts.Amount,
+		EventDate:       opts.EventDate.UTC(),
+		Merchant:        toMerchantModel(opts.Merchant),
+		Metadata:        opts.Metadata,
+	}
+	_, model.EventDateOffset = opts.EventDate.Zone()
+
+	if opts.IdempotencyKey != "" {
+		model.IdempotencyKey = &opts.IdempotencyKey
+	}
+
+	return model
+}
+
+func toMerchantModel(m *account.Merchant) *Merchant {
+	if m == nil {
+		return nil
+	}
+
+	return &Merchant{Name: m.Name, MCC: m.MCC, City: m.City, Country: m.Country, TerminalID: m.TerminalID}
+}
+
+// Event dates are stored in UTC along with the offset, in seconds east of UTC, they were informed with.
+func toDomainTransaction(model AccountTransaction) account.Transaction {
+	tx := account.Transaction{
+		ID:              model.ID,
+		AccountID:       model.AccountID,
+		OperationTypeID: model.OperationTypeID,
+		Amount:          model.Amount,
+		EventDate:       model.EventDate.In(time.FixedZone("", model.EventDateOffset)),
+		Metadata:        model.Metadata,
+	}
+
+	if m := model.Merchant; m != nil {
+		tx.Merchant = &account.Merchant{Name: m.Name, MCC: m.MCC, City: m.City, Country: m.Country, TerminalID: m.TerminalID}
+	}
+
+	return tx
+}
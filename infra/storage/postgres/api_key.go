@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"strings"
+	"time"
+
+	"github/guiferpa/bank/domain/auth"
+
+	"gorm.io/gorm"
+)
+
+type APIKey struct {
+	gorm.Model
+
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	Name          string `gorm:"size:255;not null"`
+	Prefix        string `gorm:"size:32;uniqueIndex;not null"`
+	Hash          string `gorm:"size:64;not null"`
+	SigningSecret string `gorm:"size:64"`
+	Scopes        string `gorm:"size:255;not null;default:''"`
+	Tenant        string `gorm:"size:64"`
+	RevokedAt     *time.Time
+}
+
+func (k *APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Scopes are kept comma separated, keys made before scopes existed have none.
+func joinScopes(scopes []auth.Scope) string {
+	ss := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		ss = append(ss, string(scope))
+	}
+
+	return strings.Join(ss, ",")
+}
+
+func splitScopes(s string) []auth.Scope {
+	scopes := make([]auth.Scope, 0)
+	for _, scope := range strings.Split(s, ",") {
+		if scope != "" {
+			scopes = append(scopes, auth.Scope(scope))
+		}
+	}
+
+	return scopes
+}
+
+func toDomainAPIKey(model APIKey) auth.APIKey {
+	key := auth.APIKey{
+		ID:            model.ID,
+		Name:          model.Name,
+		Prefix:        model.Prefix,
+		Hash:          model.Hash,
+		SigningSecret: model.SigningSecret,
+		Scopes:        splitScopes(model.Scopes),
+		Tenant:        model.Tenant,
+		CreatedAt:     model.CreatedAt,
+	}
+	if model.RevokedAt != nil {
+		key.RevokedAt = *model.RevokedAt
+	}
+
+	return key
+}
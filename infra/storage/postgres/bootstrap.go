@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github/guiferpa/bank/domain/health"
+	"github/guiferpa/bank/domain/log"
+)
+
+const (
+	DefaultBootstrapInitialBackoff = 500 * time.Millisecond
+	DefaultBootstrapMaxBackoff     = 5 * time.Second
+)
+
+type BootstrapState string
+
+const (
+	ConnectingBootstrapState BootstrapState = "connecting"
+	ConnectedBootstrapState  BootstrapState = "connected"
+	FailedBootstrapState     BootstrapState = "failed"
+)
+
+type BootstrapOptions struct {
+	// Timeout is how long Connect keeps trying, zero means a single attempt.
+	Timeout time.Duration
+	// The wait between attempts doubles from InitialBackoff up to MaxBackoff, each one randomized
+	// between half and the whole of it so instances starting together don't retry in lockstep.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Logger         log.LoggerRepository
+}
+
+// Bootstrapper connects to the database retrying with exponential backoff, so the API can start before Postgres.
+// It's a health.HealthChecker reporting how the connection is going.
+type Bootstrapper struct {
+	opts BootstrapOptions
+
+	mu       sync.Mutex
+	state    BootstrapState
+	attempts int
+	err      error
+}
+
+func (b *Bootstrapper) set(state BootstrapState, attempts int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state, b.attempts, b.err = state, attempts, err
+}
+
+func (b *Bootstrapper) State() BootstrapState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Bootstrapper) HealthChecks(ctx context.Context) []health.Check {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	check := health.Check{Name: "database_connection", Status: health.UpStatus}
+	if b.state != ConnectedBootstrapState {
+		check.Status = health.DownStatus
+		check.Error = fmt.Sprintf("%s after %d attempt(s)", b.state, b.attempts)
+		if b.err != nil {
+			check.Error = fmt.Sprintf("%s: %s", check.Error, b.err)
+		}
+	}
+
+	return []health.Check{check}
+}
+
+// backoff is the wait after the attempt-th failed attempt.
+func (b *Bootstrapper) backoff(attempt int) time.Duration {
+	d := b.opts.InitialBackoff
+	for i := 1; i < attempt && d < b.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > b.opts.MaxBackoff {
+		d = b.opts.MaxBackoff
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (b *Bootstrapper) fail(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state, b.err = FailedBootstrapState, err
+}
+
+func (b *Bootstrapper) wait(ctx context.Context, db *sql.DB) error {
+	if b.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.opts.Timeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			b.set(ConnectedBootstrapState, attempt, nil)
+			if b.opts.Logger != nil {
+				b.opts.Logger.Info(ctx, fmt.Sprintf("database connected after %d attempt(s)", attempt))
+			}
+			return nil
+		}
+
+		delay := b.backoff(attempt)
+		deadline, ok := ctx.Deadline()
+		if b.opts.Timeout <= 0 || ctx.Err() != nil || (ok && time.Until(deadline) < delay) {
+			err = fmt.Errorf("database unreachable after %d attempt(s): %w", attempt, err)
+			b.set(FailedBootstrapState, attempt, err)
+			return err
+		}
+
+		b.set(ConnectingBootstrapState, attempt, err)
+		if b.opts.Logger != nil {
+			b.opts.Logger.Warn(ctx, fmt.Sprintf("database connection attempt %d failed, retrying in %s: %s", attempt, delay.Round(time.Millisecond), err))
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = fmt.Errorf("database unreachable after %d attempt(s): %w", attempt, ctx.Err())
+			b.set(FailedBootstrapState, attempt, err)
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Connect opens the pool, waits for the database and then sets the storage up as NewStorage does.
+func (b *Bootstrapper) Connect(ctx context.Context, opts NewStorageOptions) (*PostgresStorage, error) {
+	db, exr, err := open(opts)
+	if err != nil {
+		b.fail(err)
+		return nil, err
+	}
+
+	if err := b.wait(ctx, exr); err != nil {
+		exr.Close()
+		return nil, err
+	}
+
+	ps, err := setup(db, opts)
+	if err != nil {
+		exr.Close()
+		b.fail(err)
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+func NewBootstrapper(opts BootstrapOptions) *Bootstrapper {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultBootstrapInitialBackoff
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = DefaultBootstrapMaxBackoff
+		if opts.MaxBackoff < opts.InitialBackoff {
+			opts.MaxBackoff = opts.InitialBackoff
+		}
+	}
+
+	return &Bootstrapper{opts: opts, state: ConnectingBootstrapState}
+}
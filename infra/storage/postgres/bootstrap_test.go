@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github/guiferpa/bank/domain/health"
+	infralog "github/guiferpa/bank/infra/logger/log"
+)
+
+func TestBootstrapperBackoff(t *testing.T) {
+	b := NewBootstrapper(BootstrapOptions{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+
+	suite := []struct {
+		Attempt int
+		Ceil    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+
+	for _, s := range suite {
+		for i := 0; i < 100; i++ {
+			got := b.backoff(s.Attempt)
+			if got < s.Ceil/2 || got > s.Ceil {
+				t.Errorf("unexpected backoff for attempt %d, got: %v, expected between %v and %v", s.Attempt, got, s.Ceil/2, s.Ceil)
+				return
+			}
+		}
+	}
+}
+
+func TestBootstrapperGivesUpAtDeadline(t *testing.T) {
+	b := NewBootstrapper(BootstrapOptions{
+		Timeout:        300 * time.Millisecond,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	})
+
+	if got, expected := b.HealthChecks(context.Background())[0].Status, health.DownStatus; got != expected {
+		t.Errorf("unexpected status before connecting, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	started := time.Now()
+	_, err := b.Connect(context.Background(), NewStorageOptions{Host: "127.0.0.1", Port: "1", User: "postgres", DatabaseName: "bank"})
+	if err == nil {
+		t.Error("unexpected nil error connecting to a closed port")
+		return
+	}
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("unexpected time to give up, got: %v, expected at most the timeout", elapsed)
+		return
+	}
+
+	if got, expected := b.State(), FailedBootstrapState; got != expected {
+		t.Errorf("unexpected state, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	check := b.HealthChecks(context.Background())[0]
+	if got, expected := check.Status, health.DownStatus; got != expected {
+		t.Errorf("unexpected status, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if strings.HasPrefix(check.Error, "failed after 1 attempt(s)") {
+		t.Errorf("unexpected single attempt, got: %v", check.Error)
+		return
+	}
+}
+
+func TestBootstrapperStopsOnCancel(t *testing.T) {
+	b := NewBootstrapper(BootstrapOptions{Timeout: time.Minute, InitialBackoff: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := b.Connect(ctx, NewStorageOptions{Host: "127.0.0.1", Port: "1", User: "postgres", DatabaseName: "bank"})
+	if got, expected := errors.Is(err, context.Canceled), true; got != expected {
+		t.Errorf("unexpected error, got: %v, expected the context's", err)
+		return
+	}
+}
+
+func TestBootstrapperLogsRetriesWithoutLoggerContext(t *testing.T) {
+	logger, err := infralog.NewLogger(infralog.NewLoggerOptions{Level: "error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewBootstrapper(BootstrapOptions{
+		Timeout:        100 * time.Millisecond,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		Logger:         logger,
+	})
+
+	if _, err := b.Connect(context.Background(), NewStorageOptions{Host: "127.0.0.1", Port: "1", User: "postgres", DatabaseName: "bank"}); err == nil {
+		t.Error("unexpected nil error connecting to a closed port")
+		return
+	}
+
+	if check := b.HealthChecks(context.Background())[0]; strings.HasPrefix(check.Error, "failed after 1 attempt(s)") {
+		t.Errorf("unexpected single attempt, no retry was logged: %v", check.Error)
+		return
+	}
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CustomerAddress struct {
+	Street       string `gorm:"size:256"`
+	Number       string `gorm:"size:32"`
+	Complement   string `gorm:"size:128"`
+	Neighborhood string `gorm:"size:128"`
+	City         string `gorm:"size:128"`
+	State        string `gorm:"size:64"`
+	ZipCode      string `gorm:"size:16"`
+	Country      string `gorm:"size:64"`
+}
+
+type Customer struct {
+	gorm.Model
+
+	ID             uint            `gorm:"primaryKey;autoIncrement"`
+	DocumentNumber string          `gorm:"index;unique"`
+	Name           string          `gorm:"size:256"`
+	BirthDate      *time.Time      `gorm:"type:date"`
+	Address        CustomerAddress `gorm:"embedded;embeddedPrefix:address_"`
+	Tenant         string          `gorm:"size:64;index"`
+
+	Accounts []Account `gorm:"foreignKey:CustomerID"`
+}
+
+func (c *Customer) TableName() string {
+	return "customers"
+}
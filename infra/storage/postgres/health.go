@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github/guiferpa/bank/domain/health"
+
+	"gorm.io/gorm"
+)
+
+func newCheck(name string, started time.Time, err error) health.Check {
+	check := health.Check{Name: name, Status: health.UpStatus, Latency: time.Since(started)}
+	if err != nil {
+		check.Status, check.Error = health.DownStatus, err.Error()
+	}
+
+	return check
+}
+
+// pendingMigration finds the first table or column of the models missing in the database, with a single
+// query so it's cheap enough for readiness probes.
+func (ps *PostgresStorage) pendingMigration(ctx context.Context) error {
+	rows := make([]struct {
+		TableName  string
+		ColumnName string
+	}, 0)
+	if err := ps.db.WithContext(ctx).Raw("SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA()").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	columns := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		columns[row.TableName+"."+row.ColumnName] = true
+	}
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: ps.db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+
+			if !columns[stmt.Schema.Table+"."+field.DBName] {
+				return fmt.Errorf("missing column %s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ps *PostgresStorage) HealthChecks(ctx context.Context) []health.Check {
+	started := time.Now()
+	db, err := ps.db.DB()
+	if err == nil {
+		err = db.PingContext(ctx)
+	}
+	checks := []health.Check{newCheck("database", started, err)}
+
+	started = time.Now()
+	checks = append(checks, newCheck("migrations", started, ps.pendingMigration(ctx)))
+
+	return checks
+}
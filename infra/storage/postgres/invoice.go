@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"time"
+
+	"github/guiferpa/bank/domain/billing"
+
+	"gorm.io/gorm"
+)
+
+type Invoice struct {
+	gorm.Model
+
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	AccountID   uint      `gorm:"uniqueIndex:idx_invoices_account_period"`
+	PeriodStart time.Time `gorm:"uniqueIndex:idx_invoices_account_period"`
+	PeriodEnd   time.Time
+	DueDate     time.Time
+	Status      string `gorm:"size:16;index"`
+	Total       int64
+	PaidAmount  int64
+	ClosedAt    *time.Time
+	PaidAt      *time.Time
+
+	Account Account        `gorm:"foreignKey:AccountID"`
+	Totals  []InvoiceTotal `gorm:"foreignKey:InvoiceID"`
+}
+
+func (i *Invoice) TableName() string {
+	return "invoices"
+}
+
+type InvoiceTotal struct {
+	InvoiceID       uint `gorm:"primaryKey"`
+	OperationTypeID uint `gorm:"primaryKey"`
+	Amount          int64
+
+	OperationType OperationType `gorm:"foreignKey:OperationTypeID"`
+}
+
+func (it *InvoiceTotal) TableName() string {
+	return "invoice_totals"
+}
+
+func toDomainInvoice(model Invoice) billing.Invoice {
+	totals := make([]billing.InvoiceTotal, 0, len(model.Totals))
+	for _, t := range model.Totals {
+		totals = append(totals, billing.InvoiceTotal{OperationTypeID: t.OperationTypeID, Amount: t.Amount})
+	}
+
+	return billing.Invoice{
+		ID:          model.ID,
+		AccountID:   model.AccountID,
+		PeriodStart: model.PeriodStart.UTC(),
+		PeriodEnd:   model.PeriodEnd.UTC(),
+		DueDate:     model.DueDate.UTC(),
+		Status:      billing.Status(model.Status),
+		Totals:      totals,
+		Total:       model.Total,
+		PaidAmount:  model.PaidAmount,
+		ClosedAt:    model.ClosedAt,
+		PaidAt:      model.PaidAt,
+	}
+}
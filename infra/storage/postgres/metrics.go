@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"time"
+
+	"github/guiferpa/bank/domain/metrics"
+
+	"gorm.io/gorm"
+)
+
+const queryStartedAtKey = "metrics:query_started_at"
+
+// registerMetrics times every statement gorm runs, labeled by the kind of operation and its table.
+func registerMetrics(db *gorm.DB, m metrics.MetricsRepository) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(queryStartedAtKey, time.Now())
+	}
+
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			value, ok := tx.InstanceGet(queryStartedAtKey)
+			if !ok {
+				return
+			}
+
+			table := tx.Statement.Table
+			if table == "" {
+				table = "unknown"
+			}
+
+			m.ObserveQuery(operation, table, time.Since(value.(time.Time)))
+		}
+	}
+
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("metrics:before_create", before); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("metrics:after_create", after("create")); err != nil {
+		return err
+	}
+
+	if err := cb.Query().Before("gorm:query").Register("metrics:before_query", before); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("metrics:after_query", after("query")); err != nil {
+		return err
+	}
+
+	if err := cb.Update().Before("gorm:update").Register("metrics:before_update", before); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("metrics:after_update", after("update")); err != nil {
+		return err
+	}
+
+	if err := cb.Delete().Before("gorm:delete").Register("metrics:before_delete", before); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")); err != nil {
+		return err
+	}
+
+	if err := cb.Row().Before("gorm:row").Register("metrics:before_row", before); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("metrics:after_row", after("row")); err != nil {
+		return err
+	}
+
+	if err := cb.Raw().Before("gorm:raw").Register("metrics:before_raw", before); err != nil {
+		return err
+	}
+
+	return cb.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw"))
+}
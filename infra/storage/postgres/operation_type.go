@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"github/guiferpa/bank/domain/account"
+
 	"gorm.io/gorm"
 )
 
@@ -16,8 +18,11 @@ func (ot *OperationType) TableName() string {
 }
 
 var OperationTypeSeedData = []OperationType{
-	{ID: 1, Description: "COMPRA A VISTA"},
-	{ID: 2, Description: "COMPRA PARCELADA"},
-	{ID: 3, Description: "SAQUE"},
-	{ID: 4, Description: "PAGAMENTO"},
+	{ID: account.CashPurchaseOperationTypeID, Description: "COMPRA A VISTA"},
+	{ID: account.InstallmentPurchaseOperationTypeID, Description: "COMPRA PARCELADA"},
+	{ID: account.WithdrawalOperationTypeID, Description: "SAQUE"},
+	{ID: account.PaymentOperationTypeID, Description: "PAGAMENTO"},
+	{ID: account.InterestOperationTypeID, Description: "JUROS ROTATIVO"},
+	{ID: account.LateFeeOperationTypeID, Description: "MULTA POR ATRASO"},
+	{ID: account.IOFOperationTypeID, Description: "IOF"},
 }
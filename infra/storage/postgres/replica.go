@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github/guiferpa/bank/domain/log"
+
+	"gorm.io/gorm"
+)
+
+const DefaultReplicaCheckInterval = 5 * time.Second
+
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy int32
+}
+
+// replicaSet hands out its healthy replicas in turns, a replica failing the periodic ping is left out
+// until it answers again.
+type replicaSet struct {
+	replicas []*replica
+	turn     uint32
+	logger   log.LoggerRepository
+	stop     context.CancelFunc
+	done     sync.WaitGroup
+}
+
+// next is nil when no replica is healthy, the primary takes the read then.
+func (rs *replicaSet) next() *gorm.DB {
+	healthy := make([]*gorm.DB, 0, len(rs.replicas))
+	for _, r := range rs.replicas {
+		if atomic.LoadInt32(&r.healthy) == 1 {
+			healthy = append(healthy, r.db)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	return healthy[atomic.AddUint32(&rs.turn, 1)%uint32(len(healthy))]
+}
+
+func (rs *replicaSet) check(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, r := range rs.replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+
+			pctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			var healthy int32
+			db, err := r.db.DB()
+			if err == nil {
+				err = db.PingContext(pctx)
+			}
+			if err == nil {
+				healthy = 1
+			}
+
+			if previous := atomic.SwapInt32(&r.healthy, healthy); previous != healthy && rs.logger != nil {
+				if err != nil {
+					rs.logger.Warn(ctx, fmt.Sprintf("%s is down, reads go to the others or the primary: %s", r.name, err))
+				} else {
+					rs.logger.Info(ctx, fmt.Sprintf("%s is up", r.name))
+				}
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+func (rs *replicaSet) watch(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rs.stop = cancel
+
+	rs.done.Add(1)
+	go func() {
+		defer rs.done.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rs.check(ctx, interval)
+			}
+		}
+	}()
+}
+
+func (rs *replicaSet) close() error {
+	if rs.stop != nil {
+		rs.stop()
+	}
+	rs.done.Wait()
+
+	var first error
+	for _, r := range rs.replicas {
+		db, err := r.db.DB()
+		if err == nil {
+			err = db.Close()
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github/guiferpa/bank/domain/consistency"
+	infralog "github/guiferpa/bank/infra/logger/log"
+
+	"gorm.io/gorm"
+)
+
+func openUnreachable(t *testing.T, name string) *gorm.DB {
+	db, _, err := open(NewStorageOptions{DSN: fmt.Sprintf("postgres://postgres@127.0.0.1:1/%s", name)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestReaderRoutesToHealthyReplicas(t *testing.T) {
+	primary := openUnreachable(t, "primary")
+	first, second, third := openUnreachable(t, "first"), openUnreachable(t, "second"), openUnreachable(t, "third")
+
+	rs := &replicaSet{replicas: []*replica{
+		{name: "replica 1", db: first, healthy: 1},
+		{name: "replica 2", db: second, healthy: 0},
+		{name: "replica 3", db: third, healthy: 1},
+	}}
+	ps := &PostgresStorage{db: primary, replicas: rs}
+
+	seen := make(map[gorm.ConnPool]int)
+	for i := 0; i < 10; i++ {
+		seen[ps.reader(context.Background()).Statement.ConnPool]++
+	}
+
+	if got, expected := seen[first.ConnPool], 5; got != expected {
+		t.Errorf("unexpected reads on replica 1, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := seen[third.ConnPool], 5; got != expected {
+		t.Errorf("unexpected reads on replica 3, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	suite := []struct {
+		Name     string
+		Ctx      context.Context
+		Healthy  int32
+		Expected gorm.ConnPool
+	}{
+		{"Primary asked by the context", consistency.WithPrimary(context.Background()), 1, primary.ConnPool},
+		{"Primary when every replica is down", context.Background(), 0, primary.ConnPool},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			for _, r := range rs.replicas {
+				r.healthy = s.Healthy
+			}
+
+			if got, expected := ps.reader(s.Ctx).Statement.ConnPool, s.Expected; got != expected {
+				t.Errorf("unexpected pool, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestReaderPinnedToOneReplica(t *testing.T) {
+	first, second := openUnreachable(t, "first"), openUnreachable(t, "second")
+
+	rs := &replicaSet{replicas: []*replica{
+		{name: "replica 1", db: first, healthy: 1},
+		{name: "replica 2", db: second, healthy: 1},
+	}}
+	ps := &PostgresStorage{db: openUnreachable(t, "primary"), replicas: rs}
+
+	ctx := consistency.WithPin(context.Background())
+	pinned := ps.reader(ctx).Statement.ConnPool
+	for i := 0; i < 3; i++ {
+		if got, expected := ps.reader(ctx).Statement.ConnPool, pinned; got != expected {
+			t.Errorf("unexpected pool at read %d, got: %v, expected: %v", i, got, expected)
+			return
+		}
+	}
+
+	if got, expected := ps.reader(context.Background()).Statement.ConnPool, pinned; got == expected {
+		t.Errorf("unexpected pool out of the pinned context, got the pinned one: %v", got)
+		return
+	}
+}
+
+func TestReplicaSetCheckLeavesDownReplicasOut(t *testing.T) {
+	logger, err := infralog.NewLogger(infralog.NewLoggerOptions{Level: "error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rs := &replicaSet{replicas: []*replica{{name: "replica 1", db: openUnreachable(t, "replica"), healthy: 1}}, logger: logger}
+
+	rs.check(context.Background(), DefaultReplicaCheckInterval)
+
+	if got := rs.next(); got != nil {
+		t.Errorf("unexpected replica picked, got: %v, expected none", got)
+		return
+	}
+}
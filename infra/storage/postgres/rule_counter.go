@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"time"
+)
+
+type RuleCounter struct {
+	Key         string    `gorm:"primaryKey;size:255"`
+	WindowStart time.Time `gorm:"primaryKey"`
+	Count       int64
+	Amount      int64
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+func (rc *RuleCounter) TableName() string {
+	return "rule_counters"
+}
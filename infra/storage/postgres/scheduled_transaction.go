@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"time"
+
+	"github/guiferpa/bank/domain/schedule"
+
+	"gorm.io/gorm"
+)
+
+type ScheduledTransaction struct {
+	gorm.Model
+
+	ID                  uint `gorm:"primaryKey;autoIncrement"`
+	AccountID           uint `gorm:"index"`
+	OperationTypeID     uint
+	Amount              int64
+	StartAt             time.Time
+	RecurrenceFrequency string `gorm:"size:16"`
+	RecurrenceInterval  int
+	EndAt               *time.Time
+	MaxOccurrences      int
+	Occurrences         int
+	NextRunAt           *time.Time `gorm:"index"`
+	Status              string     `gorm:"size:16;index"`
+	Version             int
+
+	Account       Account       `gorm:"foreignKey:AccountID"`
+	OperationType OperationType `gorm:"foreignKey:OperationTypeID"`
+}
+
+func (st *ScheduledTransaction) TableName() string {
+	return "scheduled_transactions"
+}
+
+func toDomainScheduledTransaction(model ScheduledTransaction) schedule.ScheduledTransaction {
+	st := schedule.ScheduledTransaction{
+		ID:              model.ID,
+		AccountID:       model.AccountID,
+		OperationTypeID: model.OperationTypeID,
+		Amount:          model.Amount,
+		StartAt:         model.StartAt.UTC(),
+		EndAt:           model.EndAt,
+		MaxOccurrences:  model.MaxOccurrences,
+		Occurrences:     model.Occurrences,
+		NextRunAt:       model.NextRunAt,
+		Status:          schedule.Status(model.Status),
+		Version:         model.Version,
+		CreatedAt:       model.CreatedAt,
+	}
+
+	if model.RecurrenceFrequency != "" {
+		st.Recurrence = &schedule.Recurrence{
+			Frequency: schedule.Frequency(model.RecurrenceFrequency),
+			Interval:  model.RecurrenceInterval,
+		}
+	}
+
+	return st
+}
@@ -1,11 +1,27 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/billing"
+	"github/guiferpa/bank/domain/consistency"
+	"github/guiferpa/bank/domain/customer"
+	"github/guiferpa/bank/domain/fraud"
 	"github/guiferpa/bank/domain/log"
+	"github/guiferpa/bank/domain/metrics"
+	"github/guiferpa/bank/domain/schedule"
+	"github/guiferpa/bank/domain/statement"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/trace"
 	driver "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -13,22 +29,59 @@ import (
 )
 
 type PostgresStorage struct {
-	db     *gorm.DB
-	logger log.LoggerRepository
+	db       *gorm.DB
+	logger   log.LoggerRepository
+	replicas *replicaSet
 }
 
-func (ps *PostgresStorage) CreateAccount(opts account.CreateAccountOptions) (uint, error) {
-	model := &Account{DocumentNumber: opts.DocumentNumber}
-	if err := ps.db.Create(model).Error; err != nil {
+// reader is where read-only queries go, a healthy replica unless there's none or ctx must read from the primary.
+func (ps *PostgresStorage) reader(ctx context.Context) *gorm.DB {
+	if ps.replicas != nil && !consistency.Primary(ctx) {
+		next := func() interface{} { return ps.replicas.next() }
+		if db, _ := consistency.Pinned(ctx, next).(*gorm.DB); db != nil {
+			return db.WithContext(ctx)
+		}
+	}
+
+	return ps.db.WithContext(ctx)
+}
+
+func (ps *PostgresStorage) CreateAccount(ctx context.Context, opts account.CreateAccountOptions) (uint, error) {
+	model := &Account{
+		CustomerID:     opts.CustomerID,
+		DocumentNumber: opts.DocumentNumber,
+		ClosingDay:     opts.ClosingDay,
+		DueDay:         opts.DueDay,
+		TimeZone:       opts.TimeZone,
+		Tenant:         opts.Tenant,
+		Legacy:         opts.CustomerID == 0,
+	}
+	err := ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if model.CustomerID == 0 {
+			holder := Customer{DocumentNumber: opts.DocumentNumber, Tenant: opts.Tenant}
+			if err := tx.Where(&Customer{DocumentNumber: opts.DocumentNumber}).FirstOrCreate(&holder).Error; err != nil {
+				return err
+			}
+			model.CustomerID = holder.ID
+		}
+
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		var perr *pgconn.PgError
+		if errors.As(err, &perr) && perr.ConstraintName == legacyAccountsIndex {
+			return 0, account.NewDomainError(account.DomainAccountAlreadyExistsErrorCode, "account already exists")
+		}
+
 		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
 	}
 
 	return model.ID, nil
 }
 
-func (ps *PostgresStorage) GetAccountByID(accountID uint) (account.Account, error) {
+func (ps *PostgresStorage) GetAccountByID(ctx context.Context, accountID uint) (account.Account, error) {
 	var dest Account
-	if err := ps.db.Select("*").Where("id = ?", accountID).First(&dest).Error; err != nil {
+	if err := ps.reader(ctx).Select("*").Where("id = ?", accountID).First(&dest).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return account.Account{}, account.NewInfraError(account.InfraAccountNotFoundErrorCode, "account not found")
 		}
@@ -36,44 +89,616 @@ func (ps *PostgresStorage) GetAccountByID(accountID uint) (account.Account, erro
 		return account.Account{}, account.NewInfraError(account.InfraUnknownError, err.Error())
 	}
 
-	return account.Account{
-		ID:             dest.ID,
-		DocumentNumber: dest.DocumentNumber,
-	}, nil
+	return toDomainAccount(dest), nil
+}
+
+func (ps *PostgresStorage) ListAccounts(ctx context.Context) ([]account.Account, error) {
+	dest := make([]Account, 0)
+	if err := ps.reader(ctx).Select("*").Order("id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	accs := make([]account.Account, 0, len(dest))
+	for _, acc := range dest {
+		accs = append(accs, toDomainAccount(acc))
+	}
+
+	return accs, nil
 }
 
-func (ps *PostgresStorage) HasAccountByDocumentNumber(documentNumber string) (bool, error) {
+func (ps *PostgresStorage) HasAccountByDocumentNumber(ctx context.Context, documentNumber string) (bool, error) {
 	var dest int64
-	if err := ps.db.Model(&Account{}).Select("*").Where("document_number = ?", documentNumber).Count(&dest).Error; err != nil {
+	if err := ps.db.WithContext(ctx).Model(&Account{}).Select("*").Where("document_number = ?", documentNumber).Count(&dest).Error; err != nil {
 		return false, account.NewInfraError(account.InfraUnknownError, err.Error())
 	}
 
 	return dest > 0, nil
 }
 
-func (ps *PostgresStorage) HasOperationTypeByID(operationTypeID uint) (bool, error) {
+func (ps *PostgresStorage) HasOperationTypeByID(ctx context.Context, operationTypeID uint) (bool, error) {
 	var dest int64
-	if err := ps.db.Model(&OperationType{}).Select("*").Where("id = ?", operationTypeID).Count(&dest).Error; err != nil {
+	if err := ps.db.WithContext(ctx).Model(&OperationType{}).Select("*").Where("id = ?", operationTypeID).Count(&dest).Error; err != nil {
 		return false, account.NewInfraError(account.InfraUnknownError, err.Error())
 	}
 
 	return dest > 0, nil
 }
 
-func (ps *PostgresStorage) CreateTransaction(opts account.CreateTransactionOptions) (uint, error) {
-	model := &AccountTransaction{
-		AccountID:       opts.AccountID,
-		OperationTypeID: opts.OperationTypeID,
-		Amount:          opts.Amount,
-		EventDate:       opts.EventDate,
+func (ps *PostgresStorage) CreateTransaction(ctx context.Context, opts account.CreateTransactionOptions) (uint, error) {
+	model := toTransactionModel(opts)
+	if opts.IdempotencyKey == "" {
+		if err := ps.db.WithContext(ctx).Create(&model).Error; err != nil {
+			return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
+		}
+
+		return model.ID, nil
+	}
+
+	result := ps.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(&model)
+	if err := result.Error; err != nil {
+		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return 0, account.NewInfraError(account.InfraTransactionDuplicatedErrorCode, "transaction already exists")
+	}
+
+	return model.ID, nil
+}
+
+// CreateTransactions stores every transaction or none of them.
+func (ps *PostgresStorage) CreateTransactions(ctx context.Context, items []account.CreateTransactionOptions) ([]uint, error) {
+	models := make([]*AccountTransaction, 0, len(items))
+	for _, opts := range items {
+		models = append(models, toTransactionModel(opts))
+	}
+
+	if err := ps.db.WithContext(ctx).CreateInBatches(models, TransactionsBatchSize).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	ids := make([]uint, 0, len(models))
+	for _, model := range models {
+		ids = append(ids, model.ID)
+	}
+
+	return ids, nil
+}
+
+func (ps *PostgresStorage) GetTransactionByID(ctx context.Context, transactionID uint) (account.Transaction, error) {
+	var dest AccountTransaction
+	if err := ps.reader(ctx).Select("*").Where("id = ?", transactionID).First(&dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return account.Transaction{}, account.NewInfraError(account.InfraTransactionNotFoundErrorCode, "transaction not found")
+		}
+
+		return account.Transaction{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return toDomainTransaction(dest), nil
+}
+
+func (ps *PostgresStorage) ListTransactions(ctx context.Context, opts account.ListTransactionsOptions) ([]account.Transaction, error) {
+	query := ps.reader(ctx).Select("*").Where("account_id = ?", opts.AccountID)
+	if opts.MCC != "" {
+		query = query.Where("merchant->>'mcc' = ?", opts.MCC)
+	}
+	if !opts.From.IsZero() {
+		query = query.Where("event_date >= ?", opts.From)
+	}
+	if !opts.To.IsZero() {
+		query = query.Where("event_date < ?", opts.To)
+	}
+
+	dest := make([]AccountTransaction, 0)
+	if err := query.Order("event_date, id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	txs := make([]account.Transaction, 0, len(dest))
+	for _, tx := range dest {
+		txs = append(txs, toDomainTransaction(tx))
+	}
+
+	return txs, nil
+}
+
+func (ps *PostgresStorage) ListTransactionsByAccountID(ctx context.Context, accountID uint, from, to time.Time) ([]account.Transaction, error) {
+	dest := make([]AccountTransaction, 0)
+	if err := ps.reader(ctx).Select("*").
+		Where("account_id = ? AND event_date >= ? AND event_date < ?", accountID, from, to).
+		Order("event_date, id").
+		Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	txs := make([]account.Transaction, 0, len(dest))
+	for _, tx := range dest {
+		txs = append(txs, toDomainTransaction(tx))
+	}
+
+	return txs, nil
+}
+
+func toCustomerModel(opts customer.CreateCustomerOptions) *Customer {
+	model := &Customer{
+		DocumentNumber: opts.DocumentNumber,
+		Name:           opts.Name,
+		Address: CustomerAddress{
+			Street:       opts.Address.Street,
+			Number:       opts.Address.Number,
+			Complement:   opts.Address.Complement,
+			Neighborhood: opts.Address.Neighborhood,
+			City:         opts.Address.City,
+			State:        opts.Address.State,
+			ZipCode:      opts.Address.ZipCode,
+			Country:      opts.Address.Country,
+		},
+		Tenant: opts.Tenant,
+	}
+	if !opts.BirthDate.IsZero() {
+		model.BirthDate = &opts.BirthDate
+	}
+
+	return model
+}
+
+func (ps *PostgresStorage) CreateCustomer(ctx context.Context, opts customer.CreateCustomerOptions) (uint, error) {
+	model := toCustomerModel(opts)
+	if err := ps.db.WithContext(ctx).Create(model).Error; err != nil {
+		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return model.ID, nil
+}
+
+// CompleteCustomer takes a holder without a name as created along a legacy account, since a customer can't be
+// created without one, as long as it was created for the same tenant.
+func (ps *PostgresStorage) CompleteCustomer(ctx context.Context, opts customer.CreateCustomerOptions) (uint, bool, error) {
+	model := toCustomerModel(opts)
+	result := ps.db.WithContext(ctx).Model(model).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("document_number = ? AND name = '' AND tenant = ?", opts.DocumentNumber, opts.Tenant).
+		Updates(model)
+	if err := result.Error; err != nil {
+		return 0, false, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return model.ID, result.RowsAffected > 0, nil
+}
+
+func (ps *PostgresStorage) GetCustomerByID(ctx context.Context, customerID uint) (customer.Customer, error) {
+	var dest Customer
+	if err := ps.reader(ctx).Select("*").Where("id = ?", customerID).First(&dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customer.Customer{}, account.NewInfraError(account.InfraCustomerNotFoundErrorCode, "customer not found")
+		}
+
+		return customer.Customer{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	cus := customer.Customer{
+		ID:             dest.ID,
+		DocumentNumber: dest.DocumentNumber,
+		Name:           dest.Name,
+		Address: customer.Address{
+			Street:       dest.Address.Street,
+			Number:       dest.Address.Number,
+			Complement:   dest.Address.Complement,
+			Neighborhood: dest.Address.Neighborhood,
+			City:         dest.Address.City,
+			State:        dest.Address.State,
+			ZipCode:      dest.Address.ZipCode,
+			Country:      dest.Address.Country,
+		},
+		Tenant: dest.Tenant,
+	}
+	if dest.BirthDate != nil {
+		cus.BirthDate = *dest.BirthDate
+	}
+
+	return cus, nil
+}
+
+func (ps *PostgresStorage) HasCustomerByDocumentNumber(ctx context.Context, documentNumber string) (bool, error) {
+	var dest int64
+	if err := ps.db.WithContext(ctx).Model(&Customer{}).Select("*").Where("document_number = ?", documentNumber).Count(&dest).Error; err != nil {
+		return false, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return dest > 0, nil
+}
+
+func (ps *PostgresStorage) ListAccountsByCustomerID(ctx context.Context, customerID uint) ([]account.Account, error) {
+	dest := make([]Account, 0)
+	if err := ps.reader(ctx).Select("*").Where("customer_id = ?", customerID).Order("id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	accs := make([]account.Account, 0, len(dest))
+	for _, acc := range dest {
+		accs = append(accs, toDomainAccount(acc))
+	}
+
+	return accs, nil
+}
+
+func (ps *PostgresStorage) SumTransactionsByOperationType(ctx context.Context, accountID uint, from, to time.Time) ([]billing.InvoiceTotal, error) {
+	dest := make([]InvoiceTotal, 0)
+	if err := ps.reader(ctx).Model(&AccountTransaction{}).
+		Select("operation_type_id, SUM(amount) AS amount").
+		Where("account_id = ? AND event_date >= ? AND event_date < ?", accountID, from, to).
+		Group("operation_type_id").
+		Order("operation_type_id").
+		Scan(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	totals := make([]billing.InvoiceTotal, 0, len(dest))
+	for _, t := range dest {
+		totals = append(totals, billing.InvoiceTotal{OperationTypeID: t.OperationTypeID, Amount: t.Amount})
+	}
+
+	return totals, nil
+}
+
+func (ps *PostgresStorage) SumTransactionsByDay(ctx context.Context, accountID uint, from, to time.Time, loc *time.Location) ([]statement.DailyTotal, error) {
+	dest := make([]struct {
+		Day    time.Time
+		Amount int64
+		Count  int
+	}, 0)
+	if err := ps.reader(ctx).Model(&AccountTransaction{}).
+		Select("(event_date AT TIME ZONE ?)::date AS day, SUM(amount) AS amount, COUNT(*) AS count", loc.String()).
+		Where("account_id = ? AND event_date >= ? AND event_date < ?", accountID, from, to).
+		Group("day").
+		Order("day").
+		Scan(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	totals := make([]statement.DailyTotal, 0, len(dest))
+	for _, d := range dest {
+		totals = append(totals, statement.DailyTotal{
+			Date:   time.Date(d.Day.Year(), d.Day.Month(), d.Day.Day(), 0, 0, 0, 0, loc),
+			Amount: d.Amount,
+			Count:  d.Count,
+		})
+	}
+
+	return totals, nil
+}
+
+func (ps *PostgresStorage) SumTransactionsBefore(ctx context.Context, accountID uint, before time.Time) (int64, error) {
+	var sum int64
+	if err := ps.reader(ctx).Model(&AccountTransaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account_id = ? AND event_date < ?", accountID, before).
+		Scan(&sum).Error; err != nil {
+		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return sum, nil
+}
+
+// StreamTransactions reads the transactions row by row, so a statement of any size is never loaded at once.
+func (ps *PostgresStorage) StreamTransactions(ctx context.Context, accountID uint, from, to time.Time, fn func(account.Transaction) error) error {
+	// Rows and their scans come from the same replica.
+	db := ps.reader(ctx)
+	rows, err := db.Model(&AccountTransaction{}).
+		Where("account_id = ? AND event_date >= ? AND event_date < ?", accountID, from, to).
+		Order("event_date, id").
+		Rows()
+	if err != nil {
+		return account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model AccountTransaction
+		if err := db.ScanRows(rows, &model); err != nil {
+			return account.NewInfraError(account.InfraUnknownError, err.Error())
+		}
+
+		if err := fn(toDomainTransaction(model)); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return nil
+}
+
+func (ps *PostgresStorage) SaveInvoice(ctx context.Context, inv billing.Invoice) (uint, error) {
+	model := &Invoice{
+		AccountID:   inv.AccountID,
+		PeriodStart: inv.PeriodStart,
+		PeriodEnd:   inv.PeriodEnd,
+		DueDate:     inv.DueDate,
+		Status:      string(inv.Status),
+		Total:       inv.Total,
+		PaidAmount:  inv.PaidAmount,
+		ClosedAt:    inv.ClosedAt,
+		PaidAt:      inv.PaidAt,
+	}
+	err := ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "account_id"}, {Name: "period_start"}},
+			DoUpdates: clause.AssignmentColumns([]string{"updated_at", "period_end", "due_date", "status", "total", "paid_amount", "closed_at", "paid_at"}),
+		}).Omit("Totals").Create(model).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("invoice_id = ?", model.ID).Delete(&InvoiceTotal{}).Error; err != nil {
+			return err
+		}
+
+		if len(inv.Totals) == 0 {
+			return nil
+		}
+
+		totals := make([]InvoiceTotal, 0, len(inv.Totals))
+		for _, t := range inv.Totals {
+			totals = append(totals, InvoiceTotal{InvoiceID: model.ID, OperationTypeID: t.OperationTypeID, Amount: t.Amount})
+		}
+
+		return tx.Create(&totals).Error
+	})
+	if err != nil {
+		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return model.ID, nil
+}
+
+func (ps *PostgresStorage) GetInvoiceByID(ctx context.Context, invoiceID uint) (billing.Invoice, error) {
+	var dest Invoice
+	if err := ps.reader(ctx).Preload("Totals").Where("id = ?", invoiceID).First(&dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return billing.Invoice{}, account.NewInfraError(account.InfraInvoiceNotFoundErrorCode, "invoice not found")
+		}
+
+		return billing.Invoice{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return toDomainInvoice(dest), nil
+}
+
+func (ps *PostgresStorage) GetLatestInvoiceByAccountID(ctx context.Context, accountID uint) (billing.Invoice, error) {
+	var dest Invoice
+	if err := ps.db.WithContext(ctx).Preload("Totals").Where("account_id = ?", accountID).Order("period_start DESC").First(&dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return billing.Invoice{}, account.NewInfraError(account.InfraInvoiceNotFoundErrorCode, "invoice not found")
+		}
+
+		return billing.Invoice{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return toDomainInvoice(dest), nil
+}
+
+func (ps *PostgresStorage) ListInvoicesByAccountID(ctx context.Context, accountID uint) ([]billing.Invoice, error) {
+	dest := make([]Invoice, 0)
+	if err := ps.reader(ctx).Preload("Totals").Where("account_id = ?", accountID).Order("period_start").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	invs := make([]billing.Invoice, 0, len(dest))
+	for _, inv := range dest {
+		invs = append(invs, toDomainInvoice(inv))
+	}
+
+	return invs, nil
+}
+
+func (ps *PostgresStorage) ListInvoicesByStatus(ctx context.Context, statuses ...billing.Status) ([]billing.Invoice, error) {
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, string(status))
+	}
+
+	dest := make([]Invoice, 0)
+	if err := ps.db.WithContext(ctx).Preload("Totals").Where("status IN ?", values).Order("account_id, period_start").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	invs := make([]billing.Invoice, 0, len(dest))
+	for _, inv := range dest {
+		invs = append(invs, toDomainInvoice(inv))
+	}
+
+	return invs, nil
+}
+
+// IncrementCounter upserts the counter and returns it from the same statement, concurrent increments of a key
+// being serialized by the row lock of the conflict.
+func (ps *PostgresStorage) IncrementCounter(ctx context.Context, key fraud.CounterKey, delta fraud.Counter, expiresAt time.Time) (fraud.Counter, error) {
+	model := &RuleCounter{
+		Key:         key.Name,
+		WindowStart: key.WindowStart,
+		Count:       delta.Count,
+		Amount:      delta.Amount,
+		ExpiresAt:   expiresAt,
 	}
-	if err := ps.db.Create(&model).Error; err != nil {
+	if err := ps.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}, {Name: "window_start"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":  gorm.Expr("rule_counters.count + EXCLUDED.count"),
+			"amount": gorm.Expr("rule_counters.amount + EXCLUDED.amount"),
+		}),
+	}, clause.Returning{Columns: []clause.Column{{Name: "count"}, {Name: "amount"}}}).Create(model).Error; err != nil {
+		return fraud.Counter{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return fraud.Counter{Count: model.Count, Amount: model.Amount}, nil
+}
+
+func (ps *PostgresStorage) CreateScheduledTransaction(ctx context.Context, st schedule.ScheduledTransaction) (uint, error) {
+	model := &ScheduledTransaction{
+		AccountID:       st.AccountID,
+		OperationTypeID: st.OperationTypeID,
+		Amount:          st.Amount,
+		StartAt:         st.StartAt,
+		EndAt:           st.EndAt,
+		MaxOccurrences:  st.MaxOccurrences,
+		Occurrences:     st.Occurrences,
+		NextRunAt:       st.NextRunAt,
+		Status:          string(st.Status),
+	}
+	if st.Recurrence != nil {
+		model.RecurrenceFrequency = string(st.Recurrence.Frequency)
+		model.RecurrenceInterval = st.Recurrence.Interval
+	}
+	if err := ps.db.WithContext(ctx).Create(model).Error; err != nil {
 		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
 	}
 
 	return model.ID, nil
 }
 
+func (ps *PostgresStorage) GetScheduledTransactionByID(ctx context.Context, id uint) (schedule.ScheduledTransaction, error) {
+	var dest ScheduledTransaction
+	if err := ps.reader(ctx).Where("id = ?", id).First(&dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return schedule.ScheduledTransaction{}, account.NewInfraError(account.InfraScheduleNotFoundErrorCode, "scheduled transaction not found")
+		}
+
+		return schedule.ScheduledTransaction{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return toDomainScheduledTransaction(dest), nil
+}
+
+func (ps *PostgresStorage) ListScheduledTransactionsByAccountID(ctx context.Context, accountID uint) ([]schedule.ScheduledTransaction, error) {
+	dest := make([]ScheduledTransaction, 0)
+	if err := ps.reader(ctx).Where("account_id = ?", accountID).Order("id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	sts := make([]schedule.ScheduledTransaction, 0, len(dest))
+	for _, st := range dest {
+		sts = append(sts, toDomainScheduledTransaction(st))
+	}
+
+	return sts, nil
+}
+
+func (ps *PostgresStorage) ListDueScheduledTransactions(ctx context.Context, at time.Time) ([]schedule.ScheduledTransaction, error) {
+	dest := make([]ScheduledTransaction, 0)
+	if err := ps.db.WithContext(ctx).Where("status = ? AND next_run_at <= ?", string(schedule.ActiveStatus), at).Order("next_run_at, id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	sts := make([]schedule.ScheduledTransaction, 0, len(dest))
+	for _, st := range dest {
+		sts = append(sts, toDomainScheduledTransaction(st))
+	}
+
+	return sts, nil
+}
+
+// UpdateScheduledTransaction only writes over the version it was read at, so a replica holding
+// a stale copy gets InfraScheduleOutdatedErrorCode instead of stepping back someone else's progress.
+func (ps *PostgresStorage) UpdateScheduledTransaction(ctx context.Context, st schedule.ScheduledTransaction) error {
+	result := ps.db.WithContext(ctx).Model(&ScheduledTransaction{}).Where("id = ? AND version = ?", st.ID, st.Version).Updates(map[string]interface{}{
+		"amount":          st.Amount,
+		"end_at":          st.EndAt,
+		"max_occurrences": st.MaxOccurrences,
+		"occurrences":     st.Occurrences,
+		"next_run_at":     st.NextRunAt,
+		"status":          string(st.Status),
+		"version":         st.Version + 1,
+	})
+	if err := result.Error; err != nil {
+		return account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return account.NewInfraError(account.InfraScheduleOutdatedErrorCode, "scheduled transaction was updated by someone else")
+	}
+
+	return nil
+}
+
+func (ps *PostgresStorage) ListOperationTypes(ctx context.Context) ([]account.OperationType, error) {
+	dest := make([]OperationType, 0)
+	if err := ps.reader(ctx).Order("id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	ots := make([]account.OperationType, 0, len(dest))
+	for _, ot := range dest {
+		ots = append(ots, account.OperationType{ID: ot.ID, Description: ot.Description})
+	}
+
+	return ots, nil
+}
+
+func (ps *PostgresStorage) CreateAPIKey(ctx context.Context, key auth.APIKey) (uint, error) {
+	model := &APIKey{
+		Name:          key.Name,
+		Prefix:        key.Prefix,
+		Hash:          key.Hash,
+		SigningSecret: key.SigningSecret,
+		Scopes:        joinScopes(key.Scopes),
+		Tenant:        key.Tenant,
+	}
+	model.CreatedAt = key.CreatedAt
+	if err := ps.db.WithContext(ctx).Create(model).Error; err != nil {
+		return 0, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return model.ID, nil
+}
+
+// GetAPIKeyByPrefix reads from the primary, a key revoked a moment ago mustn't be accepted by a lagging replica.
+func (ps *PostgresStorage) GetAPIKeyByPrefix(ctx context.Context, prefix string) (auth.APIKey, error) {
+	var dest APIKey
+	if err := ps.db.WithContext(ctx).Where("prefix = ?", prefix).First(&dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return auth.APIKey{}, account.NewInfraError(account.InfraAPIKeyNotFoundErrorCode, "api key not found")
+		}
+
+		return auth.APIKey{}, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	return toDomainAPIKey(dest), nil
+}
+
+func (ps *PostgresStorage) ListAPIKeys(ctx context.Context) ([]auth.APIKey, error) {
+	dest := make([]APIKey, 0)
+	if err := ps.db.WithContext(ctx).Order("id").Find(&dest).Error; err != nil {
+		return nil, account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	keys := make([]auth.APIKey, 0, len(dest))
+	for _, key := range dest {
+		keys = append(keys, toDomainAPIKey(key))
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey keeps the time of the first revocation when it's revoked again.
+func (ps *PostgresStorage) RevokeAPIKey(ctx context.Context, id uint, at time.Time) error {
+	result := ps.db.WithContext(ctx).Model(&APIKey{}).Where("id = ?", id).Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", at))
+	if err := result.Error; err != nil {
+		return account.NewInfraError(account.InfraUnknownError, err.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return account.NewInfraError(account.InfraAPIKeyNotFoundErrorCode, "api key not found")
+	}
+
+	return nil
+}
+
 func (ps *PostgresStorage) RunSeed() error {
 	return ps.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(OperationTypeSeedData, len(OperationTypeSeedData)).Error
 }
@@ -84,32 +709,242 @@ type NewStorageOptions struct {
 	Password     string
 	DatabaseName string
 	Port         string
-	Logger       log.LoggerRepository
+	// SSLMode is one of disable, allow, prefer, require, verify-ca or verify-full, disable when it's empty.
+	SSLMode string
+	// SSLRootCert is the CA bundle the server certificate is verified against, SSLCert and SSLKey are
+	// the client certificate, all of them file paths.
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+	// ApplicationName shows up in pg_stat_activity and the server logs.
+	ApplicationName string
+	// StatementTimeout aborts any statement running for longer, zero means no timeout.
+	StatementTimeout time.Duration
+	// DSN, when set, is used as it is instead of the connection fields above, only pool settings still apply.
+	DSN string
+	// Zero pool settings keep the database/sql defaults.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ReplicaDSNs are read replicas taking the read-only queries in turns, with the same pool settings.
+	ReplicaDSNs []string
+	// ReplicaCheckInterval is how often replicas are pinged to leave the ones down out, DefaultReplicaCheckInterval
+	// when it's zero.
+	ReplicaCheckInterval time.Duration
+	Logger               log.LoggerRepository
+	// SkipMigration leaves the schema untouched, Migrate runs it on demand.
+	SkipMigration bool
+	// Metrics, when set, observes the latency of every query.
+	Metrics metrics.MetricsRepository
+	// TracerProvider, when set, makes a span of every query.
+	TracerProvider trace.TracerProvider
 }
 
-func NewStorage(opts NewStorageOptions) (*PostgresStorage, error) {
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%v/%s?sslmode=%s", opts.User, opts.Password, opts.Host, opts.Port, opts.DatabaseName, "disable")
-	db, err := gorm.Open(driver.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+func (opts NewStorageOptions) dsn() string {
+	if opts.DSN != "" {
+		return opts.DSN
+	}
+
+	sslmode := opts.SSLMode
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", sslmode)
+	if opts.SSLRootCert != "" {
+		query.Set("sslrootcert", opts.SSLRootCert)
+	}
+	if opts.SSLCert != "" {
+		query.Set("sslcert", opts.SSLCert)
+	}
+	if opts.SSLKey != "" {
+		query.Set("sslkey", opts.SSLKey)
+	}
+	if opts.ApplicationName != "" {
+		query.Set("application_name", opts.ApplicationName)
+	}
+	if opts.StatementTimeout > 0 {
+		query.Set("statement_timeout", strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10))
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(opts.User, opts.Password),
+		Host:     net.JoinHostPort(opts.Host, opts.Port),
+		Path:     "/" + opts.DatabaseName,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+// open builds the pool without connecting, the first connection is up to the Bootstrapper.
+func open(opts NewStorageOptions) (*gorm.DB, *sql.DB, error) {
+	db, err := gorm.Open(driver.Open(opts.dsn()), &gorm.Config{
+		Logger:               logger.Default.LogMode(logger.Silent),
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	exr, err := db.DB()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if err := exr.Ping(); err != nil {
-		return nil, err
+	if opts.MaxOpenConns > 0 {
+		exr.SetMaxOpenConns(opts.MaxOpenConns)
 	}
+	if opts.MaxIdleConns > 0 {
+		exr.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		exr.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if opts.ConnMaxIdleTime > 0 {
+		exr.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
+
+	return db, exr, nil
+}
 
-	if err := db.AutoMigrate(&Account{}, &OperationType{}, &AccountTransaction{}); err != nil {
-		return nil, err
+func setup(db *gorm.DB, opts NewStorageOptions) (*PostgresStorage, error) {
+	if opts.Metrics != nil {
+		if err := registerMetrics(db, opts.Metrics); err != nil {
+			return nil, err
+		}
 	}
 
-	ps := &PostgresStorage{db, opts.Logger}
+	if opts.TracerProvider != nil {
+		if err := registerTracing(db, opts.TracerProvider); err != nil {
+			return nil, err
+		}
+	}
+
+	ps := &PostgresStorage{db: db, logger: opts.Logger}
+
+	if !opts.SkipMigration {
+		if err := ps.Migrate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(opts.ReplicaDSNs) > 0 {
+		replicas, err := openReplicas(opts)
+		if err != nil {
+			return nil, err
+		}
+		ps.replicas = replicas
+	}
 
 	return ps, nil
 }
+
+// openReplicas checks the replicas once before watching them, so the ones up take reads right away.
+func openReplicas(opts NewStorageOptions) (*replicaSet, error) {
+	interval := opts.ReplicaCheckInterval
+	if interval <= 0 {
+		interval = DefaultReplicaCheckInterval
+	}
+
+	rs := &replicaSet{logger: opts.Logger}
+	for i, dsn := range opts.ReplicaDSNs {
+		ropts := opts
+		ropts.DSN = dsn
+
+		db, _, err := open(ropts)
+		if err == nil && opts.Metrics != nil {
+			err = registerMetrics(db, opts.Metrics)
+		}
+		if err == nil && opts.TracerProvider != nil {
+			err = registerTracing(db, opts.TracerProvider)
+		}
+		if err != nil {
+			rs.close()
+			return nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+
+		rs.replicas = append(rs.replicas, &replica{name: fmt.Sprintf("replica %d", i+1), db: db})
+	}
+
+	rs.check(context.Background(), interval)
+	rs.watch(interval)
+
+	return rs, nil
+}
+
+// NewStorage tries to connect only once, a Bootstrapper keeps trying for a while.
+func NewStorage(opts NewStorageOptions) (*PostgresStorage, error) {
+	return NewBootstrapper(BootstrapOptions{Logger: opts.Logger}).Connect(context.Background(), opts)
+}
+
+var models = []interface{}{&Customer{}, &Account{}, &OperationType{}, &AccountTransaction{}, &Invoice{}, &InvoiceTotal{}, &RuleCounter{}, &ScheduledTransaction{}, &APIKey{}}
+
+// DB exposes the pool behind the storage, for instance to collect its stats.
+func (ps *PostgresStorage) DB() (*sql.DB, error) {
+	return ps.db.DB()
+}
+
+// Close releases the pools, queries still running get to finish.
+func (ps *PostgresStorage) Close() error {
+	if ps.replicas != nil {
+		if err := ps.replicas.close(); err != nil {
+			return err
+		}
+	}
+
+	db, err := ps.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.Close()
+}
+
+func (ps *PostgresStorage) Migrate() error {
+	if err := ps.db.AutoMigrate(models...); err != nil {
+		return err
+	}
+
+	if err := migrateAccountHolders(ps.db); err != nil {
+		return err
+	}
+
+	if err := ps.db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON accounts (customer_id) WHERE legacy AND deleted_at IS NULL", legacyAccountsIndex)).Error; err != nil {
+		return err
+	}
+
+	return ps.db.Exec("CREATE INDEX IF NOT EXISTS idx_transactions_merchant_mcc ON transactions ((merchant->>'mcc'))").Error
+}
+
+// legacyAccountsIndex keeps a holder from having two accounts opened without a customer, as the document number
+// was unique per account before customers existed.
+const legacyAccountsIndex = "idx_accounts_legacy_customer"
+
+// Before customers existed the document number was unique per account, so databases created back then
+// still carry that constraint and have accounts without a holder, the ones bound here being legacy.
+func migrateAccountHolders(db *gorm.DB) error {
+	if db.Migrator().HasConstraint(&Account{}, "accounts_document_number_key") {
+		if err := db.Migrator().DropConstraint(&Account{}, "accounts_document_number_key"); err != nil {
+			return err
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO customers (document_number, created_at, updated_at)
+			SELECT DISTINCT document_number, NOW(), NOW() FROM accounts WHERE customer_id IS NULL
+			ON CONFLICT (document_number) DO NOTHING
+		`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE accounts SET customer_id = customers.id, legacy = true FROM customers
+			WHERE accounts.customer_id IS NULL AND accounts.document_number = customers.document_number
+		`).Error
+	})
+}
@@ -8,6 +8,11 @@ import (
 	"time"
 
 	"github/guiferpa/bank/domain/account"
+	"github/guiferpa/bank/domain/auth"
+	"github/guiferpa/bank/domain/billing"
+	"github/guiferpa/bank/domain/customer"
+	"github/guiferpa/bank/domain/fraud"
+	"github/guiferpa/bank/domain/health"
 	"github/guiferpa/bank/pkg/docker"
 )
 
@@ -36,8 +41,6 @@ func TestIntegrationForInfra(t *testing.T) {
 		}
 	}()
 
-	time.Sleep(4 * time.Second)
-
 	newStorageOptions := NewStorageOptions{
 		Host:         "localhost",
 		User:         "postgres",
@@ -45,7 +48,7 @@ func TestIntegrationForInfra(t *testing.T) {
 		DatabaseName: "infra",
 		Port:         "5432",
 	}
-	client, err := NewStorage(newStorageOptions)
+	client, err := NewBootstrapper(BootstrapOptions{Timeout: 30 * time.Second}).Connect(ctx, newStorageOptions)
 	if err != nil {
 		t.Error(err)
 		return
@@ -95,13 +98,53 @@ func TestIntegrationForInfra(t *testing.T) {
 				}
 			},
 		},
+		{
+			Describe: "Migration run again successful",
+			Spec: func(t *testing.T) {
+				if err := client.Migrate(); err != nil {
+					t.Error(err)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Checked health with database up and migrated",
+			Spec: func(t *testing.T) {
+				for _, check := range client.HealthChecks(context.Background()) {
+					if got, expected := check.Status, health.UpStatus; got != expected {
+						t.Errorf("unexpected %s check status, got: %v, expected: %v (%s)", check.Name, got, expected, check.Error)
+						return
+					}
+				}
+			},
+		},
+		{
+			Describe: "Listed operation types successful",
+			Spec: func(t *testing.T) {
+				ots, err := client.ListOperationTypes(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := len(ots), len(OperationTypeSeedData); got != expected {
+					t.Errorf("unexpected number of operation types, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := ots[0].Description, "COMPRA A VISTA"; got != expected {
+					t.Errorf("unexpected operation type description, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
 		{
 			Describe: "Created account successful",
 			Spec: func(t *testing.T) {
 				createAccountOptions := account.CreateAccountOptions{
 					DocumentNumber: "42",
 				}
-				id, err := client.CreateAccount(createAccountOptions)
+				id, err := client.CreateAccount(context.Background(), createAccountOptions)
 				if err != nil {
 					t.Error(err)
 					return
@@ -120,13 +163,69 @@ func TestIntegrationForInfra(t *testing.T) {
 			},
 		},
 		{
-			Describe: "Got duplicated account error when create account with the same document number",
+			Describe: "Created account bound to the holder with the same document number",
 			Spec: func(t *testing.T) {
-				createAccountOptions := account.CreateAccountOptions{
-					DocumentNumber: "42",
+				dest := Account{}
+				if err := client.db.Select("*").Where("id = ?", 1).Find(&dest).Error; err != nil {
+					t.Error(err)
+					return
 				}
-				if _, err := client.CreateAccount(createAccountOptions); err == nil {
-					t.Errorf("unexpected value for error, got: %v", err)
+
+				holder := Customer{}
+				if err := client.db.Select("*").Where("id = ?", dest.CustomerID).First(&holder).Error; err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := holder.DocumentNumber, dest.DocumentNumber; got != expected {
+					t.Errorf("unexpected holder's DocumentNumber, got: %s, expected: %s", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Declined second account without customer of the holder",
+			Spec: func(t *testing.T) {
+				_, err := client.CreateAccount(context.Background(), account.CreateAccountOptions{DocumentNumber: "42"})
+				cerr, ok := err.(*account.DomainError)
+				if !ok {
+					t.Errorf("unexpected error, got: %v", err)
+					return
+				}
+
+				if got, expected := cerr.Code, account.DomainAccountAlreadyExistsErrorCode; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Completed the holder created along an account without customer",
+			Spec: func(t *testing.T) {
+				id, ok, err := client.CompleteCustomer(context.Background(), customer.CreateCustomerOptions{DocumentNumber: "42", Name: "John Doe"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := ok, true; got != expected {
+					t.Errorf("unexpected completed, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				cus, err := client.GetCustomerByID(context.Background(), id)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := cus.DocumentNumber+" "+cus.Name, "42 John Doe"; got != expected {
+					t.Errorf("unexpected customer, got: %s, expected: %s", got, expected)
+					return
+				}
+
+				if _, ok, err = client.CompleteCustomer(context.Background(), customer.CreateCustomerOptions{DocumentNumber: "42", Name: "Jane Doe"}); err != nil || ok {
+					t.Errorf("unexpected complete of a customer with a name, got: %v, %v", ok, err)
 					return
 				}
 			},
@@ -136,7 +235,7 @@ func TestIntegrationForInfra(t *testing.T) {
 			Spec: func(t *testing.T) {
 				documentNumber := "42"
 
-				acc, err := client.GetAccountByID(1)
+				acc, err := client.GetAccountByID(context.Background(), 1)
 				if err != nil {
 					t.Error(err)
 					return
@@ -151,7 +250,7 @@ func TestIntegrationForInfra(t *testing.T) {
 		{
 			Describe: "Got account not found when get account by ID",
 			Spec: func(t *testing.T) {
-				_, err := client.GetAccountByID(2)
+				_, err := client.GetAccountByID(context.Background(), 2)
 				if _, ok := err.(*account.InfraError); !ok {
 					t.Errorf("unexpected value for error, got: %v", err)
 					return
@@ -167,7 +266,7 @@ func TestIntegrationForInfra(t *testing.T) {
 					Amount:          -10_00,
 					EventDate:       time.Now(),
 				}
-				transID, err := client.CreateTransaction(transOptions)
+				transID, err := client.CreateTransaction(context.Background(), transOptions)
 				if err != nil {
 					t.Error(err)
 					return
@@ -185,11 +284,373 @@ func TestIntegrationForInfra(t *testing.T) {
 				}
 			},
 		},
+		{
+			Describe: "Got duplicated transaction error when create transaction with the same idempotency key",
+			Spec: func(t *testing.T) {
+				transOptions := account.CreateTransactionOptions{
+					AccountID:       1,
+					OperationTypeID: account.IOFOperationTypeID,
+					Amount:          -38,
+					EventDate:       time.Now(),
+					IdempotencyKey:  "iof:1",
+				}
+				if _, err := client.CreateTransaction(context.Background(), transOptions); err != nil {
+					t.Error(err)
+					return
+				}
+
+				_, err := client.CreateTransaction(context.Background(), transOptions)
+				cerr, ok := err.(*account.InfraError)
+				if !ok {
+					t.Errorf("unexpected value for error, got: %v", err)
+					return
+				}
+
+				if got, expected := cerr.Code, account.InfraTransactionDuplicatedErrorCode; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				txs, err := client.ListTransactionsByAccountID(context.Background(), 1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := len(txs), 2; got != expected {
+					t.Errorf("unexpected number of transactions, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Created customer successful",
+			Spec: func(t *testing.T) {
+				createCustomerOptions := customer.CreateCustomerOptions{
+					DocumentNumber: "84",
+					Name:           "Jane Doe",
+					BirthDate:      time.Date(1990, time.January, 31, 0, 0, 0, 0, time.UTC),
+					Address:        customer.Address{City: "São Paulo", Country: "BR"},
+				}
+				id, err := client.CreateCustomer(context.Background(), createCustomerOptions)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				cus, err := client.GetCustomerByID(context.Background(), id)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := cus.Name, createCustomerOptions.Name; got != expected {
+					t.Errorf("unexpected Name, got: %s, expected: %s", got, expected)
+					return
+				}
+
+				if got, expected := cus.BirthDate.Format("2006-01-02"), "1990-01-31"; got != expected {
+					t.Errorf("unexpected BirthDate, got: %s, expected: %s", got, expected)
+					return
+				}
+
+				if got, expected := cus.Address.City, createCustomerOptions.Address.City; got != expected {
+					t.Errorf("unexpected Address.City, got: %s, expected: %s", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Created many accounts for the same customer successful",
+			Spec: func(t *testing.T) {
+				cus := &Customer{DocumentNumber: "85"}
+				if err := client.db.Create(cus).Error; err != nil {
+					t.Error(err)
+					return
+				}
+
+				for i := 0; i < 2; i++ {
+					opts := account.CreateAccountOptions{CustomerID: cus.ID, DocumentNumber: cus.DocumentNumber}
+					if _, err := client.CreateAccount(context.Background(), opts); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+
+				accs, err := client.ListAccountsByCustomerID(context.Background(), cus.ID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := len(accs), 2; got != expected {
+					t.Errorf("unexpected number of accounts, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Got customer not found when get customer by ID",
+			Spec: func(t *testing.T) {
+				_, err := client.GetCustomerByID(context.Background(), 9000)
+				cerr, ok := err.(*account.InfraError)
+				if !ok {
+					t.Errorf("unexpected value for error, got: %v", err)
+					return
+				}
+
+				if got, expected := cerr.Code, account.InfraCustomerNotFoundErrorCode; got != expected {
+					t.Errorf("unexpected error code, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Saved invoice replacing the one for the same period successful",
+			Spec: func(t *testing.T) {
+				inv := billing.Invoice{
+					AccountID:   1,
+					PeriodStart: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+					PeriodEnd:   time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+					DueDate:     time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+					Status:      billing.OpenStatus,
+					Totals:      []billing.InvoiceTotal{{OperationTypeID: 1, Amount: -10_00}},
+					Total:       -10_00,
+				}
+				id, err := client.SaveInvoice(context.Background(), inv)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				inv.Status = billing.ClosedStatus
+				inv.Totals = append(inv.Totals, billing.InvoiceTotal{OperationTypeID: 3, Amount: -5_00})
+				inv.Total = -15_00
+				replacedID, err := client.SaveInvoice(context.Background(), inv)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := replacedID, id; got != expected {
+					t.Errorf("unexpected invoice ID, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				dest, err := client.GetInvoiceByID(context.Background(), id)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := dest.Status, billing.ClosedStatus; got != expected {
+					t.Errorf("unexpected invoice status, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := len(dest.Totals), 2; got != expected {
+					t.Errorf("unexpected number of invoice totals, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Summed account transactions by operation type successful",
+			Spec: func(t *testing.T) {
+				totals, err := client.SumTransactionsByOperationType(context.Background(), 1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := len(totals), 2; got != expected {
+					t.Errorf("unexpected number of totals, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := totals[0].Amount, int64(-10_00); got != expected {
+					t.Errorf("unexpected total amount, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Incremented rule counter successful",
+			Spec: func(t *testing.T) {
+				key := fraud.CounterKey{Name: "velocity:1", WindowStart: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)}
+				var counter fraud.Counter
+				for i := 0; i < 2; i++ {
+					var err error
+					if counter, err = client.IncrementCounter(context.Background(), key, fraud.Counter{Count: 1, Amount: 10_00}, key.WindowStart.Add(time.Minute)); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+
+				if got, expected := counter, (fraud.Counter{Count: 2, Amount: 20_00}); got != expected {
+					t.Errorf("unexpected counter, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Summed transactions by day in the account's time zone keeping the informed offset",
+			Spec: func(t *testing.T) {
+				accountID, err := client.CreateAccount(context.Background(), account.CreateAccountOptions{DocumentNumber: "86", TimeZone: "America/Sao_Paulo"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				eventDate := time.Date(2023, time.March, 1, 23, 30, 0, 0, time.FixedZone("", -3*60*60))
+				transOptions := account.CreateTransactionOptions{
+					AccountID:       accountID,
+					OperationTypeID: account.CashPurchaseOperationTypeID,
+					Amount:          -10_00,
+					EventDate:       eventDate,
+				}
+				if _, err := client.CreateTransaction(context.Background(), transOptions); err != nil {
+					t.Error(err)
+					return
+				}
+
+				txs, err := client.ListTransactionsByAccountID(context.Background(), accountID, eventDate.AddDate(0, 0, -1), eventDate.AddDate(0, 0, 1))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := txs[0].EventDate.Format(time.RFC3339), "2023-03-01T23:30:00-03:00"; got != expected {
+					t.Errorf("unexpected event date, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				acc, err := client.GetAccountByID(context.Background(), accountID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				totals, err := client.SumTransactionsByDay(context.Background(), accountID, eventDate.AddDate(0, 0, -1), eventDate.AddDate(0, 0, 1), acc.Location())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := len(totals), 1; got != expected {
+					t.Errorf("unexpected number of totals, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := totals[0].Date.Format("2006-01-02"), "2023-03-01"; got != expected {
+					t.Errorf("unexpected total day, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Streamed statement transactions with opening balance successful",
+			Spec: func(t *testing.T) {
+				accountID, err := client.CreateAccount(context.Background(), account.CreateAccountOptions{DocumentNumber: "87"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				start := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+				for _, eventDate := range []time.Time{start.Add(-time.Hour), start.Add(time.Hour), start.Add(2 * time.Hour)} {
+					if _, err := client.CreateTransaction(context.Background(), account.CreateTransactionOptions{
+						AccountID:       accountID,
+						OperationTypeID: account.CashPurchaseOperationTypeID,
+						Amount:          -10_00,
+						EventDate:       eventDate,
+					}); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+
+				opening, err := client.SumTransactionsBefore(context.Background(), accountID, start)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := opening, int64(-10_00); got != expected {
+					t.Errorf("unexpected opening balance, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				streamed := 0
+				if err := client.StreamTransactions(context.Background(), accountID, start, start.AddDate(0, 0, 1), func(tx account.Transaction) error {
+					streamed += 1
+					return nil
+				}); err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := streamed, 2; got != expected {
+					t.Errorf("unexpected number of streamed transactions, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
+		{
+			Describe: "Listed transactions filtering by merchant's MCC successful",
+			Spec: func(t *testing.T) {
+				transOptions := account.CreateTransactionOptions{
+					AccountID:       1,
+					OperationTypeID: account.CashPurchaseOperationTypeID,
+					Amount:          -25_00,
+					EventDate:       time.Now(),
+					Merchant:        &account.Merchant{Name: "Padaria Real", MCC: "5462", Country: "BR"},
+					Metadata:        map[string]string{"order_id": "42"},
+				}
+				transID, err := client.CreateTransaction(context.Background(), transOptions)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				txs, err := client.ListTransactions(context.Background(), account.ListTransactionsOptions{AccountID: 1, MCC: "5462"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := len(txs), 1; got != expected {
+					t.Errorf("unexpected number of transactions, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := txs[0].ID, transID; got != expected {
+					t.Errorf("unexpected transaction ID, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				tx, err := client.GetTransactionByID(context.Background(), transID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := tx.Merchant.Name, transOptions.Merchant.Name; got != expected {
+					t.Errorf("unexpected merchant name, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if got, expected := tx.Metadata["order_id"], "42"; got != expected {
+					t.Errorf("unexpected metadata, got: %v, expected: %v", got, expected)
+					return
+				}
+			},
+		},
 		{
 			Describe: "Got account by document number successful",
 			Spec: func(t *testing.T) {
 				acc := &Account{DocumentNumber: "42"}
-				has, err := client.HasAccountByDocumentNumber(acc.DocumentNumber)
+				has, err := client.HasAccountByDocumentNumber(context.Background(), acc.DocumentNumber)
 				if err != nil {
 					t.Error(err)
 					return
@@ -204,13 +665,13 @@ func TestIntegrationForInfra(t *testing.T) {
 		{
 			Describe: "Got none account by document number successful",
 			Spec: func(t *testing.T) {
-				acc := &Account{DocumentNumber: "43"}
-				if err := client.db.Create(&acc).Error; err != nil {
+				acc := account.CreateAccountOptions{DocumentNumber: "43"}
+				if _, err := client.CreateAccount(context.Background(), acc); err != nil {
 					t.Error(err)
 					return
 				}
 
-				has, err := client.HasAccountByDocumentNumber("44")
+				has, err := client.HasAccountByDocumentNumber(context.Background(), "44")
 				if err != nil {
 					t.Error(err)
 					return
@@ -222,6 +683,41 @@ func TestIntegrationForInfra(t *testing.T) {
 				}
 			},
 		},
+		{
+			Describe: "Revoked API key keeps its first revocation time",
+			Spec: func(t *testing.T) {
+				created := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+				id, err := client.CreateAPIKey(context.Background(), auth.APIKey{Name: "backoffice", Prefix: "a1b2c3d4e5f6", Hash: "hash", CreatedAt: created})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				revoked := created.Add(time.Hour)
+				for _, at := range []time.Time{revoked, revoked.Add(time.Hour)} {
+					if err := client.RevokeAPIKey(context.Background(), id, at); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+
+				key, err := client.GetAPIKeyByPrefix(context.Background(), "a1b2c3d4e5f6")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if got, expected := key.RevokedAt.UTC(), revoked; !got.Equal(expected) {
+					t.Errorf("unexpected revocation time, got: %v, expected: %v", got, expected)
+					return
+				}
+
+				if err := client.RevokeAPIKey(context.Background(), 1398, revoked); err == nil {
+					t.Error("unexpected nil error revoking an unknown api key")
+					return
+				}
+			},
+		},
 	}
 
 	for _, s := range suite {
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const querySpanKey = "tracing:query_span"
+
+// registerTracing starts a span for every statement gorm runs, child of the one in the statement's context.
+func registerTracing(db *gorm.DB, tp trace.TracerProvider) error {
+	tracer := tp.Tracer("github/guiferpa/bank/infra/storage/postgres")
+
+	before := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			ctx, span := tracer.Start(tx.Statement.Context, "gorm."+operation, trace.WithSpanKind(trace.SpanKindClient))
+			tx.Statement.Context = ctx
+			tx.InstanceSet(querySpanKey, span)
+		}
+	}
+
+	after := func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(querySpanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+
+		span.SetAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.sql.table", tx.Statement.Table),
+			attribute.String("db.statement", tx.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+
+		if err := tx.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("tracing:before_create", before("create")); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("tracing:after_create", after); err != nil {
+		return err
+	}
+
+	if err := cb.Query().Before("gorm:query").Register("tracing:before_query", before("query")); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("tracing:after_query", after); err != nil {
+		return err
+	}
+
+	if err := cb.Update().Before("gorm:update").Register("tracing:before_update", before("update")); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("tracing:after_update", after); err != nil {
+		return err
+	}
+
+	if err := cb.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("tracing:after_delete", after); err != nil {
+		return err
+	}
+
+	if err := cb.Row().Before("gorm:row").Register("tracing:before_row", before("row")); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("tracing:after_row", after); err != nil {
+		return err
+	}
+
+	if err := cb.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")); err != nil {
+		return err
+	}
+
+	return cb.Raw().After("gorm:raw").Register("tracing:after_raw", after)
+}
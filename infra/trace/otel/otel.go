@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github/guiferpa/bank/domain/trace"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	NoneExporter   = "none"
+	StdoutExporter = "stdout"
+	OTLPExporter   = "otlp"
+)
+
+// NewExporter builds the exporter by its kind, none (or empty) gives no exporter so spans are only
+// made for their IDs to show up in the logs. OTLP is configured by the standard OTEL_EXPORTER_OTLP_* variables.
+func NewExporter(ctx context.Context, kind string, w io.Writer) (sdktrace.SpanExporter, error) {
+	switch kind {
+	case "", NoneExporter:
+		return nil, nil
+	case StdoutExporter:
+		return stdouttrace.New(stdouttrace.WithWriter(w))
+	case OTLPExporter:
+		return otlptracehttp.New(ctx)
+	}
+
+	return nil, fmt.Errorf("unknown tracing exporter %q", kind)
+}
+
+func NewTracerProvider(serviceName string, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	}
+
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+type Span struct {
+	span oteltrace.Span
+}
+
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
+
+// Tracer adapts an OpenTelemetry tracer to the domain one.
+type Tracer struct {
+	tracer oteltrace.Tracer
+}
+
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	ctx, span := t.tracer.Start(ctx, name)
+
+	return ctx, &Span{span}
+}
+
+func NewTracer(tp oteltrace.TracerProvider, name string) *Tracer {
+	return &Tracer{tp.Tracer(name)}
+}
@@ -0,0 +1,65 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := NewTracerProvider("bank-test", exporter)
+	tracer := NewTracer(tp, "test")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.End(errors.New("account not found"))
+	parent.End(nil)
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Error(err)
+		return
+	}
+
+	spans := exporter.GetSpans()
+	if got, expected := len(spans), 2; got != expected {
+		t.Errorf("unexpected number of spans, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := spans[0].Name, "child"; got != expected {
+		t.Errorf("unexpected span name, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := spans[0].Parent.SpanID(), spans[1].SpanContext.SpanID(); got != expected {
+		t.Errorf("unexpected parent span, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := spans[0].Status.Code, codes.Error; got != expected {
+		t.Errorf("unexpected child status, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if got, expected := spans[1].Status.Code, codes.Unset; got != expected {
+		t.Errorf("unexpected parent status, got: %v, expected: %v", got, expected)
+		return
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter, err := NewExporter(context.Background(), NoneExporter, nil)
+	if err != nil || exporter != nil {
+		t.Errorf("unexpected exporter for none, got: %v, %v", exporter, err)
+		return
+	}
+
+	if _, err := NewExporter(context.Background(), "zipkin", nil); err == nil {
+		t.Error("expected unknown exporter error")
+		return
+	}
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Every leaf field is tagged with its key in the file, flags are named after it (database.host is -database-host)
+// and env is its var environment. Secrets are redacted when printed and may be read from the file in <env>_FILE.
+type Config struct {
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Log       LogConfig
+	Auth      AuthConfig
+	Tracing   TracingConfig
+	Fraud     FraudConfig
+	RateLimit RateLimitConfig
+	EventDate EventDateConfig
+	Batch     BatchConfig
+	Scheduler SchedulerConfig
+}
+
+type ServerConfig struct {
+	Port              int           `config:"server.port" env:"PORT"`
+	ReadHeaderTimeout time.Duration `config:"server.read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `config:"server.read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `config:"server.write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `config:"server.idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `config:"server.shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+type DatabaseConfig struct {
+	Host     string `config:"database.host" env:"DATABASE_HOST"`
+	Port     int    `config:"database.port" env:"DATABASE_PORT"`
+	User     string `config:"database.user" env:"DATABASE_USER"`
+	Password string `config:"database.password" env:"DATABASE_PASSWORD" secret:"true"`
+	Name     string `config:"database.name" env:"DATABASE_NAME"`
+	// DSN is used as it is instead of the connection fields, only pool settings still apply.
+	DSN              string        `config:"database.dsn" env:"DATABASE_DSN" secret:"true"`
+	SSLMode          string        `config:"database.sslmode" env:"DATABASE_SSLMODE"`
+	SSLRootCert      string        `config:"database.sslrootcert" env:"DATABASE_SSLROOTCERT"`
+	SSLCert          string        `config:"database.sslcert" env:"DATABASE_SSLCERT"`
+	SSLKey           string        `config:"database.sslkey" env:"DATABASE_SSLKEY"`
+	ApplicationName  string        `config:"database.application_name" env:"DATABASE_APPLICATION_NAME"`
+	StatementTimeout time.Duration `config:"database.statement_timeout" env:"DATABASE_STATEMENT_TIMEOUT"`
+	MaxOpenConns     int           `config:"database.max_open_conns" env:"DATABASE_MAX_OPEN_CONNS"`
+	MaxIdleConns     int           `config:"database.max_idle_conns" env:"DATABASE_MAX_IDLE_CONNS"`
+	ConnMaxLifetime  time.Duration `config:"database.conn_max_lifetime" env:"DATABASE_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime  time.Duration `config:"database.conn_max_idle_time" env:"DATABASE_CONN_MAX_IDLE_TIME"`
+	// ReplicaDSNs take the read-only queries, comma separated in the var environment and flag.
+	ReplicaDSNs          []string      `config:"database.replica_dsns" env:"DATABASE_REPLICA_DSNS" secret:"true"`
+	ReplicaCheckInterval time.Duration `config:"database.replica_check_interval" env:"DATABASE_REPLICA_CHECK_INTERVAL"`
+	// The first connection is retried with exponential backoff for up to ConnectTimeout.
+	ConnectTimeout    time.Duration `config:"database.connect_timeout" env:"DATABASE_CONNECT_TIMEOUT"`
+	ConnectBackoff    time.Duration `config:"database.connect_backoff" env:"DATABASE_CONNECT_BACKOFF"`
+	ConnectMaxBackoff time.Duration `config:"database.connect_max_backoff" env:"DATABASE_CONNECT_MAX_BACKOFF"`
+}
+
+type LogConfig struct {
+	Level string `config:"log.level" env:"LOG_LEVEL"`
+}
+
+type AuthConfig struct {
+	// Enabled makes the API require an API key or a bearer token, probes and metrics excepted.
+	Enabled bool `config:"auth.enabled" env:"AUTH_ENABLED"`
+	// JWKSFile holds the public keys bearer tokens are signed by, tokens aren't accepted without it.
+	JWKSFile    string `config:"auth.jwks_file" env:"AUTH_JWKS_FILE"`
+	JWTIssuer   string `config:"auth.jwt_issuer" env:"AUTH_JWT_ISSUER"`
+	JWTAudience string `config:"auth.jwt_audience" env:"AUTH_JWT_AUDIENCE"`
+	// SignatureWindow bounds how old, or ahead, the timestamp of a signed request may be, zero turns signed
+	// requests down.
+	SignatureWindow time.Duration `config:"auth.signature_window" env:"AUTH_SIGNATURE_WINDOW"`
+}
+
+type TracingConfig struct {
+	Exporter string `config:"tracing.exporter" env:"TRACING_EXPORTER"`
+}
+
+type FraudConfig struct {
+	RulesFile    string `config:"fraud.rules_file" env:"FRAUD_RULES_FILE"`
+	CounterStore string `config:"fraud.counter_store" env:"FRAUD_COUNTER_STORE"`
+}
+
+type RateLimitConfig struct {
+	Store string `config:"rate_limit.store" env:"RATE_LIMIT_STORE"`
+	// Rules are written as "<method> <route> <client|account> <requests>/<period> [burst]", none leaves the API
+	// unlimited.
+	Rules []string `config:"rate_limit.rules" env:"RATE_LIMIT_RULES"`
+}
+
+type EventDateConfig struct {
+	MaxBackdate time.Duration `config:"event_date.max_backdate" env:"EVENT_DATE_MAX_BACKDATE"`
+	MaxFuture   time.Duration `config:"event_date.max_future" env:"EVENT_DATE_MAX_FUTURE"`
+}
+
+type BatchConfig struct {
+	MaxItems int `config:"batch.max_items" env:"BATCH_MAX_ITEMS"`
+}
+
+type SchedulerConfig struct {
+	Interval time.Duration `config:"scheduler.interval" env:"SCHEDULER_INTERVAL"`
+}
+
+var (
+	LogLevels        = []string{"debug", "info", "warn", "error"}
+	TracingExporters = []string{"none", "stdout", "otlp"}
+	CounterStores    = []string{"postgres", "memory"}
+	RateLimitStores  = []string{"memory"}
+	SSLModes         = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+)
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Port:                 5432,
+			SSLMode:              "disable",
+			ConnectTimeout:       30 * time.Second,
+			ConnectBackoff:       500 * time.Millisecond,
+			ConnectMaxBackoff:    5 * time.Second,
+			ReplicaCheckInterval: 5 * time.Second,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Auth: AuthConfig{
+			Enabled:         true,
+			SignatureWindow: 5 * time.Minute,
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+		Fraud: FraudConfig{
+			CounterStore: "postgres",
+		},
+		RateLimit: RateLimitConfig{
+			Store: "memory",
+			Rules: []string{
+				"POST /api/v1/accounts/transaction client 600/1m",
+				"POST /api/v1/accounts/transaction account 60/1m",
+			},
+		},
+		EventDate: EventDateConfig{
+			MaxBackdate: 7 * 24 * time.Hour,
+			MaxFuture:   5 * time.Minute,
+		},
+		Batch: BatchConfig{
+			MaxItems: 500,
+		},
+		Scheduler: SchedulerConfig{
+			Interval: time.Minute,
+		},
+	}
+}
+
+// ValidationError gathers every problem found, so they're all fixed at once instead of one per start.
+type ValidationError struct {
+	Errors []string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration:\n  - %s", strings.Join(err.Errors, "\n  - "))
+}
+
+func (err *ValidationError) add(format string, args ...interface{}) {
+	err.Errors = append(err.Errors, fmt.Sprintf(format, args...))
+}
+
+func oneOf(value string, values []string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c Config) validate(verr *ValidationError) {
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		verr.add("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+
+	durations := []struct {
+		Key   string
+		Value time.Duration
+	}{
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+		{"event_date.max_backdate", c.EventDate.MaxBackdate},
+		{"event_date.max_future", c.EventDate.MaxFuture},
+		{"scheduler.interval", c.Scheduler.Interval},
+		{"database.statement_timeout", c.Database.StatementTimeout},
+		{"database.conn_max_lifetime", c.Database.ConnMaxLifetime},
+		{"database.conn_max_idle_time", c.Database.ConnMaxIdleTime},
+		{"database.replica_check_interval", c.Database.ReplicaCheckInterval},
+		{"database.connect_timeout", c.Database.ConnectTimeout},
+		{"database.connect_backoff", c.Database.ConnectBackoff},
+		{"database.connect_max_backoff", c.Database.ConnectMaxBackoff},
+		{"auth.signature_window", c.Auth.SignatureWindow},
+	}
+	for _, d := range durations {
+		if d.Value < 0 {
+			verr.add("%s can't be negative, got %s", d.Key, d.Value)
+		}
+	}
+
+	// The DSN carries the whole connection, so the fields making it up aren't needed.
+	if c.Database.DSN == "" {
+		if c.Database.Host == "" {
+			verr.add("database.host is required")
+		}
+
+		if c.Database.Port < 1 || c.Database.Port > 65535 {
+			verr.add("database.port must be between 1 and 65535, got %d", c.Database.Port)
+		}
+
+		if c.Database.User == "" {
+			verr.add("database.user is required")
+		}
+
+		if c.Database.Name == "" {
+			verr.add("database.name is required")
+		}
+
+		if !oneOf(c.Database.SSLMode, SSLModes) {
+			verr.add("database.sslmode must be one of %s, got %q", strings.Join(SSLModes, ", "), c.Database.SSLMode)
+		}
+
+		if (c.Database.SSLCert == "") != (c.Database.SSLKey == "") {
+			verr.add("database.sslcert and database.sslkey must be set together")
+		}
+	}
+
+	if c.Database.ConnectMaxBackoff < c.Database.ConnectBackoff {
+		verr.add("database.connect_max_backoff can't be less than database.connect_backoff, got %s and %s", c.Database.ConnectMaxBackoff, c.Database.ConnectBackoff)
+	}
+
+	if c.Database.MaxOpenConns < 0 {
+		verr.add("database.max_open_conns can't be negative, got %d", c.Database.MaxOpenConns)
+	}
+
+	if c.Database.MaxIdleConns < 0 {
+		verr.add("database.max_idle_conns can't be negative, got %d", c.Database.MaxIdleConns)
+	}
+
+	if !oneOf(c.Log.Level, LogLevels) {
+		verr.add("log.level must be one of %s, got %q", strings.Join(LogLevels, ", "), c.Log.Level)
+	}
+
+	if (c.Auth.JWTIssuer != "" || c.Auth.JWTAudience != "") && c.Auth.JWKSFile == "" {
+		verr.add("auth.jwt_issuer and auth.jwt_audience need auth.jwks_file")
+	}
+
+	if !oneOf(c.Tracing.Exporter, TracingExporters) {
+		verr.add("tracing.exporter must be one of %s, got %q", strings.Join(TracingExporters, ", "), c.Tracing.Exporter)
+	}
+
+	if !oneOf(c.Fraud.CounterStore, CounterStores) {
+		verr.add("fraud.counter_store must be one of %s, got %q", strings.Join(CounterStores, ", "), c.Fraud.CounterStore)
+	}
+
+	if !oneOf(c.RateLimit.Store, RateLimitStores) {
+		verr.add("rate_limit.store must be one of %s, got %q", strings.Join(RateLimitStores, ", "), c.RateLimit.Store)
+	}
+
+	if c.Batch.MaxItems < 1 {
+		verr.add("batch.max_items must be positive, got %d", c.Batch.MaxItems)
+	}
+}
+
+// Validate checks every field, the error is a *ValidationError with all of the problems.
+func (c Config) Validate() error {
+	verr := &ValidationError{}
+	c.validate(verr)
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+var required = map[string]string{
+	"DATABASE_HOST": "localhost",
+	"DATABASE_USER": "postgres",
+	"DATABASE_NAME": "api",
+}
+
+func with(values map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for k, v := range required {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	suite := []struct {
+		Name     string
+		Args     []string
+		Env      map[string]string
+		Port     int
+		Write    time.Duration
+		LogLevel string
+	}{
+		{"defaults", nil, required, 8080, time.Minute, "info"},
+		{"yaml file", []string{"-config", "testdata/config.yaml"}, nil, 9090, 2 * time.Minute, "warn"},
+		{"toml file from CONFIG_FILE", nil, map[string]string{"CONFIG_FILE": "testdata/config.toml"}, 9090, 2 * time.Minute, "warn"},
+		{"env over file", []string{"-config", "testdata/config.yaml"}, map[string]string{"PORT": "7070", "HTTP_WRITE_TIMEOUT": "30s"}, 7070, 30 * time.Second, "warn"},
+		{"flags over env", []string{"-config", "testdata/config.yaml", "-server-port", "6060", "-log-level", "error"}, map[string]string{"PORT": "7070"}, 6060, 2 * time.Minute, "error"},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			cfg, _, err := Load(s.Args, env(s.Env))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got, expected := cfg.Server.Port, s.Port; got != expected {
+				t.Errorf("unexpected server port, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := cfg.Server.WriteTimeout, s.Write; got != expected {
+				t.Errorf("unexpected write timeout, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := cfg.Log.Level, s.LogLevel; got != expected {
+				t.Errorf("unexpected log level, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	_, _, err := Load([]string{"-database-port", "none"}, env(map[string]string{
+		"DATABASE_HOST":     "localhost",
+		"HTTP_IDLE_TIMEOUT": "forever",
+		"LOG_LEVEL":         "verbose",
+		"BATCH_MAX_ITEMS":   "0",
+	}))
+
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Errorf("unexpected error, got: %v, expected a validation error", err)
+		return
+	}
+
+	expected := []string{
+		"server.idle_timeout must be a duration such as 30s or 5m, got \"forever\" (from HTTP_IDLE_TIMEOUT)",
+		"database.port must be an integer, got \"none\" (from -database-port)",
+		"database.user is required",
+		"database.name is required",
+		"log.level must be one of debug, info, warn, error, got \"verbose\"",
+		"batch.max_items must be positive, got 0",
+	}
+	if got, expected := strings.Join(verr.Errors, "\n"), strings.Join(expected, "\n"); got != expected {
+		t.Errorf("unexpected errors, got:\n%v\nexpected:\n%v", got, expected)
+		return
+	}
+}
+
+func TestValidateDatabase(t *testing.T) {
+	suite := []struct {
+		Name     string
+		Database DatabaseConfig
+		Errors   []string
+	}{
+		{
+			Name:     "Connection fields",
+			Database: DatabaseConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "bank", SSLMode: "verify-full"},
+		},
+		{
+			Name:     "DSN without connection fields",
+			Database: DatabaseConfig{DSN: "postgres://postgres@localhost/bank"},
+		},
+		{
+			Name:     "Unknown sslmode and a cert without key",
+			Database: DatabaseConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "bank", SSLMode: "on", SSLCert: "client.crt"},
+			Errors: []string{
+				"database.sslmode must be one of disable, allow, prefer, require, verify-ca, verify-full, got \"on\"",
+				"database.sslcert and database.sslkey must be set together",
+			},
+		},
+		{
+			Name:     "Negative pool settings",
+			Database: DatabaseConfig{DSN: "postgres://postgres@localhost/bank", MaxOpenConns: -1, ConnMaxLifetime: -time.Second},
+			Errors: []string{
+				"database.conn_max_lifetime can't be negative, got -1s",
+				"database.max_open_conns can't be negative, got -1",
+			},
+		},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Database = s.Database
+
+			verr := &ValidationError{}
+			cfg.validate(verr)
+			if got, expected := strings.Join(verr.Errors, "\n"), strings.Join(s.Errors, "\n"); got != expected {
+				t.Errorf("unexpected errors, got:\n%v\nexpected:\n%v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestLoadReplicaDSNs(t *testing.T) {
+	expected := "postgres://bank@replica-1/bank,postgres://bank@replica-2/bank"
+
+	suite := []struct {
+		Name string
+		Args []string
+		Env  map[string]string
+	}{
+		{"yaml list", []string{"-config", "testdata/config.yaml"}, nil},
+		{"toml list", []string{"-config", "testdata/config.toml"}, nil},
+		{"comma separated var environment", nil, with(map[string]string{"DATABASE_REPLICA_DSNS": "postgres://bank@replica-1/bank, postgres://bank@replica-2/bank,"})},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			cfg, _, err := Load(s.Args, env(s.Env))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got := strings.Join(cfg.Database.ReplicaDSNs, ","); got != expected {
+				t.Errorf("unexpected replica dsns, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestLoadEmptyRateLimitRules(t *testing.T) {
+	suite := []struct {
+		Name     string
+		Env      map[string]string
+		Expected int
+	}{
+		{"unset keeps the defaults", with(nil), len(Default().RateLimit.Rules)},
+		{"empty clears the defaults", with(map[string]string{"RATE_LIMIT_RULES": ""}), 0},
+	}
+
+	for _, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			cfg, _, err := Load(nil, env(s.Env))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if got, expected := len(cfg.RateLimit.Rules), s.Expected; got != expected {
+				t.Errorf("unexpected rate limit rules, got: %v, expected: %v", got, expected)
+				return
+			}
+		})
+	}
+}
+
+func TestLoadSecretFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("Pa$$w0rd\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := Load(nil, env(with(map[string]string{"DATABASE_PASSWORD_FILE": path})))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, expected := cfg.Database.Password, "Pa$$w0rd"; got != expected {
+		t.Errorf("unexpected password, got: %v, expected: %v", got, expected)
+		return
+	}
+
+	if _, _, err := Load(nil, env(with(map[string]string{"DATABASE_PASSWORD": "pwd", "DATABASE_PASSWORD_FILE": path}))); err == nil {
+		t.Error("expected error for both DATABASE_PASSWORD and DATABASE_PASSWORD_FILE")
+		return
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg, flags, err := Load([]string{"-print-config"}, env(with(map[string]string{"DATABASE_PASSWORD": "Pa$$w0rd"})))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !flags.PrintConfig {
+		t.Error("expected print config flag")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := Print(&buf, cfg); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if strings.Contains(buf.String(), "Pa$$w0rd") {
+		t.Errorf("unexpected password in printed config:\n%s", buf.String())
+		return
+	}
+
+	for _, line := range []string{"database:\n  host: localhost\n", "  password: REDACTED\n", "  write_timeout: 1m0s\n"} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected %q in printed config:\n%s", line, buf.String())
+			return
+		}
+	}
+}
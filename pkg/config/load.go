@@ -0,0 +1,264 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Flags aren't configuration but change where it's read from and what the program does with it.
+type Flags struct {
+	File        string
+	PrintConfig bool
+}
+
+type field struct {
+	Key    string
+	Env    string
+	Flag   string
+	Secret bool
+	Value  reflect.Value
+}
+
+func (f field) set(raw string) error {
+	switch f.Value.Interface().(type) {
+	case string:
+		f.Value.SetString(raw)
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%s must be a duration such as 30s or 5m, got %q", f.Key, raw)
+		}
+		f.Value.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer, got %q", f.Key, raw)
+		}
+		f.Value.SetInt(int64(n))
+	case []string:
+		values := make([]string, 0)
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		f.Value.Set(reflect.ValueOf(values))
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean, got %q", f.Key, raw)
+		}
+		f.Value.SetBool(b)
+	default:
+		return fmt.Errorf("%s has an unsupported type %s", f.Key, f.Value.Type())
+	}
+
+	return nil
+}
+
+// String is the value as it's set, lists are comma separated.
+func (f field) String() string {
+	if values, ok := f.Value.Interface().([]string); ok {
+		return strings.Join(values, ",")
+	}
+
+	return fmt.Sprint(f.Value.Interface())
+}
+
+// fields lists the leaves of c in declaration order, their values point into c.
+func fields(c *Config) []field {
+	var fs []field
+
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			key, ok := sf.Tag.Lookup("config")
+			if !ok {
+				walk(v.Field(i))
+				continue
+			}
+
+			fs = append(fs, field{
+				Key:    key,
+				Env:    sf.Tag.Get("env"),
+				Flag:   strings.NewReplacer(".", "-", "_", "-").Replace(key),
+				Secret: sf.Tag.Get("secret") == "true",
+				Value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem())
+
+	return fs
+}
+
+// flatten turns the sections of a file into the keys of the fields, such as database.host.
+func flatten(doc map[string]interface{}) (map[string]string, error) {
+	values := make(map[string]string)
+	for section, value := range doc {
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be a section", section)
+		}
+
+		for name, value := range entries {
+			if list, ok := value.([]interface{}); ok {
+				items := make([]string, 0, len(list))
+				for _, item := range list {
+					items = append(items, fmt.Sprint(item))
+				}
+				values[section+"."+name] = strings.Join(items, ",")
+				continue
+			}
+
+			values[section+"."+name] = fmt.Sprint(value)
+		}
+	}
+
+	return values, nil
+}
+
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]interface{})
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unknown configuration file format %q, use .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	return flatten(doc)
+}
+
+func readSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Load resolves the configuration from, in increasing precedence, the defaults, the file given by -config or
+// CONFIG_FILE, the var environments, an empty one being left out unless it's a list, and the flags in args. Every problem found along the way, validation
+// included, is reported at once by a *ValidationError.
+func Load(args []string, lookupEnv func(string) (string, bool)) (Config, Flags, error) {
+	c, flags := Default(), Flags{}
+	fs := fields(&c)
+
+	flagset := flag.NewFlagSet("config", flag.ContinueOnError)
+	flagset.StringVar(&flags.File, "config", "", "YAML or TOML configuration file, defaults to CONFIG_FILE")
+	flagset.BoolVar(&flags.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	flagValues := make(map[string]*string, len(fs))
+	for _, f := range fs {
+		usage := fmt.Sprintf("%s, var environment %s", f.Key, f.Env)
+		if f.Secret {
+			usage = fmt.Sprintf("%s, var environment %s or %s_FILE", f.Key, f.Env, f.Env)
+		}
+		flagValues[f.Key] = flagset.String(f.Flag, f.String(), usage)
+	}
+	if err := flagset.Parse(args); err != nil {
+		return c, flags, err
+	}
+
+	verr := &ValidationError{}
+
+	if flags.File == "" {
+		flags.File, _ = lookupEnv("CONFIG_FILE")
+	}
+
+	if flags.File != "" {
+		values, err := readFile(flags.File)
+		if err != nil {
+			return c, flags, err
+		}
+
+		known := make(map[string]bool, len(fs))
+		for _, f := range fs {
+			known[f.Key] = true
+			if raw, ok := values[f.Key]; ok {
+				if err := f.set(raw); err != nil {
+					verr.add("%s", err)
+				}
+			}
+		}
+
+		for key := range values {
+			if !known[key] {
+				verr.add("unknown key %s in %s", key, flags.File)
+			}
+		}
+	}
+
+	for _, f := range fs {
+		// An empty var environment is left out, but for a list, where it's the way to clear the default.
+		raw, ok := lookupEnv(f.Env)
+		_, list := f.Value.Interface().([]string)
+		ok = ok && (raw != "" || list)
+
+		if f.Secret {
+			if path, has := lookupEnv(f.Env + "_FILE"); has && path != "" {
+				if ok && raw != "" {
+					verr.add("%s and %s_FILE can't be both set", f.Env, f.Env)
+					continue
+				}
+
+				secret, err := readSecret(path)
+				if err != nil {
+					verr.add("%s_FILE: %s", f.Env, err)
+					continue
+				}
+				raw, ok = secret, true
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := f.set(raw); err != nil {
+			verr.add("%s (from %s)", err, f.Env)
+		}
+	}
+
+	visited := make(map[string]bool)
+	flagset.Visit(func(fl *flag.Flag) {
+		visited[fl.Name] = true
+	})
+	for _, f := range fs {
+		if !visited[f.Flag] {
+			continue
+		}
+
+		if err := f.set(*flagValues[f.Key]); err != nil {
+			verr.add("%s (from -%s)", err, f.Flag)
+		}
+	}
+
+	c.validate(verr)
+	if len(verr.Errors) > 0 {
+		return c, flags, verr
+	}
+
+	return c, flags, nil
+}
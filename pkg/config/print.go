@@ -0,0 +1,40 @@
+package config
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const Redacted = "REDACTED"
+
+// Print writes c in the same shape as a YAML configuration file, secrets which are set are redacted.
+func Print(w io.Writer, c Config) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+
+	var section *yaml.Node
+	current := ""
+	for _, f := range fields(&c) {
+		name, key, _ := strings.Cut(f.Key, ".")
+		if name != current {
+			section = &yaml.Node{Kind: yaml.MappingNode}
+			doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, section)
+			current = name
+		}
+
+		value := f.String()
+		if f.Secret && value != "" {
+			value = Redacted
+		}
+		section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
@@ -0,0 +1,91 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	KeyIDHeader     = "X-Signature-Key-ID"
+	TimestampHeader = "X-Signature-Timestamp"
+	NonceHeader     = "X-Signature-Nonce"
+	SignatureHeader = "X-Signature"
+)
+
+// Canonical is the form of a request which gets signed, a line for each of the method, the path with its query,
+// the unix timestamp, the nonce, the hex SHA-256 of the body and the key ID.
+func Canonical(method, uri string, timestamp int64, nonce string, body []byte, keyID string) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(strings.Join([]string{
+		strings.ToUpper(method),
+		uri,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(sum[:]),
+		keyID,
+	}, "\n"))
+}
+
+// MAC is the hex HMAC-SHA256 of payload with secret.
+func MAC(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign sets the signature headers of r as signed at the given time, the body is read and put back.
+func Sign(r *http.Request, keyID, secret string, at time.Time) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return err
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(b)
+
+	timestamp := at.Unix()
+	r.Header.Set(KeyIDHeader, keyID)
+	r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(NonceHeader, nonce)
+	r.Header.Set(SignatureHeader, MAC(secret, Canonical(r.Method, r.URL.RequestURI(), timestamp, nonce, body, keyID)))
+
+	return nil
+}
+
+// Transport signs every request it sends, with a nonce of its own, before handing it to Base or
+// http.DefaultTransport.
+type Transport struct {
+	KeyID  string
+	Secret string
+	Base   http.RoundTripper
+}
+
+func (t Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	if err := Sign(r, t.KeyID, t.Secret, time.Now()); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,104 @@
+package signing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// verify checks r as the API does, against the canonical form of what it received.
+func verify(r *http.Request, secret string) (bool, string) {
+	body, _ := io.ReadAll(r.Body)
+	timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	keyID, nonce := r.Header.Get(KeyIDHeader), r.Header.Get(NonceHeader)
+
+	return MAC(secret, Canonical(r.Method, r.URL.RequestURI(), timestamp, nonce, body, keyID)) == r.Header.Get(SignatureHeader), string(body)
+}
+
+func TestTransport(t *testing.T) {
+	var (
+		valid  bool
+		body   string
+		nonces = make(map[string]bool)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		valid, body = verify(r, "5ec2e7")
+		nonces[r.Header.Get(NonceHeader)] = true
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport{KeyID: "a1b2", Secret: "5ec2e7"}}
+
+	suite := []struct {
+		Name   string
+		Method string
+		Path   string
+		Body   string
+	}{
+		{"Request without body", http.MethodGet, "/api/v1/accounts/1", ""},
+		{"Request with query", http.MethodGet, "/api/v1/accounts/1/transactions?from=2023-03-01T00:00:00Z&limit=10", ""},
+		{"Request with body", http.MethodPost, "/api/v1/accounts/transaction", `{"account_id":1,"operation_type_id":4,"amount":10}`},
+	}
+
+	for i, s := range suite {
+		t.Run(s.Name, func(t *testing.T) {
+			var reqBody io.Reader
+			if s.Body != "" {
+				reqBody = strings.NewReader(s.Body)
+			}
+
+			req, err := http.NewRequest(s.Method, server.URL+s.Path, reqBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if !valid {
+				t.Errorf("unexpected signature, it doesn't match the request received")
+				return
+			}
+
+			if got, expected := body, s.Body; got != expected {
+				t.Errorf("unexpected body received, got: %v, expected: %v", got, expected)
+				return
+			}
+
+			if got, expected := len(nonces), i+1; got != expected {
+				t.Errorf("unexpected nonces, got: %v different ones, expected: %v", got, expected)
+				return
+			}
+
+			if got := req.Header.Get(SignatureHeader); got != "" {
+				t.Errorf("unexpected signature set on the request of the caller, got: %v", got)
+				return
+			}
+		})
+	}
+}
+
+func TestTransportSignedByAnotherSecret(t *testing.T) {
+	var valid bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		valid, _ = verify(r, "5ec2e7")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport{KeyID: "a1b2", Secret: "forged"}}
+	resp, err := client.Post(server.URL+"/api/v1/accounts/transaction", "application/json", strings.NewReader(`{"amount":10}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if valid {
+		t.Errorf("unexpected signature, it matches a secret it wasn't signed by")
+	}
+}